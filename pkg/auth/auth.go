@@ -4,7 +4,9 @@ package auth
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
+	"sort"
 	"strings"
 
 	"google.golang.org/grpc"
@@ -14,9 +16,72 @@ import (
 	"google.golang.org/grpc/status"
 )
 
-// UnaryServerInterceptor checks for a valid Bearer token in metadata.
+// DefaultCaller is the identity of the single shared AUTH_TOKEN.
+const DefaultCaller = "default"
+
+type callerKey struct{}
+
+// CallerFromContext returns the identity the request authenticated as.
+func CallerFromContext(ctx context.Context) (string, bool) {
+	c, ok := ctx.Value(callerKey{}).(string)
+	return c, ok
+}
+
+// WithCaller attaches an authenticated caller identity to ctx.
+func WithCaller(ctx context.Context, caller string) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// tokensFromEnv maps each accepted token to the caller it identifies.
+// AUTH_TOKENS holds "name=token" pairs separated by commas; the legacy
+// AUTH_TOKEN is accepted as DefaultCaller.
+func tokensFromEnv() map[string]string {
+	tokens := map[string]string{}
+	if t := os.Getenv("AUTH_TOKEN"); t != "" {
+		tokens[t] = DefaultCaller
+	}
+	for _, pair := range strings.Split(os.Getenv("AUTH_TOKENS"), ",") {
+		name, token, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if ok && name != "" && token != "" {
+			tokens[token] = name
+		}
+	}
+	return tokens
+}
+
+// Callers lists, sorted, the caller names the configured tokens identify.
+func Callers() []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, name := range tokensFromEnv() {
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsAdmin reports whether the caller in ctx holds the admin role. AUTH_ADMINS
+// lists the admin caller names, separated by commas.
+func IsAdmin(ctx context.Context) bool {
+	caller, ok := CallerFromContext(ctx)
+	if !ok || caller == "" {
+		return false
+	}
+	for _, name := range strings.Split(os.Getenv("AUTH_ADMINS"), ",") {
+		if strings.TrimSpace(name) == caller {
+			return true
+		}
+	}
+	return false
+}
+
+// UnaryServerInterceptor checks for a valid Bearer token in metadata and
+// records the caller it belongs to in the context.
 func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
-	expected := os.Getenv("AUTH_TOKEN")
+	tokens := tokensFromEnv()
 	return func(
 		ctx context.Context,
 		req interface{},
@@ -32,13 +97,54 @@ func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
 			return nil, status.Error(codes.Unauthenticated, "no auth token")
 		}
 		token := strings.TrimPrefix(vals[0], "Bearer ")
-		if token != expected {
+		caller, ok := tokens[token]
+		if !ok {
 			return nil, status.Error(codes.Unauthenticated, "invalid auth token")
 		}
-		return handler(ctx, req)
+		return handler(WithCaller(ctx, caller), req)
 	}
 }
 
+// HTTPMiddleware applies the same Bearer-token check to HTTP handlers.
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return httpMiddleware(next, false)
+}
+
+// FeedMiddleware is HTTPMiddleware that also takes the token from a "token"
+// query parameter, since calendar apps subscribe by URL and cannot send
+// headers. Use it only for read-only feeds.
+func FeedMiddleware(next http.Handler) http.Handler {
+	return httpMiddleware(next, true)
+}
+
+// AdminMiddleware is HTTPMiddleware that also requires the caller to be an
+// admin, refusing anyone else with 403 Forbidden.
+func AdminMiddleware(next http.Handler) http.Handler {
+	return httpMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !IsAdmin(r.Context()) {
+			http.Error(w, "admin role required", http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	}), false)
+}
+
+func httpMiddleware(next http.Handler, allowQuery bool) http.Handler {
+	tokens := tokensFromEnv()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" && allowQuery {
+			token = r.URL.Query().Get("token")
+		}
+		caller, ok := tokens[token]
+		if !ok {
+			http.Error(w, "invalid auth token", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(WithCaller(r.Context(), caller)))
+	})
+}
+
 // StaticTokenCreds implements PerRPCCredentials by always sending the same token.
 type StaticTokenCreds struct {
 	Token string
@@ -0,0 +1,121 @@
+// pkg/auth/auth_test.go
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"hearx/pkg/auth"
+)
+
+var _ = Describe("IsAdmin", func() {
+	BeforeEach(func() {
+		os.Setenv("AUTH_ADMINS", "root, carol")
+	})
+
+	AfterEach(func() {
+		os.Unsetenv("AUTH_ADMINS")
+	})
+
+	It("should accept a caller listed in AUTH_ADMINS, ignoring spaces", func() {
+		Expect(auth.IsAdmin(auth.WithCaller(context.Background(), "root"))).To(BeTrue())
+		Expect(auth.IsAdmin(auth.WithCaller(context.Background(), "carol"))).To(BeTrue())
+	})
+
+	It("should refuse any other caller", func() {
+		Expect(auth.IsAdmin(auth.WithCaller(context.Background(), "alice"))).To(BeFalse())
+		Expect(auth.IsAdmin(auth.WithCaller(context.Background(), "roo"))).To(BeFalse())
+	})
+
+	It("should refuse a context without a caller", func() {
+		Expect(auth.IsAdmin(context.Background())).To(BeFalse())
+	})
+
+	It("should refuse an empty caller even with a stray comma in AUTH_ADMINS", func() {
+		os.Setenv("AUTH_ADMINS", "root,")
+		Expect(auth.IsAdmin(auth.WithCaller(context.Background(), ""))).To(BeFalse())
+	})
+
+	It("should refuse everyone when AUTH_ADMINS is unset", func() {
+		os.Unsetenv("AUTH_ADMINS")
+		Expect(auth.IsAdmin(auth.WithCaller(context.Background(), "root"))).To(BeFalse())
+	})
+})
+
+var _ = Describe("HTTP middleware", func() {
+	var (
+		caller  string
+		handler http.Handler
+	)
+
+	BeforeEach(func() {
+		os.Setenv("AUTH_TOKENS", "alice=secret-a,bob=secret-b")
+		caller = ""
+		handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			caller, _ = auth.CallerFromContext(r.Context())
+		})
+	})
+
+	AfterEach(func() {
+		os.Unsetenv("AUTH_TOKENS")
+	})
+
+	serve := func(mw func(http.Handler) http.Handler, target, header string) int {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rec := httptest.NewRecorder()
+		mw(handler).ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	It("should let a feed authenticate with the token query parameter", func() {
+		Expect(serve(auth.FeedMiddleware, "/feed.ics?token=secret-a", "")).To(Equal(http.StatusOK))
+		Expect(caller).To(Equal("alice"))
+	})
+
+	It("should prefer the Authorization header over the query parameter", func() {
+		Expect(serve(auth.FeedMiddleware, "/feed.ics?token=secret-a", "Bearer secret-b")).To(Equal(http.StatusOK))
+		Expect(caller).To(Equal("bob"))
+
+		Expect(serve(auth.FeedMiddleware, "/feed.ics?token=secret-a", "Bearer wrong")).To(Equal(http.StatusUnauthorized))
+	})
+
+	It("should refuse a feed with an unknown or missing token", func() {
+		Expect(serve(auth.FeedMiddleware, "/feed.ics?token=wrong", "")).To(Equal(http.StatusUnauthorized))
+		Expect(serve(auth.FeedMiddleware, "/feed.ics", "")).To(Equal(http.StatusUnauthorized))
+		Expect(caller).To(BeEmpty())
+	})
+
+	It("should ignore the query parameter outside feeds", func() {
+		Expect(serve(auth.HTTPMiddleware, "/tasks?token=secret-a", "")).To(Equal(http.StatusUnauthorized))
+		Expect(serve(auth.HTTPMiddleware, "/tasks", "Bearer secret-a")).To(Equal(http.StatusOK))
+		Expect(caller).To(Equal("alice"))
+	})
+
+	Describe("AdminMiddleware", func() {
+		BeforeEach(func() { os.Setenv("AUTH_ADMINS", "alice") })
+		AfterEach(func() { os.Unsetenv("AUTH_ADMINS") })
+
+		It("should let an admin through", func() {
+			Expect(serve(auth.AdminMiddleware, "/admin/log/level", "Bearer secret-a")).To(Equal(http.StatusOK))
+			Expect(caller).To(Equal("alice"))
+		})
+
+		It("should forbid a valid token of another caller", func() {
+			Expect(serve(auth.AdminMiddleware, "/admin/log/level", "Bearer secret-b")).To(Equal(http.StatusForbidden))
+			Expect(caller).To(BeEmpty())
+		})
+
+		It("should still refuse an unknown token, even as a query parameter", func() {
+			Expect(serve(auth.AdminMiddleware, "/admin/log/level", "Bearer wrong")).To(Equal(http.StatusUnauthorized))
+			Expect(serve(auth.AdminMiddleware, "/admin/log/level?token=secret-a", "")).To(Equal(http.StatusUnauthorized))
+		})
+	})
+})
@@ -0,0 +1,228 @@
+// pkg/cli/admin.go
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"hearx/pkg/model"
+)
+
+// adminCmd groups the AdminService subcommands. They take the same
+// connection flags as client, and the caller must be listed in AUTH_ADMINS.
+func adminCmd() *cobra.Command {
+	cmd := adminGroup()
+	cmd.PersistentPreRunE = resolveTarget
+	connectionFlags(cmd)
+	cmd.PersistentFlags().StringVarP(&Output, "output", "o", OutputTable, "Output format: table|json|yaml")
+	return cmd
+}
+
+// adminGroup is the admin command tree without connection flags, as the
+// shell runs it over its own connection.
+func adminGroup() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "admin",
+		Short: "Administer the server (audit|archive); needs a caller listed in AUTH_ADMINS",
+	}
+	cmd.AddCommand(auditCmd(), archiveCmd())
+	return cmd
+}
+
+// auditCmd reads the audit trail. The filter flags apply to both list and
+// export.
+func auditCmd() *cobra.Command {
+	var (
+		filter       model.AuditFilter
+		since, until string
+	)
+	// parse fills in the times of filter from their flags
+	parse := func() (err error) {
+		if filter.Since, err = parseTime("--since", since); err != nil {
+			return err
+		}
+		filter.Until, err = parseTime("--until", until)
+		return err
+	}
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Show who changed which task and when (list|export)",
+		Example: "  todo admin audit list --task 5\n" +
+			"  todo admin audit list --actor alice --since 2026-10-01\n" +
+			"  todo admin audit export --since 2026-10-01 --file audit.jsonl",
+	}
+	cmd.PersistentFlags().Int64Var(&filter.TaskID, "task", 0, "Only changes to this task ID")
+	cmd.PersistentFlags().StringVar(&filter.Actor, "actor", "", "Only changes made by this caller")
+	cmd.PersistentFlags().StringVar(&filter.Action, "action", "", "Only changes of this kind, e.g. complete or assign")
+	cmd.PersistentFlags().StringVar(&since, "since", "", "Only changes at or after this time: YYYY-MM-DD, 'YYYY-MM-DD HH:MM' or RFC 3339")
+	cmd.PersistentFlags().StringVar(&until, "until", "", "Only changes before this time")
+	cmd.AddCommand(auditListCmd(&filter, parse), auditExportCmd(&filter, parse))
+	return cmd
+}
+
+func auditListCmd(filter *model.AuditFilter, parse func() error) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List recorded changes, oldest first",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkOutput(Output, "audit events"); err != nil {
+				return err
+			}
+			if err := parse(); err != nil {
+				return err
+			}
+			c, release, err := clientFor(cmd)
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), Timeout)
+			defer cancel()
+
+			var events []model.AuditEvent
+			for e, err := range c.ListAuditEvents(ctx, *filter) {
+				if err != nil {
+					return err
+				}
+				events = append(events, e)
+			}
+			return printAuditEvents(cmd.OutOrStdout(), events)
+		},
+	}
+}
+
+func auditExportCmd(filter *model.AuditFilter, parse func() error) *cobra.Command {
+	var file string
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write recorded changes as JSON lines, one event per line",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := parse(); err != nil {
+				return err
+			}
+			c, release, err := clientFor(cmd)
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), Timeout)
+			defer cancel()
+
+			w := cmd.OutOrStdout()
+			var f *os.File
+			if file != "-" {
+				if f, err = os.Create(file); err != nil {
+					return err
+				}
+				defer f.Close()
+				w = f
+			}
+			bw := bufio.NewWriter(w)
+			n, err := c.ExportAuditEvents(ctx, bw, *filter)
+			if err != nil {
+				return err
+			}
+			if err := bw.Flush(); err != nil {
+				return err
+			}
+			if f == nil {
+				return nil
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d events to %s\n", n, file)
+			return f.Close()
+		},
+	}
+	cmd.Flags().StringVarP(&file, "file", "f", "-", "Write to this file instead of stdout")
+	return cmd
+}
+
+// archiveCmd archives completed tasks now rather than waiting for the
+// server's scheduled run.
+func archiveCmd() *cobra.Command {
+	var olderThan string
+	cmd := &cobra.Command{
+		Use:   "archive",
+		Short: "Archive the tasks completed longer ago than --older-than",
+		Long: "Archived tasks are left out of list unless --archived is given. Reopening a task\n" +
+			"unarchives it.",
+		Example: "  todo admin archive --older-than 30d\n" +
+			"  todo admin archive --older-than 36h",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			age, err := parseAge("--older-than", olderThan)
+			if err != nil {
+				return err
+			}
+			c, release, err := clientFor(cmd)
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), Timeout)
+			defer cancel()
+
+			n, err := c.ArchiveTasks(ctx, age)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Archived %d tasks\n", n)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&olderThan, "older-than", "", "How long ago the tasks were completed: days such as 30d, or a duration such as 36h")
+	cmd.MarkFlagRequired("older-than")
+	return cmd
+}
+
+// parseAge reads a positive age given in whole days ("30d") or as a Go
+// duration ("36h").
+func parseAge(flag, s string) (time.Duration, error) {
+	var d time.Duration
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s %q (want e.g. 30d or 36h)", flag, s)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+	} else {
+		var err error
+		if d, err = time.ParseDuration(s); err != nil {
+			return 0, fmt.Errorf("invalid %s %q (want e.g. 30d or 36h)", flag, s)
+		}
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("%s must be positive", flag)
+	}
+	return d, nil
+}
+
+func printAuditEvents(w io.Writer, list []model.AuditEvent) error {
+	if list == nil {
+		list = []model.AuditEvent{}
+	}
+	switch Output {
+	case OutputJSON:
+		return writeJSON(w, list)
+	case OutputYAML:
+		return yaml.NewEncoder(w).Encode(list)
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tTIME\tACTOR\tACTION\tTASK\tDETAIL")
+	for _, e := range list {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%d\t%s\n",
+			e.ID, e.OccurredAt.Local().Format("2006-01-02 15:04:05"), e.Actor, e.Action, e.TaskID, e.Detail)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,115 @@
+// pkg/cli/admin_test.go
+package cli_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"hearx/pkg/model"
+	pb "hearx/proto"
+)
+
+// fakeAdminServer serves a fixed audit trail in one page and remembers the
+// last filter and archive threshold it was asked for.
+type fakeAdminServer struct {
+	pb.UnimplementedAdminServiceServer
+	last      *pb.ListAuditEventsRequest
+	olderThan time.Duration
+}
+
+func (f *fakeAdminServer) ListAuditEvents(_ context.Context, req *pb.ListAuditEventsRequest) (*pb.ListAuditEventsResponse, error) {
+	f.last = req
+	return &pb.ListAuditEventsResponse{Events: []*pb.AuditEvent{
+		{Id: 1, Actor: "alice", Action: "add", TaskId: 5, After: &pb.Task{Id: 5, Title: "T"}},
+		{Id: 2, Actor: "bob", Action: "assign", TaskId: 5, Before: &pb.Task{Id: 5, Title: "T"},
+			After: &pb.Task{Id: 5, Title: "T", Assignee: "bob"}},
+	}}, nil
+}
+
+func (f *fakeAdminServer) ArchiveTasks(_ context.Context, req *pb.ArchiveTasksRequest) (*pb.ArchiveTasksResponse, error) {
+	f.olderThan = req.OlderThan.AsDuration()
+	return &pb.ArchiveTasksResponse{Archived: 2}, nil
+}
+
+var _ = Describe("admin audit", func() {
+	var (
+		env *shellEnv
+		dir string
+	)
+
+	BeforeEach(func() {
+		env = newShellEnv()
+		var err error
+		dir, err = os.MkdirTemp("", "audit")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		env.close()
+		os.RemoveAll(dir)
+	})
+
+	It("should pass the filters and list the events", func() {
+		Expect(env.sh.Exec("admin audit list --task 5 --actor bob --since 2026-10-01")).To(Succeed())
+		Expect(env.admin.last.TaskId).To(Equal(int64(5)))
+		Expect(env.admin.last.Actor).To(Equal("bob"))
+		Expect(env.admin.last.Since).NotTo(BeNil())
+		Expect(env.admin.last.Until).To(BeNil())
+		Expect(env.out.String()).To(HavePrefix("ID"))
+		Expect(env.out.String()).To(ContainSubstring("assign"))
+	})
+
+	It("should export JSON lines to a file", func() {
+		p := filepath.Join(dir, "audit.jsonl")
+		Expect(env.sh.Exec("admin audit export --until 2026-11-01 -f " + p)).To(Succeed())
+		Expect(env.admin.last.Until).NotTo(BeNil())
+
+		b, err := os.ReadFile(p)
+		Expect(err).NotTo(HaveOccurred())
+		lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+		Expect(lines).To(HaveLen(2))
+		var e model.AuditEvent
+		Expect(json.Unmarshal([]byte(lines[1]), &e)).To(Succeed())
+		Expect(e.After.Assignee).To(Equal("bob"))
+	})
+
+	It("should reject a malformed time", func() {
+		Expect(env.sh.Exec("admin audit list --since yesterday")).To(MatchError(ContainSubstring("--since")))
+	})
+})
+
+var _ = Describe("admin archive", func() {
+	var env *shellEnv
+
+	BeforeEach(func() {
+		env = newShellEnv()
+	})
+
+	AfterEach(func() {
+		env.close()
+	})
+
+	It("should accept the threshold in days", func() {
+		Expect(env.sh.Exec("admin archive --older-than 30d")).To(Succeed())
+		Expect(env.admin.olderThan).To(Equal(30 * 24 * time.Hour))
+		Expect(env.out.String()).To(Equal("Archived 2 tasks\n"))
+	})
+
+	It("should accept a Go duration", func() {
+		Expect(env.sh.Exec("admin archive --older-than 36h")).To(Succeed())
+		Expect(env.admin.olderThan).To(Equal(36 * time.Hour))
+	})
+
+	It("should reject a malformed or non-positive threshold", func() {
+		Expect(env.sh.Exec("admin archive --older-than soon")).To(MatchError(ContainSubstring("--older-than")))
+		Expect(env.sh.Exec("admin archive --older-than 0d")).To(MatchError(ContainSubstring("positive")))
+		Expect(env.admin.olderThan).To(BeZero())
+	})
+})
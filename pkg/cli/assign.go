@@ -0,0 +1,110 @@
+// pkg/cli/assign.go
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"hearx/pkg/model"
+)
+
+// assignCmd calls the AssignTask and UnassignTask RPCs
+func assignCmd() *cobra.Command {
+	var (
+		id       int64
+		to       string
+		unassign bool
+	)
+	cmd := &cobra.Command{
+		Use:   "assign",
+		Short: "Assign a task to a user (--to), or leave it unassigned (--clear)",
+		Example: "  todo client assign --id 5 --to alice\n" +
+			"  todo client assign --id 5 --clear\n" +
+			"  todo client get --mine",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := NewTaskPrinter(Output, Template)
+			if err != nil {
+				return err
+			}
+			if to == "" && !unassign {
+				return errors.New("give --to or --clear")
+			}
+			c, release, err := clientFor(cmd)
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), Timeout)
+			defer cancel()
+
+			var task model.Task
+			if unassign {
+				task, err = c.UnassignTask(ctx, id)
+			} else {
+				task, err = c.AssignTask(ctx, id, to)
+			}
+			if err != nil {
+				return err
+			}
+			return printer.PrintOne(cmd.OutOrStdout(), task)
+		},
+	}
+	cmd.Flags().Int64Var(&id, "id", 0, "Task ID (required)")
+	cmd.MarkFlagRequired("id")
+	cmd.Flags().StringVar(&to, "to", "", "Registered user to assign the task to")
+	cmd.Flags().BoolVar(&unassign, "clear", false, "Remove the current assignee")
+	cmd.MarkFlagsMutuallyExclusive("to", "clear")
+	return cmd
+}
+
+// usersCmd calls the ListUsers RPC
+func usersCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "users",
+		Short: "List the users tasks can be assigned to",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkOutput(Output, "users"); err != nil {
+				return err
+			}
+			c, release, err := clientFor(cmd)
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), Timeout)
+			defer cancel()
+
+			list, err := c.ListUsers(ctx)
+			if err != nil {
+				return err
+			}
+			return printUsers(cmd.OutOrStdout(), list)
+		},
+	}
+}
+
+func printUsers(w io.Writer, list []model.User) error {
+	if list == nil {
+		list = []model.User{}
+	}
+	switch Output {
+	case OutputJSON:
+		return writeJSON(w, list)
+	case OutputYAML:
+		return yaml.NewEncoder(w).Encode(list)
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tREGISTERED")
+	for _, u := range list {
+		fmt.Fprintf(tw, "%s\t%s\n", u.Name, u.CreatedAt.Local().Format("2006-01-02"))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,87 @@
+// pkg/cli/assign_test.go
+package cli_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "hearx/proto"
+)
+
+// users are the names the fakeServer accepts as assignees.
+var users = []string{"alice", "bob"}
+
+func (f *fakeServer) AssignTask(_ context.Context, req *pb.AssignTaskRequest) (*pb.AssignTaskResponse, error) {
+	if !slices.Contains(users, req.Assignee) {
+		return nil, status.Errorf(codes.InvalidArgument, "user %q is not registered", req.Assignee)
+	}
+	for _, t := range f.tasks {
+		if t.Id == req.Id {
+			t.Assignee = req.Assignee
+			return &pb.AssignTaskResponse{Task: t}, nil
+		}
+	}
+	return nil, status.Error(codes.NotFound, "task not found")
+}
+
+func (f *fakeServer) UnassignTask(_ context.Context, req *pb.UnassignTaskRequest) (*pb.UnassignTaskResponse, error) {
+	for _, t := range f.tasks {
+		if t.Id == req.Id {
+			t.Assignee = ""
+			return &pb.UnassignTaskResponse{Task: t}, nil
+		}
+	}
+	return nil, status.Error(codes.NotFound, "task not found")
+}
+
+func (f *fakeServer) ListUsers(context.Context, *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+	resp := &pb.ListUsersResponse{}
+	for _, name := range users {
+		resp.Users = append(resp.Users, &pb.User{Name: name})
+	}
+	return resp, nil
+}
+
+var _ = Describe("assign", func() {
+	var env *shellEnv
+
+	BeforeEach(func() { env = newShellEnv() })
+	AfterEach(func() { env.close() })
+
+	It("should assign, list by assignee and unassign", func() {
+		sh, out := env.sh, env.out
+		script := "add --title a\nadd --title b --assignee bob\nassign --id 1 --to alice\n"
+		Expect(sh.RunScript(strings.NewReader(script))).To(Succeed())
+		Expect(env.fake.tasks[0].Assignee).To(Equal("alice"))
+		Expect(env.fake.tasks[1].Assignee).To(Equal("bob"))
+
+		out.Reset()
+		Expect(sh.Exec("get --assignee alice")).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("ASSIGNEE"))
+		Expect(out.String()).To(ContainSubstring("alice"))
+		Expect(out.String()).NotTo(ContainSubstring("bob"))
+
+		Expect(sh.Exec("assign --id 1 --clear")).To(Succeed())
+		Expect(env.fake.tasks[0].Assignee).To(BeEmpty())
+	})
+
+	It("should report an unregistered user", func() {
+		Expect(env.sh.Exec("add --title a")).To(Succeed())
+		Expect(env.sh.Exec("assign --id 1 --to mallory")).To(MatchError(ContainSubstring("not registered")))
+	})
+
+	It("should need --to or --clear, not both", func() {
+		Expect(env.sh.Exec("assign --id 1")).To(MatchError(ContainSubstring("--to or --clear")))
+		Expect(env.sh.Exec("assign --id 1 --to alice --clear")).To(HaveOccurred())
+	})
+
+	It("should complete user names after --to", func() {
+		Expect(env.sh.Complete("assign --id 1 --to a")).To(Equal([]string{"assign --id 1 --to alice"}))
+	})
+})
@@ -3,40 +3,54 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"os"
 	"time"
 
 	"github.com/spf13/cobra"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 
-	"hearx/pkg/auth"
+	"hearx/pkg/client"
+	"hearx/pkg/model"
 	"hearx/pkg/server"
-	pb "hearx/proto"
+	"hearx/pkg/tui"
 )
 
 var (
 	// Server flags
-	FlagGRPCPort  string
-	FlagMySQLHost string
-	FlagMySQLPort string
-	FlagMySQLUser string
-	FlagMySQLPass string
-	FlagMySQLDB   string
+	FlagGRPCPort      string
+	FlagMySQLHost     string
+	FlagMySQLPort     string
+	FlagMySQLUser     string
+	FlagMySQLPass     string
+	FlagMySQLPassFile string
+	FlagMySQLDB       string
 
 	// Client flags
-	ClientHost string
-	ClientPort string
+	ClientHost  string
+	ClientPort  string
+	ClientAddrs []string
 
 	// Auth flags
 	Token string
+
+	// Timeout bounds each client command, dialing included
+	Timeout time.Duration
 )
 
 func Execute() error {
+	return NewRootCmd().Execute()
+}
+
+// NewRootCmd builds the todo command tree. Building it resets the
+// package-level flag variables to their defaults.
+func NewRootCmd() *cobra.Command {
 	root := &cobra.Command{
 		Use:   "todo",
 		Short: "Todo application CLI (server + client)",
+		// errors from the server are not usage mistakes
+		SilenceUsage: true,
 	}
 
 	// server subcommand
@@ -45,7 +59,9 @@ func Execute() error {
 	// client subcommand with its own subcommands
 	root.AddCommand(clientCmd())
 
-	return root.Execute()
+	// admin subcommand, for callers listed in AUTH_ADMINS
+	root.AddCommand(adminCmd())
+	return root
 }
 
 // serverCmd configures and launches your gRPC+Gateway server.
@@ -59,9 +75,18 @@ func serverCmd() *cobra.Command {
 			os.Setenv("MYSQL_HOST", FlagMySQLHost)
 			os.Setenv("MYSQL_PORT", FlagMySQLPort)
 			os.Setenv("MYSQL_USER", FlagMySQLUser)
-			os.Setenv("MYSQL_PASSWORD", FlagMySQLPass)
 			os.Setenv("MYSQL_DATABASE", FlagMySQLDB)
 
+			// only override the password sources that were given explicitly,
+			// so MYSQL_PASSWORD / MYSQL_PASSWORD_FILE from the env still apply
+			if FlagMySQLPass != "" {
+				os.Setenv("MYSQL_PASSWORD", FlagMySQLPass)
+			}
+			if FlagMySQLPassFile != "" {
+				os.Setenv("MYSQL_PASSWORD_FILE", FlagMySQLPassFile)
+			}
+			exportEnvFlags(cmd)
+
 			// now run the Fx-based server (blocks)
 			server.Run()
 			return nil
@@ -75,91 +100,220 @@ func serverCmd() *cobra.Command {
 	cmd.Flags().StringVar(&FlagMySQLHost, "mysql-host", "localhost", "MySQL host")
 	cmd.Flags().StringVar(&FlagMySQLPort, "mysql-port", "3306", "MySQL port")
 	cmd.Flags().StringVar(&FlagMySQLUser, "mysql-user", "user", "MySQL user")
-	cmd.Flags().StringVar(&FlagMySQLPass, "mysql-pass", "password", "MySQL password")
+	cmd.Flags().StringVar(&FlagMySQLPass, "mysql-pass", "", "MySQL password (prefer --mysql-pass-file or MYSQL_PASSWORD)")
+	cmd.Flags().StringVar(&FlagMySQLPassFile, "mysql-pass-file", "", "File containing the MySQL password (e.g. /run/secrets/mysql_password)")
 	cmd.Flags().StringVar(&FlagMySQLDB, "mysql-db", "project_db", "MySQL database name")
 
+	// tuning flags, exported to the env only when set
+	for _, f := range serverEnvFlags {
+		cmd.Flags().String(f.flag, "", f.usage)
+	}
+
 	return cmd
 }
 
+// serverEnvFlags are optional server settings. Unlike the connection flags
+// above they have no defaults of their own: the server falls back to the env
+// var, then to its built-in default.
+var serverEnvFlags = []struct{ flag, env, usage string }{
+	{"http-port", "HTTP_PORT", "HTTP admin listen port (default 8000)"},
+	{"log-level", "LOG_LEVEL", "Log level: debug|info|warn|error (default info)"},
+	{"log-format", "LOG_FORMAT", "Log format: json|console (default json)"},
+	{"log-sampling", "LOG_SAMPLING", "Sample repeated log entries under load (default true)"},
+	{"log-output", "LOG_OUTPUT", "Comma-separated log destinations: stderr, stdout or file paths (default stderr)"},
+	{"grpc-default-timeout", "GRPC_DEFAULT_TIMEOUT", "Deadline for calls that arrive without one (default 10s)"},
+	{"grpc-max-timeout", "GRPC_MAX_TIMEOUT", "Cap on any deadline, at least the default (default 30s)"},
+	{"grpc-method-timeouts", "GRPC_METHOD_TIMEOUTS", "Per-method timeouts, e.g. ListTasks=2s:10s (default:max)"},
+	{"rate-limit-rps", "RATE_LIMIT_RPS", "Requests per second each caller may make per method, 0 to disable (default 10)"},
+	{"rate-limit-burst", "RATE_LIMIT_BURST", "Burst allowed above the rate limit (default 20)"},
+	{"rate-limit-methods", "RATE_LIMIT_METHODS", "Per-method limits, e.g. AddTask=1:5,ListTasks=20:40 (rate:burst)"},
+	{"max-open-tasks-per-owner", "MAX_OPEN_TASKS_PER_OWNER", "Maximum open tasks per caller, 0 for unlimited (default 0)"},
+	{"admins", "AUTH_ADMINS", "Comma-separated caller names allowed to use 'todo admin'"},
+	{"users", "USERS", "Comma-separated user names tasks can be assigned to, besides every AUTH_TOKENS caller"},
+	{"trash-retention", "TRASH_RETENTION", "How long deleted tasks stay restorable before they are purged, 0 to keep them (default 720h)"},
+	{"trash-purge-interval", "TRASH_PURGE_INTERVAL", "How often expired deleted tasks are purged (default 1h)"},
+	{"trash-purge-batch-size", "TRASH_PURGE_BATCH_SIZE", "Maximum tasks purged per statement (default 500)"},
+	{"archive-after", "ARCHIVE_AFTER", "How long a completed task stays unchanged before it is archived, 0 to archive only on demand (default 720h)"},
+	{"archive-interval", "ARCHIVE_INTERVAL", "How often completed tasks are archived (default 1h)"},
+	{"archive-batch-size", "ARCHIVE_BATCH_SIZE", "Maximum tasks archived per statement (default 500)"},
+	{"auto-complete-parents", "AUTO_COMPLETE_PARENTS", "Complete a parent task when its last open subtask is completed (default false)"},
+	{"mysql-max-open-conns", "MYSQL_MAX_OPEN_CONNS", "Maximum open MySQL connections (default 25)"},
+	{"mysql-max-idle-conns", "MYSQL_MAX_IDLE_CONNS", "Maximum idle MySQL connections (default 25)"},
+	{"mysql-conn-max-lifetime", "MYSQL_CONN_MAX_LIFETIME", "Maximum lifetime of a MySQL connection (default 5m)"},
+	{"mysql-conn-max-idle-time", "MYSQL_CONN_MAX_IDLE_TIME", "Maximum idle time of a MySQL connection (default 1m)"},
+	{"mysql-connect-timeout", "MYSQL_CONNECT_TIMEOUT", "How long to keep retrying MySQL at startup (default 1m)"},
+	{"mysql-retry-initial", "MYSQL_RETRY_INITIAL", "Initial startup retry backoff, must be positive (default 500ms)"},
+	{"mysql-retry-max", "MYSQL_RETRY_MAX", "Maximum startup retry backoff, at least the initial (default 5s)"},
+	{"mysql-query-timeout", "MYSQL_QUERY_TIMEOUT", "Timeout for each MySQL query, 0 to disable (default 10s)"},
+	{"mysql-tls", "MYSQL_TLS", "MySQL TLS mode: false|true|skip-verify|preferred"},
+	{"mysql-tls-ca", "MYSQL_TLS_CA", "CA bundle used to verify the MySQL server"},
+	{"mysql-tls-cert", "MYSQL_TLS_CERT", "Client certificate for MySQL TLS"},
+	{"mysql-tls-key", "MYSQL_TLS_KEY", "Client key for MySQL TLS"},
+	{"mysql-tls-server-name", "MYSQL_TLS_SERVER_NAME", "Expected MySQL server name, if it differs from the host"},
+	{"mysql-replica-dsns", "MYSQL_REPLICA_DSNS", "Comma-separated read replica DSNs"},
+	{"mysql-replica-dsns-file", "MYSQL_REPLICA_DSNS_FILE", "File listing read replica DSNs, one per line"},
+	{"mysql-replica-max-lag", "MYSQL_REPLICA_MAX_LAG", "Replication lag above which reads fall back to the primary (default 5s)"},
+	{"mysql-replica-check-interval", "MYSQL_REPLICA_CHECK_INTERVAL", "How often replicas are health-checked (default 5s, must be positive)"},
+}
+
+// exportEnvFlags copies every serverEnvFlags flag that was given into the env.
+func exportEnvFlags(cmd *cobra.Command) {
+	for _, f := range serverEnvFlags {
+		if v, _ := cmd.Flags().GetString(f.flag); v != "" {
+			os.Setenv(f.env, v)
+		}
+	}
+}
+
 // clientCmd groups the client subcommands
 func clientCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "client",
-		Short: "Run the gRPC client (add|get|complete)",
+		Short: "Run the gRPC client (add|get|complete|assign|delete|trash|history|restore|project|comment|export|import|tui|shell)",
+		// fill in the connection from env and profile before any subcommand dials
+		PersistentPreRunE: resolveTarget,
 	}
 
 	// client flags apply to all subcommands
-	cmd.PersistentFlags().StringVar(&ClientHost, "host", "localhost", "gRPC server host")
-	cmd.PersistentFlags().StringVar(&ClientPort, "port", "50051", "gRPC server port")
-	cmd.PersistentFlags().StringVar(&Token, "token", "", "Bearer token for auth")
+	connectionFlags(cmd)
+	cmd.PersistentFlags().StringVarP(&Output, "output", "o", OutputTable, "Output format: table|json|yaml|csv|template")
+	cmd.PersistentFlags().StringVar(&Template, "template", "", "Go template applied to each task with --output template, e.g. '{{.ID}} {{.Title}}'")
 
 	// add the actions
 	cmd.AddCommand(addCmd())
 	cmd.AddCommand(getCmd())
 	cmd.AddCommand(completeCmd())
+	cmd.AddCommand(moveCmd())
+	cmd.AddCommand(assignCmd())
+	cmd.AddCommand(usersCmd())
+	cmd.AddCommand(deleteCmd())
+	cmd.AddCommand(trashCmd())
+	cmd.AddCommand(undeleteCmd())
+	cmd.AddCommand(historyCmd())
+	cmd.AddCommand(restoreCmd())
+	cmd.AddCommand(dependCmd())
+	cmd.AddCommand(nextCmd())
+	cmd.AddCommand(repeatCmd())
+	cmd.AddCommand(projectCmd())
+	cmd.AddCommand(commentCmd())
+	cmd.AddCommand(exportCmd())
+	cmd.AddCommand(importCmd())
+	cmd.AddCommand(tuiCmd())
+	cmd.AddCommand(shellCmd())
+	cmd.AddCommand(loginCmd())
+	cmd.AddCommand(useProfileCmd())
 	return cmd
 }
 
+// connectionFlags adds the flags that say which server to call and as whom.
+// resolveTarget fills in the ones not given from the env and profile.
+func connectionFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringVar(&ClientHost, "host", "localhost", "gRPC server host")
+	cmd.PersistentFlags().StringVar(&ClientPort, "port", "50051", "gRPC server port")
+	cmd.PersistentFlags().StringSliceVar(&ClientAddrs, "addrs", nil, "Comma-separated host:port list to balance across (overrides --host/--port)")
+	cmd.PersistentFlags().StringVar(&Token, "token", "", "Bearer token for auth (prefer 'client login' or TODO_TOKEN, which stay out of shell history)")
+	cmd.PersistentFlags().StringVar(&ProfileName, "profile", "", "Profile from the config file to use instead of the current one (env TODO_PROFILE)")
+	cmd.PersistentFlags().DurationVar(&Timeout, "timeout", 5*time.Second, "Timeout for each command")
+}
+
 // addCmd calls the AddTask RPC
 func addCmd() *cobra.Command {
-	var title, desc string
+	var (
+		title, desc     string
+		parent, project int64
+		due, repeat     string
+		assignee        string
+	)
 	cmd := &cobra.Command{
 		Use:   "add",
 		Short: "Add a new task",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			conn, err := dial()
+			printer, err := NewTaskPrinter(Output, Template)
+			if err != nil {
+				return err
+			}
+			dueAt, err := parseDue(due)
 			if err != nil {
 				return err
 			}
-			defer conn.Close()
+			c, release, err := clientFor(cmd)
+			if err != nil {
+				return err
+			}
+			defer release()
 
-			client := pb.NewTodoServiceClient(conn)
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			ctx, cancel := context.WithTimeout(cmd.Context(), Timeout)
 			defer cancel()
 
-			res, err := client.AddTask(ctx, &pb.AddTaskRequest{
-				Task: &pb.Task{Title: title, Description: desc},
+			created, err := c.AddTask(ctx, model.Task{
+				Title:       title,
+				Description: desc,
+				ParentID:    parent,
+				ProjectID:   project,
+				DueAt:       dueAt,
+				Recurrence:  repeat,
+				Assignee:    assignee,
 			})
 			if err != nil {
 				return err
 			}
-			fmt.Printf("Created task ID=%d\n", res.Task.Id)
-			return nil
+			return printer.PrintOne(cmd.OutOrStdout(), created)
 		},
 	}
 
 	cmd.Flags().StringVar(&title, "title", "", "Task title (required)")
 	cmd.MarkFlagRequired("title")
 	cmd.Flags().StringVar(&desc, "desc", "", "Task description")
+	cmd.Flags().Int64Var(&parent, "parent", 0, "Add as a subtask of this task ID")
+	cmd.Flags().Int64Var(&project, "project", 0, "Add to this project ID")
+	cmd.Flags().StringVar(&due, "due", "", "Due date: YYYY-MM-DD, 'YYYY-MM-DD HH:MM' (local time) or RFC 3339")
+	cmd.Flags().StringVar(&repeat, "repeat", "", "Recurrence rule (RFC 5545 RRULE), e.g. 'FREQ=WEEKLY;BYDAY=MO'; needs --due")
+	cmd.Flags().StringVar(&assignee, "assignee", "", "Assign to this registered user")
 	return cmd
 }
 
-// getCmd calls the ListTasks RPC
+// getCmd calls the ListTasks RPC, page by page
 func getCmd() *cobra.Command {
-	return &cobra.Command{
+	var (
+		tree   bool
+		filter client.Filter
+	)
+	cmd := &cobra.Command{
 		Use:   "get",
 		Short: "List all tasks",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			conn, err := dial()
+			printer, err := NewTaskPrinter(Output, Template)
+			if err != nil {
+				return err
+			}
+			if tree && Output != OutputTable {
+				return fmt.Errorf("--tree cannot be combined with --output %s", Output)
+			}
+			c, release, err := clientFor(cmd)
 			if err != nil {
 				return err
 			}
-			defer conn.Close()
+			defer release()
 
-			client := pb.NewTodoServiceClient(conn)
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			ctx, cancel := context.WithTimeout(cmd.Context(), Timeout)
 			defer cancel()
 
-			res, err := client.ListTasks(ctx, &pb.ListTasksRequest{})
-			if err != nil {
-				return err
+			var tasks []model.Task
+			for t, err := range c.ListTasksFiltered(ctx, filter) {
+				if err != nil {
+					return err
+				}
+				tasks = append(tasks, t)
 			}
-			for _, t := range res.Tasks {
-				fmt.Printf("[%d] %s (completed=%v)\n", t.Id, t.Title, t.Completed)
+			if tree {
+				return writeTree(cmd.OutOrStdout(), tasks)
 			}
-			return nil
+			return printer.Print(cmd.OutOrStdout(), tasks)
 		},
 	}
+	cmd.Flags().BoolVar(&tree, "tree", false, "Show subtasks indented under their parents")
+	filterFlags(cmd, &filter)
+	return cmd
 }
 
 // completeCmd calls the CompleteTask RPC
@@ -169,20 +323,30 @@ func completeCmd() *cobra.Command {
 		Use:   "complete",
 		Short: "Mark a task complete",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			conn, err := dial()
+			printer, err := NewTaskPrinter(Output, Template)
 			if err != nil {
 				return err
 			}
-			defer conn.Close()
+			c, release, err := clientFor(cmd)
+			if err != nil {
+				return err
+			}
+			defer release()
 
-			client := pb.NewTodoServiceClient(conn)
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			ctx, cancel := context.WithTimeout(cmd.Context(), Timeout)
 			defer cancel()
 
-			if _, err := client.CompleteTask(ctx, &pb.CompleteTaskRequest{Id: id}); err != nil {
+			updated, next, err := c.CompleteTaskNext(ctx, id)
+			if err != nil {
+				return err
+			}
+			if err := printer.PrintOne(cmd.OutOrStdout(), updated); err != nil {
 				return err
 			}
-			fmt.Printf("Task %d marked complete\n", id)
+			if next.ID != 0 {
+				// stderr, so scripts reading stdout see the same shape as before
+				fmt.Fprintf(cmd.ErrOrStderr(), "Next occurrence: #%d due %s\n", next.ID, formatDue(next.DueAt))
+			}
 			return nil
 		},
 	}
@@ -191,18 +355,187 @@ func completeCmd() *cobra.Command {
 	return cmd
 }
 
-func dial() (*grpc.ClientConn, error) {
-	addr := fmt.Sprintf("%s:%s", ClientHost, ClientPort)
+// moveCmd calls the MoveTask and SetTaskProject RPCs
+func moveCmd() *cobra.Command {
+	var id, parent, project int64
+	cmd := &cobra.Command{
+		Use:   "move",
+		Short: "Move a task under another task (--parent, 0 for top level) or to a project (--project, 0 for none)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := NewTaskPrinter(Output, Template)
+			if err != nil {
+				return err
+			}
+			toParent, toProject := cmd.Flags().Changed("parent"), cmd.Flags().Changed("project")
+			if !toParent && !toProject {
+				return errors.New("give --parent, --project or both")
+			}
+			c, release, err := clientFor(cmd)
+			if err != nil {
+				return err
+			}
+			defer release()
 
-	opts := []grpc.DialOption{
-		grpc.WithTransportCredentials(insecure.NewCredentials()),
-		grpc.WithBlock(),
-		grpc.WithTimeout(5 * time.Second),
+			ctx, cancel := context.WithTimeout(cmd.Context(), Timeout)
+			defer cancel()
+
+			var moved model.Task
+			if toParent {
+				if moved, err = c.MoveTask(ctx, id, parent); err != nil {
+					return err
+				}
+			}
+			if toProject {
+				if moved, err = c.SetTaskProject(ctx, id, project); err != nil {
+					return err
+				}
+			}
+			return printer.PrintOne(cmd.OutOrStdout(), moved)
+		},
 	}
-	if Token != "" {
-		opts = append(opts,
-			grpc.WithPerRPCCredentials(auth.StaticTokenCreds{Token: Token}),
-		)
+	cmd.Flags().Int64Var(&id, "id", 0, "Task ID (required)")
+	cmd.MarkFlagRequired("id")
+	cmd.Flags().Int64Var(&parent, "parent", 0, "New parent task ID, 0 for top level")
+	cmd.Flags().Int64Var(&project, "project", 0, "New project ID, 0 for none")
+	return cmd
+}
+
+// dependCmd calls the AddDependency / RemoveDependency RPCs, then lists the
+// task's blockers
+func dependCmd() *cobra.Command {
+	var (
+		id     int64
+		on     []int64
+		remove bool
+	)
+	cmd := &cobra.Command{
+		Use:   "depend",
+		Short: "Make a task wait for others (--on), or list what it waits for",
+		Example: "  todo client depend --id 3 --on 1,2\n" +
+			"  todo client depend --id 3 --on 2 --remove\n" +
+			"  todo client depend --id 3",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := NewTaskPrinter(Output, Template)
+			if err != nil {
+				return err
+			}
+			if remove && len(on) == 0 {
+				return errors.New("--remove needs --on")
+			}
+			c, release, err := clientFor(cmd)
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), Timeout)
+			defer cancel()
+
+			for _, blocker := range on {
+				if remove {
+					err = c.RemoveDependency(ctx, id, blocker)
+				} else {
+					err = c.AddDependency(ctx, id, blocker)
+				}
+				if err != nil {
+					return err
+				}
+			}
+			blockers, err := c.ListBlockers(ctx, id)
+			if err != nil {
+				return err
+			}
+			return printer.Print(cmd.OutOrStdout(), blockers)
+		},
+	}
+	cmd.Flags().Int64Var(&id, "id", 0, "Task ID (required)")
+	cmd.MarkFlagRequired("id")
+	cmd.Flags().Int64SliceVar(&on, "on", nil, "IDs of the tasks to finish first")
+	cmd.Flags().BoolVar(&remove, "remove", false, "Drop the dependencies on --on instead of adding them")
+	return cmd
+}
+
+// nextCmd lists the tasks that can be worked on now, or all open tasks in
+// the order they can be worked on
+func nextCmd() *cobra.Command {
+	var all bool
+	filter := client.Filter{Ready: true}
+	cmd := &cobra.Command{
+		Use:   "next",
+		Short: "List open tasks that no open task blocks",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := NewTaskPrinter(Output, Template)
+			if err != nil {
+				return err
+			}
+			c, release, err := clientFor(cmd)
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), Timeout)
+			defer cancel()
+
+			if all {
+				tasks, err := c.PlanTasks(ctx, filter)
+				if err != nil {
+					return err
+				}
+				return printer.Print(cmd.OutOrStdout(), tasks)
+			}
+			var tasks []model.Task
+			for t, err := range c.ListTasksFiltered(ctx, filter) {
+				if err != nil {
+					return err
+				}
+				tasks = append(tasks, t)
+			}
+			return printer.Print(cmd.OutOrStdout(), tasks)
+		},
+	}
+	cmd.Flags().BoolVar(&all, "all", false, "List every open task, each after the tasks it waits for")
+	filterFlags(cmd, &filter)
+	return cmd
+}
+
+// filterFlags adds the flags that narrow a task listing to f.
+func filterFlags(cmd *cobra.Command, f *client.Filter) {
+	cmd.Flags().Int64Var(&f.ProjectID, "project", 0, "Only tasks in this project ID")
+	cmd.Flags().StringVar(&f.Assignee, "assignee", "", "Only tasks assigned to this user")
+	cmd.Flags().BoolVar(&f.AssignedToMe, "mine", false, "Only tasks assigned to you")
+	cmd.Flags().BoolVar(&f.IncludeArchived, "archived", false, "Also list archived tasks")
+	cmd.MarkFlagsMutuallyExclusive("assignee", "mine")
+}
+
+// tuiCmd opens the interactive full-screen task list
+func tuiCmd() *cobra.Command {
+	var refresh time.Duration
+	cmd := &cobra.Command{
+		Use:   "tui",
+		Short: "Browse and edit tasks interactively",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient()
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+
+			return tui.Run(c, tui.Options{Timeout: Timeout, RefreshInterval: refresh})
+		},
+	}
+	cmd.Flags().DurationVar(&refresh, "refresh", 30*time.Second, "How often to reload the list (0 disables)")
+	return cmd
+}
+
+// newClient builds an SDK client from the persistent client flags.
+func newClient() (*client.Client, error) {
+	addrs := ClientAddrs
+	if len(addrs) == 0 {
+		addrs = []string{net.JoinHostPort(ClientHost, ClientPort)}
 	}
-	return grpc.Dial(addr, opts...)
+	return client.New(
+		client.WithAddresses(addrs...),
+		client.WithToken(Token),
+	)
 }
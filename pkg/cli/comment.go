@@ -0,0 +1,150 @@
+// pkg/cli/comment.go
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"hearx/pkg/model"
+)
+
+// commentCmd groups the CommentService subcommands
+func commentCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "comment",
+		Short: "Discuss a task without touching its description (add|list|delete)",
+		Example: "  todo client comment add --id 3 --text 'Blocked on the vendor'\n" +
+			"  todo client comment list --id 3\n" +
+			"  todo client comment delete --comment 12",
+	}
+	cmd.AddCommand(commentAddCmd(), commentListCmd(), commentDeleteCmd())
+	return cmd
+}
+
+func commentAddCmd() *cobra.Command {
+	var (
+		id   int64
+		text string
+	)
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Comment on a task",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkOutput(Output, "comments"); err != nil {
+				return err
+			}
+			c, release, err := clientFor(cmd)
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), Timeout)
+			defer cancel()
+
+			created, err := c.AddComment(ctx, id, text)
+			if err != nil {
+				return err
+			}
+			if Output == OutputTable {
+				return printComments(cmd.OutOrStdout(), []model.Comment{created})
+			}
+			return printComment(cmd.OutOrStdout(), created)
+		},
+	}
+	cmd.Flags().Int64Var(&id, "id", 0, "Task ID (required)")
+	cmd.MarkFlagRequired("id")
+	cmd.Flags().StringVar(&text, "text", "", "Comment text (required)")
+	cmd.MarkFlagRequired("text")
+	return cmd
+}
+
+func commentListCmd() *cobra.Command {
+	var id int64
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List a task's comments, oldest first",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkOutput(Output, "comments"); err != nil {
+				return err
+			}
+			c, release, err := clientFor(cmd)
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), Timeout)
+			defer cancel()
+
+			var list []model.Comment
+			for cm, err := range c.ListComments(ctx, id) {
+				if err != nil {
+					return err
+				}
+				list = append(list, cm)
+			}
+			return printComments(cmd.OutOrStdout(), list)
+		},
+	}
+	cmd.Flags().Int64Var(&id, "id", 0, "Task ID (required)")
+	cmd.MarkFlagRequired("id")
+	return cmd
+}
+
+func commentDeleteCmd() *cobra.Command {
+	var id int64
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete one of your comments",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, release, err := clientFor(cmd)
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), Timeout)
+			defer cancel()
+
+			if err := c.DeleteComment(ctx, id); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Deleted comment %d\n", id)
+			return nil
+		},
+	}
+	cmd.Flags().Int64Var(&id, "comment", 0, "Comment ID (required)")
+	cmd.MarkFlagRequired("comment")
+	return cmd
+}
+
+func printComment(w io.Writer, cm model.Comment) error {
+	if Output == OutputYAML {
+		return yaml.NewEncoder(w).Encode(cm)
+	}
+	return writeJSON(w, cm)
+}
+
+func printComments(w io.Writer, list []model.Comment) error {
+	if list == nil {
+		list = []model.Comment{}
+	}
+	switch Output {
+	case OutputJSON:
+		return writeJSON(w, list)
+	case OutputYAML:
+		return yaml.NewEncoder(w).Encode(list)
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tAUTHOR\tCREATED\tCOMMENT")
+	for _, cm := range list {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", cm.ID, cm.Author, formatDue(cm.CreatedAt), oneLine(cm.Body))
+	}
+	return tw.Flush()
+}
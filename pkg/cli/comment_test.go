@@ -0,0 +1,102 @@
+// pkg/cli/comment_test.go
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"hearx/pkg/cli"
+	pb "hearx/proto"
+)
+
+// fakeCommentServer keeps comments in memory and serves them in pages of two.
+type fakeCommentServer struct {
+	pb.UnimplementedCommentServiceServer
+	comments []*pb.Comment
+}
+
+func (f *fakeCommentServer) AddComment(_ context.Context, req *pb.AddCommentRequest) (*pb.AddCommentResponse, error) {
+	c := &pb.Comment{Id: int64(len(f.comments) + 1), TaskId: req.TaskId, Author: "alice", Body: req.Body}
+	f.comments = append(f.comments, c)
+	return &pb.AddCommentResponse{Comment: c}, nil
+}
+
+func (f *fakeCommentServer) ListComments(_ context.Context, req *pb.ListCommentsRequest) (*pb.ListCommentsResponse, error) {
+	after, _ := strconv.ParseInt(req.PageToken, 10, 64)
+	resp := &pb.ListCommentsResponse{}
+	for _, c := range f.comments {
+		if c.TaskId != req.TaskId || c.Id <= after {
+			continue
+		}
+		if len(resp.Comments) == 2 {
+			resp.NextPageToken = strconv.FormatInt(resp.Comments[1].Id, 10)
+			break
+		}
+		resp.Comments = append(resp.Comments, c)
+	}
+	return resp, nil
+}
+
+func (f *fakeCommentServer) DeleteComment(_ context.Context, req *pb.DeleteCommentRequest) (*pb.DeleteCommentResponse, error) {
+	for i, c := range f.comments {
+		if c.Id == req.Id {
+			f.comments = append(f.comments[:i], f.comments[i+1:]...)
+			return &pb.DeleteCommentResponse{}, nil
+		}
+	}
+	return nil, status.Error(codes.NotFound, "comment not found")
+}
+
+var _ = Describe("comment command", func() {
+	var (
+		env *shellEnv
+		out *bytes.Buffer
+		sh  *cli.Shell
+	)
+
+	BeforeEach(func() {
+		env = newShellEnv()
+		out, sh = env.out, env.sh
+	})
+
+	AfterEach(func() { env.close() })
+
+	It("should list every page of a task's comments", func() {
+		script := `comment add --id 1 --text "first"
+comment add --id 2 --text "elsewhere"
+comment add --id 1 --text second
+comment add --id 1 --text third
+`
+		Expect(sh.RunScript(strings.NewReader(script))).To(Succeed())
+
+		out.Reset()
+		Expect(sh.Exec("comment list --id 1")).To(Succeed())
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		Expect(lines).To(HaveLen(4))
+		Expect(lines[0]).To(HavePrefix("ID"))
+		Expect(lines[2]).To(ContainSubstring("second"))
+		Expect(lines[3]).To(HavePrefix("4 "))
+	})
+
+	It("should print a single comment as an object", func() {
+		Expect(sh.Exec("comment add --id 1 --text hi -o json")).To(Succeed())
+		Expect(out.String()).To(HavePrefix("{"))
+		Expect(out.String()).To(ContainSubstring(`"author": "alice"`))
+	})
+
+	It("should delete a comment by its own ID", func() {
+		Expect(sh.Exec("comment add --id 1 --text hi")).To(Succeed())
+		Expect(sh.Exec("comment delete --comment 1")).To(Succeed())
+		Expect(env.comments.comments).To(BeEmpty())
+
+		err := sh.Exec("comment delete --comment 1")
+		Expect(cli.ExitCode(err)).To(Equal(int(codes.NotFound)))
+	})
+})
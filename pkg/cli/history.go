@@ -0,0 +1,139 @@
+// pkg/cli/history.go
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"hearx/pkg/model"
+)
+
+// deleteCmd calls the DeleteTask RPC
+func deleteCmd() *cobra.Command {
+	var id int64
+	cmd := &cobra.Command{
+		Use:     "delete",
+		Short:   "Delete a task; its history is kept and restore brings it back",
+		Example: "  todo client delete --id 5\n  todo client restore --id 5 --rev 3",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, release, err := clientFor(cmd)
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), Timeout)
+			defer cancel()
+
+			if err := c.DeleteTask(ctx, id); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Deleted task %d\n", id)
+			return nil
+		},
+	}
+	cmd.Flags().Int64Var(&id, "id", 0, "Task ID (required)")
+	cmd.MarkFlagRequired("id")
+	return cmd
+}
+
+// historyCmd calls the GetTaskHistory RPC
+func historyCmd() *cobra.Command {
+	var id int64
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "List the revisions of a task, deleted or not, with what each changed",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkOutput(Output, "history"); err != nil {
+				return err
+			}
+			c, release, err := clientFor(cmd)
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), Timeout)
+			defer cancel()
+
+			var list []model.Revision
+			for r, err := range c.TaskHistory(ctx, id) {
+				if err != nil {
+					return err
+				}
+				list = append(list, r)
+			}
+			return printRevisions(cmd.OutOrStdout(), list)
+		},
+	}
+	cmd.Flags().Int64Var(&id, "id", 0, "Task ID (required)")
+	cmd.MarkFlagRequired("id")
+	return cmd
+}
+
+// restoreCmd calls the RestoreTaskRevision RPC
+func restoreCmd() *cobra.Command {
+	var id, rev int64
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Put a task back as it was at a revision, undeleting it if needed",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := NewTaskPrinter(Output, Template)
+			if err != nil {
+				return err
+			}
+			c, release, err := clientFor(cmd)
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), Timeout)
+			defer cancel()
+
+			task, err := c.RestoreTaskRevision(ctx, id, rev)
+			if err != nil {
+				return err
+			}
+			return printer.PrintOne(cmd.OutOrStdout(), task)
+		},
+	}
+	cmd.Flags().Int64Var(&id, "id", 0, "Task ID (required)")
+	cmd.Flags().Int64Var(&rev, "rev", 0, "Revision to restore, as listed by history (required)")
+	cmd.MarkFlagRequired("id")
+	cmd.MarkFlagRequired("rev")
+	return cmd
+}
+
+func printRevisions(w io.Writer, list []model.Revision) error {
+	if list == nil {
+		list = []model.Revision{}
+	}
+	switch Output {
+	case OutputJSON:
+		return writeJSON(w, list)
+	case OutputYAML:
+		return yaml.NewEncoder(w).Encode(list)
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "REV\tTIME\tCHANGES")
+	for _, r := range list {
+		fmt.Fprintf(tw, "%d\t%s\t%s\n", r.Number, r.CreatedAt.Local().Format("2006-01-02 15:04"), formatChanges(r.Changes))
+	}
+	return tw.Flush()
+}
+
+// formatChanges renders changes on one line, e.g. title: "a" -> "b".
+func formatChanges(changes []model.FieldChange) string {
+	parts := make([]string, len(changes))
+	for i, c := range changes {
+		parts[i] = fmt.Sprintf("%s: %q -> %q", c.Field, oneLine(c.From), oneLine(c.To))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,93 @@
+// pkg/cli/history_test.go
+package cli_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"hearx/pkg/model"
+	pb "hearx/proto"
+)
+
+func (f *fakeServer) DeleteTask(_ context.Context, req *pb.DeleteTaskRequest) (*pb.DeleteTaskResponse, error) {
+	if req.Id < 1 || int(req.Id) > len(f.tasks) || f.deleted[req.Id] {
+		return nil, status.Error(codes.NotFound, "task not found")
+	}
+	if f.deleted == nil {
+		f.deleted = map[int64]bool{}
+	}
+	f.deleted[req.Id] = true
+	return &pb.DeleteTaskResponse{}, nil
+}
+
+func (f *fakeServer) GetTaskHistory(_ context.Context, req *pb.GetTaskHistoryRequest) (*pb.GetTaskHistoryResponse, error) {
+	revs, ok := f.history[req.TaskId]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "task not found")
+	}
+	return &pb.GetTaskHistoryResponse{Revisions: revs}, nil
+}
+
+func (f *fakeServer) RestoreTaskRevision(_ context.Context, req *pb.RestoreTaskRevisionRequest) (*pb.RestoreTaskRevisionResponse, error) {
+	for _, r := range f.history[req.TaskId] {
+		if r.Revision == req.Revision {
+			t := proto.Clone(r.Task).(*pb.Task)
+			f.tasks[req.TaskId-1] = t
+			delete(f.deleted, req.TaskId)
+			return &pb.RestoreTaskRevisionResponse{Task: t}, nil
+		}
+	}
+	return nil, status.Error(codes.NotFound, "no such revision")
+}
+
+var _ = Describe("history", func() {
+	var env *shellEnv
+
+	BeforeEach(func() {
+		env = newShellEnv()
+		env.fake.history = map[int64][]*pb.TaskRevision{1: {
+			{TaskId: 1, Revision: 1, Task: &pb.Task{Id: 1, Title: "draft"},
+				Changes: []*pb.FieldChange{{Field: "title", To: "draft"}}},
+			{TaskId: 1, Revision: 2, Task: &pb.Task{Id: 1, Title: "final"},
+				Changes: []*pb.FieldChange{{Field: "title", From: "draft", To: "final"}}},
+		}}
+	})
+	AfterEach(func() { env.close() })
+
+	It("should list revisions with their changes", func() {
+		Expect(env.sh.Exec("history --id 1")).To(Succeed())
+		out := env.out.String()
+		Expect(out).To(HavePrefix("REV"))
+		Expect(out).To(ContainSubstring(`title: "draft" -> "final"`))
+	})
+
+	It("should print revisions as JSON", func() {
+		Expect(env.sh.Exec("history --id 1 -o json")).To(Succeed())
+		var revs []model.Revision
+		Expect(json.Unmarshal(env.out.Bytes(), &revs)).To(Succeed())
+		Expect(revs).To(HaveLen(2))
+		Expect(revs[1].Changes[0].From).To(Equal("draft"))
+	})
+
+	It("should delete a task and restore it from a revision", func() {
+		script := "add --title final\ndelete --id 1\n"
+		Expect(env.sh.RunScript(strings.NewReader(script))).To(Succeed())
+		Expect(env.fake.deleted[1]).To(BeTrue())
+		Expect(env.out.String()).To(ContainSubstring("Deleted task 1"))
+
+		Expect(env.sh.Exec("restore --id 1 --rev 1")).To(Succeed())
+		Expect(env.fake.deleted[1]).To(BeFalse())
+		Expect(env.fake.tasks[0].Title).To(Equal("draft"))
+	})
+
+	It("should require a revision to restore", func() {
+		Expect(env.sh.Exec("restore --id 1")).To(MatchError(ContainSubstring("rev")))
+	})
+})
@@ -0,0 +1,285 @@
+// pkg/cli/output.go
+package cli
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"google.golang.org/grpc/status"
+	"gopkg.in/yaml.v3"
+
+	"hearx/pkg/model"
+)
+
+// Output formats accepted by --output.
+const (
+	OutputTable    = "table"
+	OutputJSON     = "json"
+	OutputYAML     = "yaml"
+	OutputCSV      = "csv"
+	OutputTemplate = "template"
+)
+
+var (
+	// Output selects how client commands print tasks
+	Output string
+	// Template is the Go template used with --output template
+	Template string
+)
+
+// TaskPrinter renders tasks in one of the --output formats.
+type TaskPrinter struct {
+	format string
+	tmpl   *template.Template
+}
+
+// NewTaskPrinter validates the format (and template, if used).
+func NewTaskPrinter(format, tmpl string) (*TaskPrinter, error) {
+	p := &TaskPrinter{format: format}
+	switch format {
+	case OutputTable, OutputJSON, OutputYAML, OutputCSV:
+	case OutputTemplate:
+		if tmpl == "" {
+			return nil, errors.New("--output template requires --template")
+		}
+		t, err := template.New("task").Parse(tmpl)
+		if err != nil {
+			return nil, fmt.Errorf("parse --template: %w", err)
+		}
+		p.tmpl = t
+	default:
+		return nil, fmt.Errorf("unknown output format %q (want table|json|yaml|csv|template)", format)
+	}
+	return p, nil
+}
+
+// PrintOne writes a single task, as an object rather than a list.
+func (p *TaskPrinter) PrintOne(w io.Writer, t model.Task) error {
+	switch p.format {
+	case OutputJSON:
+		return writeJSON(w, t)
+	case OutputYAML:
+		return yaml.NewEncoder(w).Encode(t)
+	default:
+		return p.Print(w, []model.Task{t})
+	}
+}
+
+// Print writes a list of tasks.
+func (p *TaskPrinter) Print(w io.Writer, tasks []model.Task) error {
+	if tasks == nil {
+		tasks = []model.Task{}
+	}
+	switch p.format {
+	case OutputJSON:
+		return writeJSON(w, tasks)
+	case OutputYAML:
+		return yaml.NewEncoder(w).Encode(tasks)
+	case OutputCSV:
+		return writeCSV(w, tasks)
+	case OutputTemplate:
+		return p.writeTemplate(w, tasks)
+	default:
+		return writeTable(w, tasks)
+	}
+}
+
+// checkOutput rejects the task-only formats for commands that print other
+// kinds of records.
+func checkOutput(format, kind string) error {
+	switch format {
+	case OutputTable, OutputJSON, OutputYAML:
+		return nil
+	default:
+		return fmt.Errorf("--output %s is not supported for %s (want table|json|yaml)", format, kind)
+	}
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// writeTable adds DUE and REPEAT columns only when some task has a due date,
+// ASSIGNEE only when some task is assigned and DELETED only for tasks in the
+// trash, so lists without any stay narrow.
+func writeTable(w io.Writer, tasks []model.Task) error {
+	scheduled, assigned, deleted := false, false, false
+	for _, t := range tasks {
+		if !t.DeletedAt.IsZero() {
+			deleted = true
+		}
+		if !t.DueAt.IsZero() || t.Recurrence != "" {
+			scheduled = true
+		}
+		if t.Assignee != "" {
+			assigned = true
+		}
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprint(tw, "ID\tTITLE\tDESCRIPTION\tSTATUS")
+	if assigned {
+		fmt.Fprint(tw, "\tASSIGNEE")
+	}
+	if scheduled {
+		fmt.Fprint(tw, "\tDUE\tREPEAT")
+	}
+	if deleted {
+		fmt.Fprint(tw, "\tDELETED")
+	}
+	fmt.Fprintln(tw)
+	for _, t := range tasks {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s", t.ID, oneLine(t.Title), oneLine(t.Description), statusOf(t))
+		if assigned {
+			fmt.Fprintf(tw, "\t%s", t.Assignee)
+		}
+		if scheduled {
+			fmt.Fprintf(tw, "\t%s\t%s", formatDue(t.DueAt), t.Recurrence)
+		}
+		if deleted {
+			fmt.Fprintf(tw, "\t%s", formatDue(t.DeletedAt))
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
+
+// formatDue shows a due date in local time, without the time of day when it
+// is midnight.
+func formatDue(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	t = t.Local()
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+		return t.Format("2006-01-02")
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+// writeTree prints tasks as a forest, each subtask under its parent. A task
+// whose parent is not in the list is shown as a root.
+func writeTree(w io.Writer, tasks []model.Task) error {
+	present := make(map[int64]bool, len(tasks))
+	for _, t := range tasks {
+		present[t.ID] = true
+	}
+	children := map[int64][]model.Task{}
+	var roots []model.Task
+	for _, t := range tasks {
+		if t.ParentID == 0 || !present[t.ParentID] {
+			roots = append(roots, t)
+			continue
+		}
+		children[t.ParentID] = append(children[t.ParentID], t)
+	}
+
+	bw := bufio.NewWriter(w)
+	var walk func(t model.Task, prefix, branch, indent string)
+	walk = func(t model.Task, prefix, branch, indent string) {
+		fmt.Fprintf(bw, "%s%s%d  [%s] %s\n", prefix, branch, t.ID, statusOf(t), oneLine(t.Title))
+		kids := children[t.ID]
+		for i, k := range kids {
+			if i == len(kids)-1 {
+				walk(k, prefix+indent, "└── ", "    ")
+			} else {
+				walk(k, prefix+indent, "├── ", "│   ")
+			}
+		}
+	}
+	for _, r := range roots {
+		walk(r, "", "", "")
+	}
+	return bw.Flush()
+}
+
+// csvHeader names the columns of csvRecord; import reads the same layout.
+var csvHeader = []string{"id", "title", "description", "completed", "owner", "created_at", "updated_at", "due_at", "recurrence"}
+
+func csvRecord(t model.Task) []string {
+	return []string{
+		strconv.FormatInt(t.ID, 10),
+		t.Title,
+		t.Description,
+		strconv.FormatBool(t.Completed),
+		t.Owner,
+		csvTime(t.CreatedAt),
+		csvTime(t.UpdatedAt),
+		csvTime(t.DueAt),
+		t.Recurrence,
+	}
+}
+
+// csvTime writes RFC 3339, or nothing for an unset time.
+func csvTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func writeCSV(w io.Writer, tasks []model.Task) error {
+	cw := csv.NewWriter(w)
+	cw.Write(csvHeader)
+	for _, t := range tasks {
+		cw.Write(csvRecord(t))
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeTemplate executes the template once per task, each on its own line.
+func (p *TaskPrinter) writeTemplate(w io.Writer, tasks []model.Task) error {
+	for _, t := range tasks {
+		var b strings.Builder
+		if err := p.tmpl.Execute(&b, t); err != nil {
+			return err
+		}
+		out := b.String()
+		if !strings.HasSuffix(out, "\n") {
+			out += "\n"
+		}
+		if _, err := io.WriteString(w, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func statusOf(t model.Task) string {
+	if !t.ArchivedAt.IsZero() {
+		return "archived"
+	}
+	if t.Completed {
+		return "done"
+	}
+	return "open"
+}
+
+// oneLine keeps multi-line descriptions from breaking table alignment.
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// ExitCode maps an error returned by Execute to the process exit status:
+// gRPC failures exit with their status code (e.g. 5 for NotFound, 16 for
+// Unauthenticated), any other error with 1.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	if st, ok := status.FromError(err); ok && st.Code() != 0 {
+		return int(st.Code())
+	}
+	return 1
+}
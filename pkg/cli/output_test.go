@@ -0,0 +1,97 @@
+// pkg/cli/output_test.go
+package cli_test
+
+import (
+	"bytes"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"hearx/pkg/cli"
+	"hearx/pkg/model"
+)
+
+var _ = Describe("TaskPrinter", func() {
+	var (
+		tasks []model.Task
+		out   *bytes.Buffer
+	)
+
+	BeforeEach(func() {
+		tasks = []model.Task{
+			{ID: 1, Title: "Buy eggs", Description: "A dozen"},
+			{ID: 12, Title: "Ship it", Completed: true},
+		}
+		out = &bytes.Buffer{}
+	})
+
+	print := func(format, tmpl string) string {
+		p, err := cli.NewTaskPrinter(format, tmpl)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.Print(out, tasks)).To(Succeed())
+		return out.String()
+	}
+
+	It("should render an aligned table with description and status", func() {
+		Expect(print("table", "")).To(Equal(
+			"ID  TITLE     DESCRIPTION  STATUS\n" +
+				"1   Buy eggs  A dozen      open\n" +
+				"12  Ship it                done\n"))
+	})
+
+	It("should render JSON", func() {
+		Expect(print("json", "")).To(ContainSubstring(`"title": "Ship it"`))
+	})
+
+	It("should render YAML", func() {
+		Expect(print("yaml", "")).To(ContainSubstring("- id: 12\n  title: Ship it\n  completed: true"))
+	})
+
+	It("should render CSV with a header", func() {
+		Expect(print("csv", "")).To(Equal(
+			"id,title,description,completed,owner,created_at,updated_at,due_at,recurrence\n" +
+				"1,Buy eggs,A dozen,false,,,,,\n" +
+				"12,Ship it,,true,,,,,\n"))
+	})
+
+	It("should add due date and recurrence columns when a task has them", func() {
+		tasks[1].DueAt = time.Date(2026, 10, 26, 0, 0, 0, 0, time.Local)
+		tasks[1].Recurrence = "FREQ=WEEKLY"
+		Expect(print("table", "")).To(Equal(
+			"ID  TITLE     DESCRIPTION  STATUS  DUE         REPEAT\n" +
+				"1   Buy eggs  A dozen      open                \n" +
+				"12  Ship it                done    2026-10-26  FREQ=WEEKLY\n"))
+	})
+
+	It("should apply a template to each task", func() {
+		Expect(print("template", "{{.ID}}={{.Title}}")).To(Equal("1=Buy eggs\n12=Ship it\n"))
+	})
+
+	It("should print a single task as an object", func() {
+		p, err := cli.NewTaskPrinter("json", "")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.PrintOne(out, tasks[0])).To(Succeed())
+		Expect(out.String()).To(HavePrefix("{"))
+	})
+
+	It("should reject unknown formats and missing templates", func() {
+		_, err := cli.NewTaskPrinter("xml", "")
+		Expect(err).To(HaveOccurred())
+		_, err = cli.NewTaskPrinter("template", "")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("ExitCode", func() {
+	It("should map gRPC status codes", func() {
+		Expect(cli.ExitCode(nil)).To(Equal(0))
+		Expect(cli.ExitCode(status.Error(codes.NotFound, "x"))).To(Equal(5))
+		Expect(cli.ExitCode(status.Error(codes.Unauthenticated, "x"))).To(Equal(16))
+		Expect(cli.ExitCode(errors.New("plain"))).To(Equal(1))
+	})
+})
@@ -0,0 +1,226 @@
+// pkg/cli/profile.go
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mattn/go-isatty"
+	"github.com/peterh/liner"
+	"github.com/spf13/cobra"
+
+	"hearx/pkg/client"
+	"hearx/pkg/profile"
+)
+
+// Environment overrides for the client connection. They beat the profile but
+// lose to flags given on the command line.
+const (
+	EnvProfile = "TODO_PROFILE"
+	EnvAddrs   = "TODO_ADDRS"
+	EnvToken   = "TODO_TOKEN"
+)
+
+// ProfileName selects a profile for one command (--profile).
+var ProfileName string
+
+// skipProfile marks commands that manage profiles rather than use one.
+const skipProfile = "skip-profile"
+
+// resolveTarget fills ClientAddrs and Token before any client command dials.
+// Precedence: flags, then TODO_ADDRS / TODO_TOKEN, then the profile named
+// by --profile or TODO_PROFILE or, failing both, the current one.
+func resolveTarget(cmd *cobra.Command, _ []string) error {
+	if _, ok := cmd.Annotations[skipProfile]; ok {
+		return nil
+	}
+	flags := cmd.Flags()
+
+	name := ProfileName
+	if name == "" {
+		name = os.Getenv(EnvProfile)
+	}
+	path, err := profile.Path()
+	if err != nil {
+		return err
+	}
+	cfg, err := profile.Load(path)
+	if err != nil {
+		return err
+	}
+	p, err := cfg.Get(name)
+	if err != nil {
+		return err
+	}
+
+	if !flags.Changed("addrs") && !flags.Changed("host") && !flags.Changed("port") {
+		if env := splitList(os.Getenv(EnvAddrs)); len(env) > 0 {
+			ClientAddrs = env
+		} else if len(p.Addrs) > 0 {
+			ClientAddrs = p.Addrs
+		}
+	}
+	if !flags.Changed("token") {
+		if env := os.Getenv(EnvToken); env != "" {
+			Token = env
+		} else if p.Token != "" {
+			Token = p.Token
+		}
+	}
+	return nil
+}
+
+// splitList splits a comma-separated list, trimming spaces and dropping
+// blanks.
+func splitList(v string) []string {
+	var out []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// loginCmd stores the server address and token under a profile name
+func loginCmd() *cobra.Command {
+	var noVerify bool
+	cmd := &cobra.Command{
+		Use:         "login [profile]",
+		Short:       "Save the server address and token as a profile",
+		Long:        "Saves --addrs (or --host/--port) and a token under the given profile name, \"default\" if omitted.\nThe token is read from --token, TODO_TOKEN, or prompted for so it stays out of shell history.",
+		Args:        cobra.MaximumNArgs(1),
+		Annotations: map[string]string{skipProfile: ""},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := "default"
+			if len(args) == 1 {
+				name = args[0]
+			} else if ProfileName != "" {
+				name = ProfileName
+			}
+
+			p := profile.Profile{Addrs: ClientAddrs}
+			if len(p.Addrs) == 0 {
+				p.Addrs = []string{net.JoinHostPort(ClientHost, ClientPort)}
+			}
+			token, err := loginToken(cmd)
+			if err != nil {
+				return err
+			}
+			p.Token = token
+
+			if !noVerify {
+				if err := verifyProfile(cmd.Context(), p); err != nil {
+					return fmt.Errorf("verify %s: %w", strings.Join(p.Addrs, ","), err)
+				}
+			}
+
+			path, err := profile.Path()
+			if err != nil {
+				return err
+			}
+			cfg, err := profile.Load(path)
+			if err != nil {
+				return err
+			}
+			cfg.Profiles[name] = p
+			if cfg.Current == "" {
+				cfg.Current = name
+			}
+			if err := cfg.Save(path); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Saved profile %q to %s\n", name, path)
+			return nil
+		},
+	}
+	cmd.Flags().BoolVar(&noVerify, "no-verify", false, "Save without checking that the server accepts the token")
+	return cmd
+}
+
+// loginToken returns the token from --token or TODO_TOKEN, else asks for it:
+// without echo on a terminal, as one line on piped stdin.
+func loginToken(cmd *cobra.Command) (string, error) {
+	if cmd.Flags().Changed("token") {
+		return Token, nil
+	}
+	if env := os.Getenv(EnvToken); env != "" {
+		return env, nil
+	}
+	if isatty.IsTerminal(os.Stdin.Fd()) {
+		l := liner.NewLiner()
+		defer l.Close()
+		return l.PasswordPrompt("Token: ")
+	}
+	line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	if err != nil && line == "" {
+		return "", errors.New("no token given on stdin")
+	}
+	return strings.TrimSpace(line), nil
+}
+
+// verifyProfile makes one authenticated call with the new settings.
+func verifyProfile(ctx context.Context, p profile.Profile) error {
+	c, err := client.New(
+		client.WithAddresses(p.Addrs...),
+		client.WithToken(p.Token),
+		client.WithPageSize(1),
+	)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+	_, _, err = c.ListTasksPage(ctx, "")
+	return err
+}
+
+// useProfileCmd switches the current profile, or lists them
+func useProfileCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:         "use-profile [profile]",
+		Short:       "Make a profile current; without a name, list profiles",
+		Args:        cobra.MaximumNArgs(1),
+		Annotations: map[string]string{skipProfile: ""},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := profile.Path()
+			if err != nil {
+				return err
+			}
+			cfg, err := profile.Load(path)
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 0 {
+				w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "CURRENT\tNAME\tADDRS")
+				for _, n := range cfg.Names() {
+					mark := ""
+					if n == cfg.Current {
+						mark = "*"
+					}
+					fmt.Fprintf(w, "%s\t%s\t%s\n", mark, n, strings.Join(cfg.Profiles[n].Addrs, ","))
+				}
+				return w.Flush()
+			}
+
+			if err := cfg.Use(args[0]); err != nil {
+				return err
+			}
+			if err := cfg.Save(path); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Switched to profile %q\n", args[0])
+			return nil
+		},
+	}
+}
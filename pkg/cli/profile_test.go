@@ -0,0 +1,103 @@
+// pkg/cli/profile_test.go
+package cli_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"hearx/pkg/cli"
+	"hearx/pkg/profile"
+)
+
+var _ = Describe("client connection target", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "profile")
+		Expect(err).NotTo(HaveOccurred())
+		path := filepath.Join(dir, "config.yaml")
+		os.Setenv(profile.EnvConfig, path)
+
+		cfg := &profile.Config{
+			Current: "home",
+			Profiles: map[string]profile.Profile{
+				"home": {Addrs: []string{"home:1"}, Token: "home-token"},
+				"work": {Addrs: []string{"work:1"}, Token: "work-token"},
+				"lab":  {Addrs: []string{"lab:1"}, Token: "lab-token"},
+			},
+		}
+		Expect(cfg.Save(path)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		for _, env := range []string{profile.EnvConfig, cli.EnvProfile, cli.EnvAddrs, cli.EnvToken} {
+			os.Unsetenv(env)
+		}
+		os.RemoveAll(dir)
+	})
+
+	// resolve runs a client command that fails before dialing, once the
+	// target has been resolved, and returns what it was resolved to.
+	resolve := func(args ...string) ([]string, string, error) {
+		root := cli.NewRootCmd()
+		root.SetArgs(append(append([]string{"client"}, args...), "import", "tasks.unknown"))
+		root.SetOut(io.Discard)
+		root.SetErr(io.Discard)
+		err := root.Execute()
+		return cli.ClientAddrs, cli.Token, err
+	}
+
+	It("should use the current profile by default", func() {
+		addrs, token, err := resolve()
+		Expect(err).To(MatchError(ContainSubstring("cannot tell the format")))
+		Expect(addrs).To(Equal([]string{"home:1"}))
+		Expect(token).To(Equal("home-token"))
+	})
+
+	It("should prefer TODO_PROFILE to the current profile, and --profile to both", func() {
+		os.Setenv(cli.EnvProfile, "work")
+		addrs, token, _ := resolve()
+		Expect(addrs).To(Equal([]string{"work:1"}))
+		Expect(token).To(Equal("work-token"))
+
+		addrs, token, _ = resolve("--profile", "lab")
+		Expect(addrs).To(Equal([]string{"lab:1"}))
+		Expect(token).To(Equal("lab-token"))
+	})
+
+	It("should prefer TODO_ADDRS and TODO_TOKEN to any profile, trimming the addresses", func() {
+		os.Setenv(cli.EnvAddrs, " a:1 , b:2,, ")
+		os.Setenv(cli.EnvToken, "env-token")
+		addrs, token, _ := resolve("--profile", "lab")
+		Expect(addrs).To(Equal([]string{"a:1", "b:2"}))
+		Expect(token).To(Equal("env-token"))
+	})
+
+	It("should fall back to the profile when TODO_ADDRS is blank", func() {
+		os.Setenv(cli.EnvAddrs, " , ")
+		addrs, _, _ := resolve()
+		Expect(addrs).To(Equal([]string{"home:1"}))
+	})
+
+	It("should prefer flags to the environment and profiles", func() {
+		os.Setenv(cli.EnvAddrs, "a:1")
+		os.Setenv(cli.EnvToken, "env-token")
+		addrs, token, _ := resolve("--profile", "lab", "--addrs", "flag:1", "--token", "flag-token")
+		Expect(addrs).To(Equal([]string{"flag:1"}))
+		Expect(token).To(Equal("flag-token"))
+
+		// --host/--port also count as choosing the address
+		addrs, _, _ = resolve("--host", "elsewhere")
+		Expect(addrs).To(BeEmpty())
+	})
+
+	It("should fail on a profile the config does not define", func() {
+		_, _, err := resolve("--profile", "nope")
+		Expect(err).To(MatchError(profile.ErrNotFound))
+	})
+})
@@ -0,0 +1,288 @@
+// pkg/cli/project.go
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"hearx/pkg/model"
+)
+
+// projectCmd groups the ProjectService subcommands
+func projectCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "project",
+		Short: "Manage projects (create|list|show|update|archive|unarchive|delete)",
+		Example: "  todo client project create --name Home\n" +
+			"  todo client add --title 'Fix the tap' --project 1\n" +
+			"  todo client get --project 1\n" +
+			"  todo client project show --id 1",
+	}
+	cmd.AddCommand(
+		projectCreateCmd(),
+		projectListCmd(),
+		projectShowCmd(),
+		projectUpdateCmd(),
+		projectArchiveCmd("archive", "Archive a project: it is hidden from lists and takes no new tasks", true),
+		projectArchiveCmd("unarchive", "Bring an archived project back", false),
+		projectDeleteCmd(),
+	)
+	return cmd
+}
+
+func projectCreateCmd() *cobra.Command {
+	var name, desc string
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a project",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkOutput(Output, "projects"); err != nil {
+				return err
+			}
+			c, release, err := clientFor(cmd)
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), Timeout)
+			defer cancel()
+
+			created, err := c.CreateProject(ctx, model.Project{Name: name, Description: desc})
+			if err != nil {
+				return err
+			}
+			return printProject(cmd.OutOrStdout(), created, nil)
+		},
+	}
+	cmd.Flags().StringVar(&name, "name", "", "Project name, unique (required)")
+	cmd.MarkFlagRequired("name")
+	cmd.Flags().StringVar(&desc, "desc", "", "Project description")
+	return cmd
+}
+
+func projectListCmd() *cobra.Command {
+	var all bool
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List projects by name",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkOutput(Output, "projects"); err != nil {
+				return err
+			}
+			c, release, err := clientFor(cmd)
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), Timeout)
+			defer cancel()
+
+			list, err := c.ListProjects(ctx, all)
+			if err != nil {
+				return err
+			}
+			return printProjects(cmd.OutOrStdout(), list)
+		},
+	}
+	cmd.Flags().BoolVar(&all, "all", false, "Include archived projects")
+	return cmd
+}
+
+func projectShowCmd() *cobra.Command {
+	var id int64
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Show a project and how many of its tasks are done",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkOutput(Output, "projects"); err != nil {
+				return err
+			}
+			c, release, err := clientFor(cmd)
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), Timeout)
+			defer cancel()
+
+			p, err := c.GetProject(ctx, id)
+			if err != nil {
+				return err
+			}
+			stats, err := c.ProjectStats(ctx, id)
+			if err != nil {
+				return err
+			}
+			return printProject(cmd.OutOrStdout(), p, &stats)
+		},
+	}
+	cmd.Flags().Int64Var(&id, "id", 0, "Project ID (required)")
+	cmd.MarkFlagRequired("id")
+	return cmd
+}
+
+func projectUpdateCmd() *cobra.Command {
+	var (
+		id         int64
+		name, desc string
+	)
+	cmd := &cobra.Command{
+		Use:   "update",
+		Short: "Rename a project or change its description",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkOutput(Output, "projects"); err != nil {
+				return err
+			}
+			if !cmd.Flags().Changed("name") && !cmd.Flags().Changed("desc") {
+				return errors.New("nothing to update: give --name and/or --desc")
+			}
+			return updateProject(cmd, id, func(p *model.Project) {
+				if cmd.Flags().Changed("name") {
+					p.Name = name
+				}
+				if cmd.Flags().Changed("desc") {
+					p.Description = desc
+				}
+			})
+		},
+	}
+	cmd.Flags().Int64Var(&id, "id", 0, "Project ID (required)")
+	cmd.MarkFlagRequired("id")
+	cmd.Flags().StringVar(&name, "name", "", "New name")
+	cmd.Flags().StringVar(&desc, "desc", "", "New description")
+	return cmd
+}
+
+func projectArchiveCmd(use, short string, archived bool) *cobra.Command {
+	var id int64
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkOutput(Output, "projects"); err != nil {
+				return err
+			}
+			return updateProject(cmd, id, func(p *model.Project) { p.Archived = archived })
+		},
+	}
+	cmd.Flags().Int64Var(&id, "id", 0, "Project ID (required)")
+	cmd.MarkFlagRequired("id")
+	return cmd
+}
+
+func projectDeleteCmd() *cobra.Command {
+	var id int64
+	cmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete a project; its tasks are kept, outside of any project",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, release, err := clientFor(cmd)
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), Timeout)
+			defer cancel()
+
+			if err := c.DeleteProject(ctx, id); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Deleted project %d\n", id)
+			return nil
+		},
+	}
+	cmd.Flags().Int64Var(&id, "id", 0, "Project ID (required)")
+	cmd.MarkFlagRequired("id")
+	return cmd
+}
+
+// updateProject reads a project, applies change and writes it back, since
+// UpdateProject replaces every editable field.
+func updateProject(cmd *cobra.Command, id int64, change func(*model.Project)) error {
+	c, release, err := clientFor(cmd)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), Timeout)
+	defer cancel()
+
+	p, err := c.GetProject(ctx, id)
+	if err != nil {
+		return err
+	}
+	change(&p)
+	updated, err := c.UpdateProject(ctx, p)
+	if err != nil {
+		return err
+	}
+	return printProject(cmd.OutOrStdout(), updated, nil)
+}
+
+func printProjects(w io.Writer, list []model.Project) error {
+	if list == nil {
+		list = []model.Project{}
+	}
+	switch Output {
+	case OutputJSON:
+		return writeJSON(w, list)
+	case OutputYAML:
+		return yaml.NewEncoder(w).Encode(list)
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tNAME\tDESCRIPTION\tSTATUS")
+	for _, p := range list {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", p.ID, oneLine(p.Name), oneLine(p.Description), projectStatus(p))
+	}
+	return tw.Flush()
+}
+
+// printProject writes one project, followed by its task counts if given.
+func printProject(w io.Writer, p model.Project, stats *model.ProjectStats) error {
+	detail := struct {
+		model.Project `yaml:",inline"`
+		Stats         *model.ProjectStats `json:"stats,omitempty" yaml:"stats,omitempty"`
+	}{p, stats}
+	switch Output {
+	case OutputJSON:
+		return writeJSON(w, detail)
+	case OutputYAML:
+		return yaml.NewEncoder(w).Encode(detail)
+	}
+	if stats == nil {
+		return printProjects(w, []model.Project{p})
+	}
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "ID:\t%d\n", p.ID)
+	fmt.Fprintf(tw, "Name:\t%s\n", oneLine(p.Name))
+	if p.Description != "" {
+		fmt.Fprintf(tw, "Description:\t%s\n", oneLine(p.Description))
+	}
+	fmt.Fprintf(tw, "Status:\t%s\n", projectStatus(p))
+	fmt.Fprintf(tw, "Tasks:\t%d open, %d completed, %d total\n", stats.Open, stats.Completed, stats.Total)
+	if stats.Total > 0 {
+		fmt.Fprintf(tw, "Progress:\t%d%%\n", stats.Completed*100/stats.Total)
+	}
+	if stats.Overdue > 0 {
+		fmt.Fprintf(tw, "Overdue:\t%d\n", stats.Overdue)
+	}
+	return tw.Flush()
+}
+
+func projectStatus(p model.Project) string {
+	if p.Archived {
+		return "archived"
+	}
+	return "active"
+}
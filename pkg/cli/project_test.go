@@ -0,0 +1,148 @@
+// pkg/cli/project_test.go
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"hearx/pkg/cli"
+	pb "hearx/proto"
+)
+
+// fakeProjectServer keeps projects in memory. It reads the task counts from
+// the fakeServer it is given.
+type fakeProjectServer struct {
+	pb.UnimplementedProjectServiceServer
+	projects []*pb.Project
+	tasks    *fakeServer
+}
+
+func (f *fakeProjectServer) CreateProject(_ context.Context, req *pb.CreateProjectRequest) (*pb.CreateProjectResponse, error) {
+	p := req.Project
+	p.Id = int64(len(f.projects) + 1)
+	f.projects = append(f.projects, p)
+	return &pb.CreateProjectResponse{Project: p}, nil
+}
+
+func (f *fakeProjectServer) GetProject(_ context.Context, req *pb.GetProjectRequest) (*pb.GetProjectResponse, error) {
+	if req.Id < 1 || int(req.Id) > len(f.projects) {
+		return nil, status.Error(codes.NotFound, "project not found")
+	}
+	return &pb.GetProjectResponse{Project: f.projects[req.Id-1]}, nil
+}
+
+func (f *fakeProjectServer) UpdateProject(ctx context.Context, req *pb.UpdateProjectRequest) (*pb.UpdateProjectResponse, error) {
+	if _, err := f.GetProject(ctx, &pb.GetProjectRequest{Id: req.Project.Id}); err != nil {
+		return nil, err
+	}
+	f.projects[req.Project.Id-1] = req.Project
+	return &pb.UpdateProjectResponse{Project: req.Project}, nil
+}
+
+func (f *fakeProjectServer) ListProjects(_ context.Context, req *pb.ListProjectsRequest) (*pb.ListProjectsResponse, error) {
+	resp := &pb.ListProjectsResponse{}
+	for _, p := range f.projects {
+		if req.IncludeArchived || !p.Archived {
+			resp.Projects = append(resp.Projects, p)
+		}
+	}
+	return resp, nil
+}
+
+func (f *fakeProjectServer) GetProjectStats(_ context.Context, req *pb.GetProjectStatsRequest) (*pb.GetProjectStatsResponse, error) {
+	s := &pb.ProjectStats{}
+	for _, t := range f.tasks.tasks {
+		if t.ProjectId != req.Id {
+			continue
+		}
+		s.Total++
+		if t.Completed {
+			s.Completed++
+		} else {
+			s.Open++
+		}
+	}
+	return &pb.GetProjectStatsResponse{Stats: s}, nil
+}
+
+var _ = Describe("project command", func() {
+	var (
+		env *shellEnv
+		out *bytes.Buffer
+		sh  *cli.Shell
+	)
+
+	BeforeEach(func() {
+		env = newShellEnv()
+		env.projects.tasks = env.fake
+		out, sh = env.out, env.sh
+	})
+
+	AfterEach(func() { env.close() })
+
+	It("should group tasks and report progress", func() {
+		script := `project create --name Home --desc "around the house"
+add --title Tap --project 1
+add --title Roof --project 1
+add --title Taxes
+move --id 3 --project 1
+complete --id 1
+`
+		Expect(sh.RunScript(strings.NewReader(script))).To(Succeed())
+
+		out.Reset()
+		Expect(sh.Exec("get --project 1 -o template --template '{{.ID}}'")).To(Succeed())
+		Expect(out.String()).To(Equal("1\n2\n3\n"))
+
+		out.Reset()
+		Expect(sh.Exec("project show --id 1")).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("Name:         Home"))
+		Expect(out.String()).To(ContainSubstring("2 open, 1 completed, 3 total"))
+		Expect(out.String()).To(ContainSubstring("Progress:     33%"))
+	})
+
+	It("should hide archived projects unless --all is given", func() {
+		script := "project create --name Home\nproject create --name Old\nproject archive --id 2\n"
+		Expect(sh.RunScript(strings.NewReader(script))).To(Succeed())
+
+		out.Reset()
+		Expect(sh.Exec("project list -o json")).To(Succeed())
+		Expect(out.String()).To(ContainSubstring(`"name": "Home"`))
+		Expect(out.String()).NotTo(ContainSubstring("Old"))
+
+		out.Reset()
+		Expect(sh.Exec("project list --all")).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("archived"))
+	})
+
+	It("should keep the fields update is not given", func() {
+		Expect(sh.Exec("project create --name Home --desc keep")).To(Succeed())
+		Expect(sh.Exec("project update --id 1 --name House")).To(Succeed())
+		Expect(env.projects.projects[0].Description).To(Equal("keep"))
+		Expect(env.projects.projects[0].Name).To(Equal("House"))
+
+		Expect(sh.Exec("project update --id 1")).To(MatchError(ContainSubstring("nothing to update")))
+	})
+
+	It("should refuse the task-only output formats", func() {
+		Expect(sh.Exec("project list -o csv")).To(MatchError(ContainSubstring("not supported for projects")))
+	})
+
+	It("should need --parent or --project to move a task", func() {
+		Expect(sh.Exec("add --title a")).To(Succeed())
+		Expect(sh.Exec("move --id 1")).To(MatchError(ContainSubstring("--parent, --project")))
+	})
+
+	It("should complete project subcommands and IDs", func() {
+		Expect(sh.Exec("project create --name Home")).To(Succeed())
+		Expect(sh.Complete("project sh")).To(Equal([]string{"project show"}))
+		Expect(sh.Complete("project show --id ")).To(Equal([]string{"project show --id 1"}))
+		Expect(sh.Complete("add --project ")).To(Equal([]string{"add --project 1"}))
+	})
+})
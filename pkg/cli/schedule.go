@@ -0,0 +1,87 @@
+// pkg/cli/schedule.go
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// dueLayouts are the forms --due accepts; all but RFC 3339 are local time.
+var dueLayouts = []string{"2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02"}
+
+// parseDue reads a --due value. An empty value is the zero time.
+func parseDue(s string) (time.Time, error) {
+	return parseTime("--due", s)
+}
+
+// parseTime reads a time given to flag in any of the dueLayouts or RFC 3339.
+// An empty value is the zero time.
+func parseTime(flag, s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range dueLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid %s %q (want YYYY-MM-DD, 'YYYY-MM-DD HH:MM' or RFC 3339)", flag, s)
+}
+
+// repeatCmd calls the SetRecurrence RPC
+func repeatCmd() *cobra.Command {
+	var (
+		id       int64
+		rule     string
+		due      string
+		noRepeat bool
+	)
+	cmd := &cobra.Command{
+		Use:   "repeat",
+		Short: "Make a task repeat, or stop it repeating with --clear",
+		Long: "Sets an RFC 5545 RRULE on a task: FREQ=DAILY|WEEKLY|MONTHLY|YEARLY with INTERVAL, COUNT, UNTIL,\n" +
+			"BYDAY (weekly) and BYMONTHDAY (monthly). Completing the task creates the next occurrence.",
+		Example: "  todo client repeat --id 3 --rule 'FREQ=WEEKLY;BYDAY=MO' --due 2026-10-26\n" +
+			"  todo client repeat --id 3 --rule 'FREQ=MONTHLY;BYMONTHDAY=-1'\n" +
+			"  todo client repeat --id 3 --clear",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := NewTaskPrinter(Output, Template)
+			if err != nil {
+				return err
+			}
+			if noRepeat == (rule != "") {
+				return fmt.Errorf("give exactly one of --rule and --clear")
+			}
+			dueAt, err := parseDue(due)
+			if err != nil {
+				return err
+			}
+			c, release, err := clientFor(cmd)
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), Timeout)
+			defer cancel()
+
+			updated, err := c.SetRecurrence(ctx, id, rule, dueAt)
+			if err != nil {
+				return err
+			}
+			return printer.PrintOne(cmd.OutOrStdout(), updated)
+		},
+	}
+	cmd.Flags().Int64Var(&id, "id", 0, "Task ID (required)")
+	cmd.MarkFlagRequired("id")
+	cmd.Flags().StringVar(&rule, "rule", "", "Recurrence rule, e.g. 'FREQ=WEEKLY;BYDAY=MO,TH'")
+	cmd.Flags().StringVar(&due, "due", "", "New due date, if the task has none or it should move")
+	cmd.Flags().BoolVar(&noRepeat, "clear", false, "Stop the task repeating")
+	return cmd
+}
@@ -0,0 +1,349 @@
+// pkg/cli/shell.go
+package cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-isatty"
+	"github.com/peterh/liner"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"hearx/pkg/client"
+)
+
+// errExit ends the shell loop without reporting a failure.
+var errExit = errors.New("exit")
+
+// shellCmd runs the client commands in a REPL over a single connection
+func shellCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "shell",
+		Short: "Interactive shell; reads commands from stdin when it is not a terminal",
+		Long: `Runs add, get, complete, export and import over one gRPC connection.
+
+Interactively it keeps a history and tab-completes commands, flags and task
+IDs. When stdin is not a terminal, each line is run as a command, blank lines
+and lines starting with # are skipped, and the first failure stops the script.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c, err := newClient()
+			if err != nil {
+				return err
+			}
+			defer c.Close()
+
+			sh := NewShell(c, cmd.OutOrStdout())
+			if isatty.IsTerminal(os.Stdin.Fd()) {
+				return sh.RunInteractive()
+			}
+			return sh.RunScript(cmd.InOrStdin())
+		},
+	}
+}
+
+// clientKey carries the shell's shared client in a command's context.
+type clientKey struct{}
+
+// clientFor returns the shell's client when cmd runs inside `client shell`,
+// or dials a new one. release closes only a client dialed here.
+func clientFor(cmd *cobra.Command) (c *client.Client, release func(), err error) {
+	if c, ok := cmd.Context().Value(clientKey{}).(*client.Client); ok {
+		return c, func() {}, nil
+	}
+	c, err = newClient()
+	if err != nil {
+		return nil, nil, err
+	}
+	return c, func() { c.Close() }, nil
+}
+
+// Shell executes client command lines against one shared Client.
+type Shell struct {
+	c   *client.Client
+	out io.Writer
+
+	// defaults for --output and --template, taken from the shell's own flags
+	output   string
+	template string
+
+	ids   []string // task IDs offered by Complete
+	stale bool
+}
+
+// NewShell returns a Shell that writes command output to out.
+func NewShell(c *client.Client, out io.Writer) *Shell {
+	return &Shell{c: c, out: out, output: Output, template: Template, stale: true}
+}
+
+// commands builds a fresh command tree for one line, so flag values never
+// leak from one line into the next.
+func (s *Shell) commands() *cobra.Command {
+	root := &cobra.Command{
+		Use:           "todo>",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	root.CompletionOptions.DisableDefaultCmd = true
+	root.PersistentFlags().StringVarP(&Output, "output", "o", s.output, "Output format: table|json|yaml|csv|template")
+	root.PersistentFlags().StringVar(&Template, "template", s.template, "Go template applied to each task with --output template")
+	root.AddCommand(addCmd(), getCmd(), completeCmd(), moveCmd(), assignCmd(), usersCmd(), deleteCmd(), trashCmd(), undeleteCmd(),
+		historyCmd(), restoreCmd(), dependCmd(), nextCmd(), repeatCmd(), projectCmd(), commentCmd(), exportCmd(), importCmd(), adminGroup())
+	root.AddCommand(&cobra.Command{
+		Use:     "exit",
+		Aliases: []string{"quit"},
+		Short:   "Leave the shell",
+		RunE:    func(*cobra.Command, []string) error { return errExit },
+	})
+	root.SetOut(s.out)
+	root.SetErr(s.out)
+	return root
+}
+
+// Exec runs a single command line such as `add --title "Buy eggs"`.
+func (s *Shell) Exec(line string) error {
+	args, err := splitArgs(line)
+	if err != nil || len(args) == 0 {
+		return err
+	}
+	root := s.commands()
+	root.SetArgs(args)
+	err = root.ExecuteContext(context.WithValue(context.Background(), clientKey{}, s.c))
+	s.stale = true
+	return err
+}
+
+// RunScript executes every line read from in and stops at the first error,
+// which is returned with its line number.
+func (s *Shell) RunScript(in io.Reader) error {
+	sc := bufio.NewScanner(in)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := s.Exec(line); errors.Is(err, errExit) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+	}
+	return sc.Err()
+}
+
+// RunInteractive prompts on the terminal until exit or Ctrl-D. Errors are
+// printed and the shell carries on.
+func (s *Shell) RunInteractive() error {
+	l := liner.NewLiner()
+	defer l.Close()
+	l.SetCtrlCAborts(true)
+	l.SetTabCompletionStyle(liner.TabPrints)
+	l.SetCompleter(s.Complete)
+
+	history := historyPath()
+	if f, err := os.Open(history); err == nil {
+		l.ReadHistory(f)
+		f.Close()
+	}
+	defer func() {
+		if history == "" {
+			return
+		}
+		os.MkdirAll(filepath.Dir(history), 0o700)
+		if f, err := os.OpenFile(history, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600); err == nil {
+			l.WriteHistory(f)
+			f.Close()
+		}
+	}()
+
+	for {
+		line, err := l.Prompt("todo> ")
+		switch {
+		case errors.Is(err, liner.ErrPromptAborted):
+			continue
+		case errors.Is(err, io.EOF):
+			fmt.Fprintln(s.out)
+			return nil
+		case err != nil:
+			return err
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		l.AppendHistory(line)
+		if err := s.Exec(line); errors.Is(err, errExit) {
+			return nil
+		} else if err != nil {
+			fmt.Fprintln(s.out, "error:", err)
+		}
+	}
+}
+
+// historyPath is where the interactive history persists between sessions.
+func historyPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "todo", "shell_history")
+}
+
+// Complete returns the completions of the last word of line: command names
+// first, then subcommand names or that command's flags, task IDs after --id,
+// --parent or --on, project IDs after --project or a project's --id, and
+// user names after --to or --assignee.
+func (s *Shell) Complete(line string) []string {
+	fields := strings.Fields(line)
+	word := ""
+	if len(fields) > 0 && !strings.HasSuffix(line, " ") {
+		word = fields[len(fields)-1]
+		fields = fields[:len(fields)-1]
+	}
+	head := line[:len(line)-len(word)]
+
+	var candidates []string
+	root := s.commands()
+	switch {
+	case len(fields) == 0:
+		for _, c := range root.Commands() {
+			if c.IsAvailableCommand() {
+				candidates = append(candidates, c.Name())
+			}
+		}
+	case fields[len(fields)-1] == "--project", fields[len(fields)-1] == "--id" && fields[0] == "project":
+		candidates = s.projectIDs()
+	case fields[len(fields)-1] == "--id", fields[len(fields)-1] == "--parent", fields[len(fields)-1] == "--on":
+		candidates = s.taskIDs()
+	case fields[len(fields)-1] == "--to", fields[len(fields)-1] == "--assignee":
+		candidates = s.userNames()
+	case strings.HasPrefix(word, "-"):
+		if c, _, err := root.Find(fields); err == nil && c != root {
+			c.InheritedFlags().VisitAll(func(f *pflag.Flag) { candidates = append(candidates, "--"+f.Name) })
+			c.LocalFlags().VisitAll(func(f *pflag.Flag) { candidates = append(candidates, "--"+f.Name) })
+		}
+	case len(fields) == 1:
+		if c, _, err := root.Find(fields); err == nil && c != root {
+			for _, sub := range c.Commands() {
+				if sub.IsAvailableCommand() {
+					candidates = append(candidates, sub.Name())
+				}
+			}
+		}
+	}
+
+	var out []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, word) {
+			out = append(out, head+c)
+		}
+	}
+	return out
+}
+
+// taskIDs lists the IDs of open tasks, refetched after any command ran.
+func (s *Shell) taskIDs() []string {
+	if !s.stale {
+		return s.ids
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	var ids []string
+	for t, err := range s.c.ListTasks(ctx) {
+		if err != nil {
+			return s.ids
+		}
+		if !t.Completed {
+			ids = append(ids, strconv.FormatInt(t.ID, 10))
+		}
+	}
+	s.ids, s.stale = ids, false
+	return ids
+}
+
+// projectIDs lists the IDs of active projects. There are few, so they are
+// fetched on every completion rather than cached.
+func (s *Shell) projectIDs() []string {
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	list, err := s.c.ListProjects(ctx, false)
+	if err != nil {
+		return nil
+	}
+	ids := make([]string, 0, len(list))
+	for _, p := range list {
+		ids = append(ids, strconv.FormatInt(p.ID, 10))
+	}
+	return ids
+}
+
+// userNames lists the registered users, fetched on every completion like
+// projectIDs.
+func (s *Shell) userNames() []string {
+	ctx, cancel := context.WithTimeout(context.Background(), Timeout)
+	defer cancel()
+	list, err := s.c.ListUsers(ctx)
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(list))
+	for _, u := range list {
+		names = append(names, u.Name)
+	}
+	return names
+}
+
+// splitArgs splits a command line into words like a POSIX shell would for
+// the simple cases: whitespace separates, quotes group and backslash escapes.
+func splitArgs(line string) ([]string, error) {
+	var (
+		args  []string
+		cur   strings.Builder
+		inArg bool
+		quote rune
+	)
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\' && quote != '\'':
+			if i+1 == len(runes) {
+				return nil, errors.New("trailing backslash")
+			}
+			i++
+			cur.WriteRune(runes[i])
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, cur.String())
+				cur.Reset()
+				inArg = false
+			}
+		default:
+			cur.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inArg {
+		args = append(args, cur.String())
+	}
+	return args, nil
+}
@@ -0,0 +1,350 @@
+// pkg/cli/shell_test.go
+package cli_test
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"hearx/pkg/cli"
+	"hearx/pkg/client"
+	pb "hearx/proto"
+)
+
+// fakeServer keeps tasks in memory and serves them in a single page, unless
+// pageSize is set.
+type fakeServer struct {
+	pb.UnimplementedTodoServiceServer
+	tasks   []*pb.Task
+	batches int
+
+	pageSize  int           // tasks per ListTasks page; 0 serves them all at once
+	listDelay time.Duration // how long each ListTasks call takes
+	listErr   error         // returned by ListTasks when set
+
+	deps    map[int64][]int64 // task ID to the IDs it waits for
+	deleted map[int64]bool
+	history map[int64][]*pb.TaskRevision // task ID to its revisions, oldest first
+}
+
+func (f *fakeServer) AddTask(_ context.Context, req *pb.AddTaskRequest) (*pb.AddTaskResponse, error) {
+	t := req.Task
+	t.Id = int64(len(f.tasks) + 1)
+	f.tasks = append(f.tasks, t)
+	return &pb.AddTaskResponse{Task: t}, nil
+}
+
+func (f *fakeServer) AddTasks(ctx context.Context, req *pb.AddTasksRequest) (*pb.AddTasksResponse, error) {
+	f.batches++
+	resp := &pb.AddTasksResponse{}
+	for _, t := range req.Tasks {
+		created, _ := f.AddTask(ctx, &pb.AddTaskRequest{Task: t})
+		resp.Tasks = append(resp.Tasks, created.Task)
+	}
+	return resp, nil
+}
+
+func (f *fakeServer) CompleteTask(_ context.Context, req *pb.CompleteTaskRequest) (*pb.CompleteTaskResponse, error) {
+	for _, t := range f.tasks {
+		if t.Id == req.Id {
+			t.Completed = true
+			return &pb.CompleteTaskResponse{Task: t}, nil
+		}
+	}
+	return nil, status.Error(codes.NotFound, "task not found")
+}
+
+func (f *fakeServer) MoveTask(_ context.Context, req *pb.MoveTaskRequest) (*pb.MoveTaskResponse, error) {
+	for _, t := range f.tasks {
+		if t.Id == req.Id {
+			t.ParentId = req.ParentId
+			return &pb.MoveTaskResponse{Task: t}, nil
+		}
+	}
+	return nil, status.Error(codes.NotFound, "task not found")
+}
+
+func (f *fakeServer) AddDependency(_ context.Context, req *pb.AddDependencyRequest) (*pb.AddDependencyResponse, error) {
+	if f.deps == nil {
+		f.deps = map[int64][]int64{}
+	}
+	f.deps[req.TaskId] = append(f.deps[req.TaskId], req.BlockerId)
+	return &pb.AddDependencyResponse{}, nil
+}
+
+func (f *fakeServer) ListBlockers(_ context.Context, req *pb.ListBlockersRequest) (*pb.ListBlockersResponse, error) {
+	resp := &pb.ListBlockersResponse{}
+	for _, id := range f.deps[req.TaskId] {
+		resp.Tasks = append(resp.Tasks, f.tasks[id-1])
+	}
+	return resp, nil
+}
+
+func (f *fakeServer) SetTaskProject(_ context.Context, req *pb.SetTaskProjectRequest) (*pb.SetTaskProjectResponse, error) {
+	for _, t := range f.tasks {
+		if t.Id == req.Id {
+			t.ProjectId = req.ProjectId
+			return &pb.SetTaskProjectResponse{Task: t}, nil
+		}
+	}
+	return nil, status.Error(codes.NotFound, "task not found")
+}
+
+func (f *fakeServer) ListTasks(ctx context.Context, req *pb.ListTasksRequest) (*pb.ListTasksResponse, error) {
+	select {
+	case <-time.After(f.listDelay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if f.listErr != nil {
+		return nil, f.listErr
+	}
+	resp := &pb.ListTasksResponse{}
+next:
+	for _, t := range f.tasks {
+		if t.ArchivedAt != nil && !req.IncludeArchived {
+			continue
+		}
+		if req.ProjectId != 0 && t.ProjectId != req.ProjectId {
+			continue
+		}
+		if req.Assignee != "" && t.Assignee != req.Assignee {
+			continue
+		}
+		if !req.Ready {
+			resp.Tasks = append(resp.Tasks, t)
+			continue
+		}
+		if t.Completed {
+			continue
+		}
+		for _, id := range f.deps[t.Id] {
+			if !f.tasks[id-1].Completed {
+				continue next
+			}
+		}
+		resp.Tasks = append(resp.Tasks, t)
+	}
+	if f.pageSize > 0 {
+		start, _ := strconv.Atoi(req.PageToken)
+		stop := min(start+f.pageSize, len(resp.Tasks))
+		if stop < len(resp.Tasks) {
+			resp.NextPageToken = strconv.Itoa(stop)
+		}
+		resp.Tasks = resp.Tasks[start:stop]
+	}
+	return resp, nil
+}
+
+// PlanTasks lists the open tasks in passes, each pass taking those whose
+// blockers are done or already listed.
+func (f *fakeServer) PlanTasks(context.Context, *pb.PlanTasksRequest) (*pb.PlanTasksResponse, error) {
+	resp := &pb.PlanTasksResponse{}
+	placed := map[int64]bool{}
+	for progress := true; progress; {
+		progress = false
+	next:
+		for _, t := range f.tasks {
+			if t.Completed || placed[t.Id] {
+				continue
+			}
+			for _, id := range f.deps[t.Id] {
+				if !f.tasks[id-1].Completed && !placed[id] {
+					continue next
+				}
+			}
+			placed[t.Id] = true
+			resp.Tasks = append(resp.Tasks, t)
+			progress = true
+		}
+	}
+	return resp, nil
+}
+
+// shellEnv is a Shell wired to a fakeServer over a real connection.
+type shellEnv struct {
+	fake     *fakeServer
+	projects *fakeProjectServer
+	comments *fakeCommentServer
+	admin    *fakeAdminServer
+	srv      *grpc.Server
+	c        *client.Client
+	out      *bytes.Buffer
+	sh       *cli.Shell
+}
+
+func newShellEnv() *shellEnv {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+	e := &shellEnv{
+		fake:     &fakeServer{},
+		projects: &fakeProjectServer{},
+		comments: &fakeCommentServer{},
+		admin:    &fakeAdminServer{},
+		srv:      grpc.NewServer(),
+		out:      &bytes.Buffer{},
+	}
+	pb.RegisterTodoServiceServer(e.srv, e.fake)
+	pb.RegisterProjectServiceServer(e.srv, e.projects)
+	pb.RegisterCommentServiceServer(e.srv, e.comments)
+	pb.RegisterAdminServiceServer(e.srv, e.admin)
+	go e.srv.Serve(lis)
+
+	e.c, err = client.New(client.WithAddresses(lis.Addr().String()))
+	Expect(err).NotTo(HaveOccurred())
+
+	cli.Timeout = 5 * time.Second
+	cli.Output = cli.OutputTable
+	e.sh = cli.NewShell(e.c, e.out)
+	return e
+}
+
+func (e *shellEnv) close() {
+	e.c.Close()
+	e.srv.Stop()
+}
+
+var _ = Describe("Shell", func() {
+	var (
+		env  *shellEnv
+		fake *fakeServer
+		out  *bytes.Buffer
+		sh   *cli.Shell
+	)
+
+	BeforeEach(func() {
+		env = newShellEnv()
+		fake, out, sh = env.fake, env.out, env.sh
+	})
+
+	AfterEach(func() { env.close() })
+
+	It("should run a piped script over one connection", func() {
+		script := `# set up
+add --title "Buy eggs" --desc 'a dozen'
+
+add --title Milk
+complete --id 1
+get -o csv
+`
+		Expect(sh.RunScript(strings.NewReader(script))).To(Succeed())
+		Expect(fake.tasks).To(HaveLen(2))
+		Expect(fake.tasks[0].Description).To(Equal("a dozen"))
+		Expect(out.String()).To(ContainSubstring("1,Buy eggs,a dozen,true,"))
+		Expect(out.String()).To(ContainSubstring("2,Milk,,false,"))
+	})
+
+	It("should not carry flags over to the next line", func() {
+		Expect(sh.Exec("add --title one -o json")).To(Succeed())
+		out.Reset()
+		Expect(sh.Exec("get")).To(Succeed())
+		Expect(out.String()).To(HavePrefix("ID"))
+	})
+
+	It("should stop the script at the first failure and report the line", func() {
+		script := "add --title a\ncomplete --id 42\nadd --title b\n"
+		err := sh.RunScript(strings.NewReader(script))
+		Expect(err).To(MatchError(ContainSubstring("line 2")))
+		Expect(cli.ExitCode(err)).To(Equal(int(codes.NotFound)))
+		Expect(fake.tasks).To(HaveLen(1))
+	})
+
+	It("should draw subtasks under their parents with get --tree", func() {
+		script := `add --title Groceries
+add --title Eggs --parent 1
+add --title Milk --parent 1
+add --title Cake
+move --id 4 --parent 2
+complete --id 3
+`
+		Expect(sh.RunScript(strings.NewReader(script))).To(Succeed())
+		out.Reset()
+		Expect(sh.Exec("get --tree")).To(Succeed())
+		Expect(out.String()).To(Equal("" +
+			"1  [open] Groceries\n" +
+			"├── 2  [open] Eggs\n" +
+			"│   └── 4  [open] Cake\n" +
+			"└── 3  [done] Milk\n"))
+	})
+
+	It("should hide archived tasks unless get --archived", func() {
+		Expect(sh.RunScript(strings.NewReader("add --title Old\nadd --title New\n"))).To(Succeed())
+		fake.tasks[0].Completed = true
+		fake.tasks[0].ArchivedAt = timestamppb.Now()
+
+		out.Reset()
+		Expect(sh.Exec("get --tree")).To(Succeed())
+		Expect(out.String()).To(Equal("2  [open] New\n"))
+
+		out.Reset()
+		Expect(sh.Exec("get --tree --archived")).To(Succeed())
+		Expect(out.String()).To(Equal("1  [archived] Old\n2  [open] New\n"))
+	})
+
+	It("should list what can be worked on next", func() {
+		script := `add --title Design
+add --title Build
+add --title Ship
+depend --id 2 --on 1
+depend --id 3 --on 1,2 -o csv
+`
+		Expect(sh.RunScript(strings.NewReader(script))).To(Succeed())
+		Expect(out.String()).To(ContainSubstring("1,Design,"))
+		Expect(out.String()).To(ContainSubstring("2,Build,"))
+
+		out.Reset()
+		Expect(sh.Exec("next -o template --template '{{.ID}}'")).To(Succeed())
+		Expect(out.String()).To(Equal("1\n"))
+
+		out.Reset()
+		Expect(sh.Exec("next --all -o template --template '{{.ID}}'")).To(Succeed())
+		Expect(out.String()).To(Equal("1\n2\n3\n"))
+
+		Expect(sh.Exec("complete --id 1")).To(Succeed())
+		out.Reset()
+		Expect(sh.Exec("next -o template --template '{{.ID}}'")).To(Succeed())
+		Expect(out.String()).To(Equal("2\n"))
+	})
+
+	It("should refuse --tree with another output format", func() {
+		Expect(sh.Exec("get --tree -o json")).To(MatchError(ContainSubstring("--tree")))
+	})
+
+	It("should stop at exit", func() {
+		Expect(sh.RunScript(strings.NewReader("exit\nadd --title never\n"))).To(Succeed())
+		Expect(fake.tasks).To(BeEmpty())
+	})
+
+	It("should reject unbalanced quotes", func() {
+		Expect(sh.Exec(`add --title "oops`)).To(MatchError(ContainSubstring("unterminated")))
+	})
+
+	Describe("Complete", func() {
+		It("should complete command names", func() {
+			Expect(sh.Complete("co")).To(Equal([]string{"comment", "complete"}))
+		})
+
+		It("should complete the command's flags", func() {
+			Expect(sh.Complete("add --t")).To(ConsistOf("add --template", "add --title"))
+		})
+
+		It("should complete open task IDs and refresh them after a command", func() {
+			Expect(sh.Exec("add --title a")).To(Succeed())
+			Expect(sh.Exec("add --title b")).To(Succeed())
+			Expect(sh.Complete("complete --id ")).To(Equal([]string{"complete --id 1", "complete --id 2"}))
+
+			Expect(sh.Exec("complete --id 1")).To(Succeed())
+			Expect(sh.Complete("complete --id ")).To(Equal([]string{"complete --id 2"}))
+		})
+	})
+})
@@ -0,0 +1,492 @@
+// pkg/cli/transfer.go
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"hearx/pkg/client"
+	"hearx/pkg/ical"
+	"hearx/pkg/model"
+)
+
+// File formats accepted by export and import.
+const (
+	FormatJSON     = "json"
+	FormatCSV      = "csv"
+	FormatMarkdown = "md"
+	FormatICS      = "ics"
+)
+
+// FormatFromPath guesses the file format from its extension, or "".
+func FormatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return FormatJSON
+	case ".csv":
+		return FormatCSV
+	case ".md", ".markdown":
+		return FormatMarkdown
+	case ".ics", ".ical":
+		return FormatICS
+	}
+	return ""
+}
+
+// TaskEncoder writes tasks one at a time, so an export never holds the
+// whole list in memory. Close finishes the document.
+type TaskEncoder struct {
+	w      io.Writer
+	format string
+	csv    *csv.Writer
+	ics    *ical.Encoder
+	n      int
+}
+
+// NewTaskEncoder starts a document in the given format on w.
+func NewTaskEncoder(w io.Writer, format string) (*TaskEncoder, error) {
+	e := &TaskEncoder{w: w, format: format}
+	switch format {
+	case FormatJSON:
+		_, err := io.WriteString(w, "[")
+		return e, err
+	case FormatCSV:
+		e.csv = csv.NewWriter(w)
+		return e, e.csv.Write(csvHeader)
+	case FormatMarkdown:
+		return e, nil
+	case FormatICS:
+		e.ics = ical.NewEncoder(w)
+		return e, nil
+	default:
+		return nil, fmt.Errorf("unknown format %q (want json|csv|md|ics)", format)
+	}
+}
+
+// Encode appends one task.
+func (e *TaskEncoder) Encode(t model.Task) error {
+	defer func() { e.n++ }()
+	switch e.format {
+	case FormatJSON:
+		b, err := json.Marshal(t)
+		if err != nil {
+			return err
+		}
+		sep := ",\n  "
+		if e.n == 0 {
+			sep = "\n  "
+		}
+		_, err = fmt.Fprintf(e.w, "%s%s", sep, b)
+		return err
+	case FormatCSV:
+		return e.csv.Write(csvRecord(t))
+	case FormatICS:
+		return e.ics.Encode(t)
+	default:
+		return writeChecklistItem(e.w, t)
+	}
+}
+
+// Close terminates the document and flushes buffered output.
+func (e *TaskEncoder) Close() error {
+	switch e.format {
+	case FormatJSON:
+		end := "\n]\n"
+		if e.n == 0 {
+			end = "]\n"
+		}
+		_, err := io.WriteString(e.w, end)
+		return err
+	case FormatCSV:
+		e.csv.Flush()
+		return e.csv.Error()
+	case FormatICS:
+		return e.ics.Close()
+	}
+	return nil
+}
+
+// writeChecklistItem renders a task as a GitHub-style checklist item, with
+// the description indented beneath it.
+func writeChecklistItem(w io.Writer, t model.Task) error {
+	box := " "
+	if t.Completed {
+		box = "x"
+	}
+	if _, err := fmt.Fprintf(w, "- [%s] %s\n", box, oneLine(t.Title)); err != nil {
+		return err
+	}
+	if t.Description == "" {
+		return nil
+	}
+	for _, line := range strings.Split(t.Description, "\n") {
+		if _, err := fmt.Fprintf(w, "  %s\n", line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeTasks reads tasks in the given format. Only title, description and
+// completion are kept: IDs and owners are assigned by the server.
+func DecodeTasks(r io.Reader, format string) ([]model.Task, error) {
+	var (
+		tasks []model.Task
+		err   error
+	)
+	switch format {
+	case FormatJSON:
+		err = json.NewDecoder(r).Decode(&tasks)
+	case FormatCSV:
+		tasks, err = decodeCSV(r)
+	case FormatMarkdown:
+		tasks, err = decodeChecklist(r)
+	case FormatICS:
+		tasks, err = ical.Decode(r)
+	default:
+		return nil, fmt.Errorf("unknown format %q (want json|csv|md|ics)", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := range tasks {
+		tasks[i] = model.Task{
+			Title:       strings.TrimSpace(tasks[i].Title),
+			Description: strings.TrimSpace(tasks[i].Description),
+			Completed:   tasks[i].Completed,
+		}
+	}
+	return tasks, nil
+}
+
+// decodeCSV reads columns by header name, so they may come in any order and
+// only title is required.
+func decodeCSV(r io.Reader) ([]model.Task, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	col := map[string]int{}
+	for i, h := range header {
+		col[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	if _, ok := col["title"]; !ok {
+		return nil, errors.New("csv: missing title column")
+	}
+	field := func(rec []string, name string) string {
+		if i, ok := col[name]; ok && i < len(rec) {
+			return rec[i]
+		}
+		return ""
+	}
+
+	var tasks []model.Task
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return tasks, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		t := model.Task{Title: field(rec, "title"), Description: field(rec, "description")}
+		if v := field(rec, "completed"); v != "" {
+			if t.Completed, err = strconv.ParseBool(v); err != nil {
+				line, _ := cr.FieldPos(col["completed"])
+				return nil, fmt.Errorf("csv line %d: completed: %w", line, err)
+			}
+		}
+		if v := field(rec, "due_at"); v != "" {
+			if t.DueAt, err = time.Parse(time.RFC3339, v); err != nil {
+				line, _ := cr.FieldPos(col["due_at"])
+				return nil, fmt.Errorf("csv line %d: due_at: %w", line, err)
+			}
+		}
+		t.Recurrence = field(rec, "recurrence")
+		tasks = append(tasks, t)
+	}
+}
+
+// checklistItem matches `- [ ] title` and `* [x] title`, at any indent.
+var checklistItem = regexp.MustCompile(`^\s*[-*+]\s+\[([ xX])\]\s+(.*)$`)
+
+// decodeChecklist turns each checklist item into a task. Indented lines that
+// follow an item become its description; anything else is ignored.
+func decodeChecklist(r io.Reader) ([]model.Task, error) {
+	var tasks []model.Task
+	var desc []string
+	flush := func() {
+		if len(tasks) > 0 {
+			tasks[len(tasks)-1].Description = strings.Join(desc, "\n")
+		}
+		desc = nil
+	}
+
+	sc := bufio.NewScanner(r)
+	inItem := false
+	for sc.Scan() {
+		line := sc.Text()
+		if m := checklistItem.FindStringSubmatch(line); m != nil {
+			flush()
+			tasks = append(tasks, model.Task{Title: m[2], Completed: m[1] != " "})
+			inItem = true
+			continue
+		}
+		indented := strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")
+		if inItem && indented && strings.TrimSpace(line) != "" {
+			desc = append(desc, strings.TrimSpace(line))
+			continue
+		}
+		inItem = false
+	}
+	flush()
+	return tasks, sc.Err()
+}
+
+// exportCmd streams every task to a file, replaced only on success, or stdout
+func exportCmd() *cobra.Command {
+	var format, file string
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export all tasks as json, csv, a markdown checklist or iCalendar",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format == "" {
+				format = FormatFromPath(file)
+			}
+			if format == "" {
+				format = FormatJSON
+			}
+			// reject a bad format before creating the file
+			if _, err := NewTaskEncoder(io.Discard, format); err != nil {
+				return err
+			}
+			c, release, err := clientFor(cmd)
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			if file == "-" {
+				return exportTasks(cmd.Context(), c, cmd.OutOrStdout(), format)
+			}
+			return writeFileAtomic(file, func(w io.Writer) error {
+				return exportTasks(cmd.Context(), c, w, format)
+			})
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "", "json|csv|md|ics (default from the --file extension, else json)")
+	cmd.Flags().StringVarP(&file, "file", "f", "-", "Write to this file instead of stdout")
+	return cmd
+}
+
+// importCmd creates tasks from a file in batches
+func importCmd() *cobra.Command {
+	var (
+		format    string
+		dryRun    bool
+		allowDups bool
+		batchSize int
+	)
+	cmd := &cobra.Command{
+		Use:   "import [file]",
+		Short: "Create tasks from a json, csv, markdown checklist or iCalendar file (- for stdin)",
+		Long: `Creates the tasks in the file through the batched AddTasks RPC.
+
+Tasks whose title matches an existing task, or an earlier one in the file,
+are skipped (case-insensitive) unless --allow-duplicates is given. Created
+tasks are printed in the --output format; a summary goes to stderr.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file := "-"
+			if len(args) == 1 {
+				file = args[0]
+			}
+			if format == "" {
+				format = FormatFromPath(file)
+			}
+			if format == "" {
+				return errors.New("cannot tell the format of " + file + "; pass --format json|csv|md|ics")
+			}
+			if batchSize < 1 || batchSize > client.MaxBatchSize {
+				return fmt.Errorf("--batch-size must be between 1 and %d", client.MaxBatchSize)
+			}
+			printer, err := NewTaskPrinter(Output, Template)
+			if err != nil {
+				return err
+			}
+
+			r := cmd.InOrStdin()
+			if file != "-" {
+				f, err := os.Open(file)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				r = f
+			}
+			tasks, err := DecodeTasks(r, format)
+			if err != nil {
+				return fmt.Errorf("read %s: %w", file, err)
+			}
+
+			c, release, err := clientFor(cmd)
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			skipped := 0
+			if !allowDups {
+				existing, err := existingTitles(cmd.Context(), c)
+				if err != nil {
+					return err
+				}
+				tasks, skipped = dropDuplicates(tasks, existing)
+			}
+
+			stderr := cmd.ErrOrStderr()
+			if dryRun {
+				fmt.Fprintf(stderr, "would create %d task(s), skipping %d duplicate(s)\n", len(tasks), skipped)
+				return printer.Print(cmd.OutOrStdout(), tasks)
+			}
+
+			var created []model.Task
+			for start := 0; start < len(tasks); start += batchSize {
+				batch := tasks[start:min(start+batchSize, len(tasks))]
+				ctx, cancel := context.WithTimeout(cmd.Context(), Timeout)
+				out, err := c.AddTasks(ctx, batch)
+				cancel()
+				if err != nil {
+					printer.Print(cmd.OutOrStdout(), created)
+					fmt.Fprintf(stderr, "created %d of %d task(s) before failing\n", len(created), len(tasks))
+					return err
+				}
+				created = append(created, out...)
+			}
+			fmt.Fprintf(stderr, "created %d task(s), skipped %d duplicate(s)\n", len(created), skipped)
+			return printer.Print(cmd.OutOrStdout(), created)
+		},
+	}
+	cmd.Flags().StringVar(&format, "format", "", "json|csv|md|ics (default from the file extension)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Show what would be created without creating anything")
+	cmd.Flags().BoolVar(&allowDups, "allow-duplicates", false, "Create tasks even if a task with the same title exists")
+	cmd.Flags().IntVar(&batchSize, "batch-size", 100, fmt.Sprintf("Tasks per AddTasks call (max %d)", client.MaxBatchSize))
+	return cmd
+}
+
+// existingTitles returns the normalised titles of every task on the server,
+// archived ones included.
+func existingTitles(ctx context.Context, c *client.Client) (map[string]bool, error) {
+	titles := map[string]bool{}
+	err := eachTask(ctx, c, client.Filter{IncludeArchived: true}, func(t model.Task) error {
+		titles[titleKey(t.Title)] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return titles, nil
+}
+
+// exportTasks encodes every task, archived ones included, to w.
+func exportTasks(ctx context.Context, c *client.Client, w io.Writer, format string) error {
+	bw := bufio.NewWriter(w)
+	enc, err := NewTaskEncoder(bw, format)
+	if err != nil {
+		return err
+	}
+	if err := eachTask(ctx, c, client.Filter{IncludeArchived: true}, enc.Encode); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// eachTask calls fn for every task matching f, page by page. Each page gets
+// its own --timeout, so a long listing is not cut off part way through.
+func eachTask(ctx context.Context, c *client.Client, f client.Filter, fn func(model.Task) error) error {
+	token := ""
+	for {
+		pageCtx, cancel := context.WithTimeout(ctx, Timeout)
+		page, next, err := c.ListTasksFilteredPage(pageCtx, f, token)
+		cancel()
+		if err != nil {
+			return err
+		}
+		for _, t := range page {
+			if err := fn(t); err != nil {
+				return err
+			}
+		}
+		if next == "" {
+			return nil
+		}
+		token = next
+	}
+}
+
+// writeFileAtomic calls write with a temporary file next to name and renames
+// it over name once everything is written, so a failed write leaves any
+// existing file untouched and no partial one behind.
+func writeFileAtomic(name string, write func(io.Writer) error) (err error) {
+	f, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			f.Close()
+			os.Remove(f.Name())
+		}
+	}()
+	if err := f.Chmod(0o644); err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), name)
+}
+
+// dropDuplicates removes tasks whose title is in seen or repeats an earlier
+// one, and reports how many it dropped. seen is updated in place.
+func dropDuplicates(tasks []model.Task, seen map[string]bool) ([]model.Task, int) {
+	kept := tasks[:0]
+	for _, t := range tasks {
+		k := titleKey(t.Title)
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		kept = append(kept, t)
+	}
+	return kept, len(tasks) - len(kept)
+}
+
+// titleKey normalises a title for duplicate detection.
+func titleKey(title string) string {
+	return strings.ToLower(strings.Join(strings.Fields(title), " "))
+}
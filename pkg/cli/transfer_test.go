@@ -0,0 +1,205 @@
+// pkg/cli/transfer_test.go
+package cli_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"hearx/pkg/cli"
+	"hearx/pkg/model"
+	pb "hearx/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var _ = Describe("TaskEncoder and DecodeTasks", func() {
+	tasks := []model.Task{
+		{ID: 1, Title: "Buy eggs", Description: "a dozen\nfree range", Owner: "alice"},
+		{ID: 2, Title: "Pay rent", Completed: true, Owner: "alice"},
+	}
+	// what survives a round trip: IDs and owners are the server's to assign
+	want := []model.Task{
+		{Title: "Buy eggs", Description: "a dozen\nfree range"},
+		{Title: "Pay rent", Completed: true},
+	}
+
+	encode := func(format string, tasks []model.Task) string {
+		var buf bytes.Buffer
+		enc, err := cli.NewTaskEncoder(&buf, format)
+		Expect(err).NotTo(HaveOccurred())
+		for _, t := range tasks {
+			Expect(enc.Encode(t)).To(Succeed())
+		}
+		Expect(enc.Close()).To(Succeed())
+		return buf.String()
+	}
+
+	for _, format := range []string{cli.FormatJSON, cli.FormatCSV, cli.FormatMarkdown, cli.FormatICS} {
+		format := format
+		It("should round-trip "+format, func() {
+			got, err := cli.DecodeTasks(strings.NewReader(encode(format, tasks)), format)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(got).To(Equal(want))
+		})
+	}
+
+	It("should write an empty JSON export as a valid array", func() {
+		Expect(encode(cli.FormatJSON, nil)).To(Equal("[]\n"))
+	})
+
+	It("should render GitHub-style checklists", func() {
+		Expect(encode(cli.FormatMarkdown, tasks)).To(Equal(
+			"- [ ] Buy eggs\n  a dozen\n  free range\n- [x] Pay rent\n"))
+	})
+
+	It("should read checklist items out of a larger markdown document", func() {
+		doc := `# Groceries
+
+Some intro text.
+
+- [ ] Milk
+  semi-skimmed
+* [X] Bread
+- plain bullet, not a task
+  - [ ] Nested item
+
+    not a description after a blank line
+`
+		got, err := cli.DecodeTasks(strings.NewReader(doc), cli.FormatMarkdown)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(got).To(Equal([]model.Task{
+			{Title: "Milk", Description: "semi-skimmed"},
+			{Title: "Bread", Completed: true},
+			{Title: "Nested item"},
+		}))
+	})
+
+	It("should read CSV columns by name in any order", func() {
+		doc := "completed,title\ntrue,Done thing\n,Open thing\n"
+		got, err := cli.DecodeTasks(strings.NewReader(doc), cli.FormatCSV)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(got).To(Equal([]model.Task{{Title: "Done thing", Completed: true}, {Title: "Open thing"}}))
+
+		_, err = cli.DecodeTasks(strings.NewReader("name\nx\n"), cli.FormatCSV)
+		Expect(err).To(MatchError(ContainSubstring("missing title")))
+	})
+
+	It("should guess formats from file names", func() {
+		Expect(cli.FormatFromPath("tasks.JSON")).To(Equal(cli.FormatJSON))
+		Expect(cli.FormatFromPath("todo.markdown")).To(Equal(cli.FormatMarkdown))
+		Expect(cli.FormatFromPath("calendar.ics")).To(Equal(cli.FormatICS))
+		Expect(cli.FormatFromPath("tasks.txt")).To(BeEmpty())
+	})
+})
+
+var _ = Describe("import and export commands", func() {
+	var (
+		env *shellEnv
+		dir string
+	)
+
+	BeforeEach(func() {
+		env = newShellEnv()
+		var err error
+		dir, err = os.MkdirTemp("", "transfer")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		env.close()
+		os.RemoveAll(dir)
+	})
+
+	writeFile := func(name, body string) string {
+		p := filepath.Join(dir, name)
+		Expect(os.WriteFile(p, []byte(body), 0o600)).To(Succeed())
+		return p
+	}
+
+	It("should import in batches and skip duplicate titles", func() {
+		env.fake.tasks = []*pb.Task{{Id: 1, Title: "Milk"}}
+		p := writeFile("list.md", "- [ ] milk\n- [ ] Eggs\n- [x] Bread\n- [ ] eggs \n- [ ] Jam\n")
+
+		Expect(env.sh.Exec("import --batch-size 2 " + p)).To(Succeed())
+		Expect(env.fake.batches).To(Equal(2))
+		Expect(env.fake.tasks).To(HaveLen(4))
+		Expect(env.fake.tasks[2].Completed).To(BeTrue())
+		Expect(env.out.String()).To(ContainSubstring("created 3 task(s), skipped 2 duplicate(s)"))
+	})
+
+	It("should change nothing on a dry run", func() {
+		p := writeFile("tasks.csv", "title,description\nA,first\nB,second\n")
+
+		Expect(env.sh.Exec("import --dry-run -o csv " + p)).To(Succeed())
+		Expect(env.fake.tasks).To(BeEmpty())
+		Expect(env.out.String()).To(ContainSubstring("would create 2 task(s)"))
+		Expect(env.out.String()).To(ContainSubstring("0,A,first,false,"))
+	})
+
+	It("should export every task to a file", func() {
+		env.fake.tasks = []*pb.Task{{Id: 1, Title: "A"}, {Id: 2, Title: "B", Completed: true}}
+		p := filepath.Join(dir, "out.md")
+
+		Expect(env.sh.Exec("export -f " + p)).To(Succeed())
+		b, err := os.ReadFile(p)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal("- [ ] A\n- [x] B\n"))
+	})
+
+	It("should export and skip archived tasks too", func() {
+		env.fake.tasks = []*pb.Task{{Id: 1, Title: "Old", Completed: true, ArchivedAt: timestamppb.Now()}, {Id: 2, Title: "New"}}
+		out := filepath.Join(dir, "out.md")
+
+		Expect(env.sh.Exec("export -f " + out)).To(Succeed())
+		b, err := os.ReadFile(out)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal("- [x] Old\n- [ ] New\n"))
+
+		Expect(env.sh.Exec("import " + writeFile("again.md", "- [ ] old\n- [ ] Newer\n"))).To(Succeed())
+		Expect(env.fake.tasks).To(HaveLen(3))
+		Expect(env.out.String()).To(ContainSubstring("created 1 task(s), skipped 1 duplicate(s)"))
+	})
+
+	It("should give each page of the export its own timeout", func() {
+		env.fake.tasks = []*pb.Task{{Id: 1, Title: "A"}, {Id: 2, Title: "B"}, {Id: 3, Title: "C"}}
+		env.fake.pageSize = 1
+		env.fake.listDelay = 80 * time.Millisecond
+		cli.Timeout = 150 * time.Millisecond // less than all three pages take
+		out := filepath.Join(dir, "out.md")
+
+		Expect(env.sh.Exec("export -f " + out)).To(Succeed())
+		b, err := os.ReadFile(out)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal("- [ ] A\n- [ ] B\n- [ ] C\n"))
+
+		Expect(env.sh.Exec("import " + writeFile("more.md", "- [ ] a\n- [ ] D\n"))).To(Succeed())
+		Expect(env.fake.tasks).To(HaveLen(4))
+	})
+
+	It("should leave an existing file alone when the export fails", func() {
+		out := writeFile("out.md", "- [ ] kept\n")
+		env.fake.tasks = []*pb.Task{{Id: 1, Title: "A"}, {Id: 2, Title: "B"}}
+		env.fake.listErr = status.Error(codes.Internal, "down")
+
+		Expect(env.sh.Exec("export -f " + out)).To(MatchError(ContainSubstring("down")))
+		b, err := os.ReadFile(out)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(b)).To(Equal("- [ ] kept\n"))
+		entries, err := os.ReadDir(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveLen(1))
+	})
+
+	It("should require a format it can recognise", func() {
+		p := writeFile("tasks.txt", "whatever")
+		Expect(env.sh.Exec("import " + p)).To(MatchError(ContainSubstring("--format")))
+	})
+})
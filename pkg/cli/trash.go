@@ -0,0 +1,78 @@
+// pkg/cli/trash.go
+package cli
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"hearx/pkg/model"
+)
+
+// trashCmd calls the ListDeletedTasks RPC, page by page
+func trashCmd() *cobra.Command {
+	var projectID int64
+	cmd := &cobra.Command{
+		Use:   "trash",
+		Short: "List deleted tasks that can still be undeleted",
+		Example: "  todo client trash\n" +
+			"  todo client undelete --id 5",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := NewTaskPrinter(Output, Template)
+			if err != nil {
+				return err
+			}
+			c, release, err := clientFor(cmd)
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), Timeout)
+			defer cancel()
+
+			var tasks []model.Task
+			for t, err := range c.ListDeletedTasks(ctx, projectID) {
+				if err != nil {
+					return err
+				}
+				tasks = append(tasks, t)
+			}
+			return printer.Print(cmd.OutOrStdout(), tasks)
+		},
+	}
+	cmd.Flags().Int64Var(&projectID, "project", 0, "Only tasks in this project")
+	return cmd
+}
+
+// undeleteCmd calls the UndeleteTask RPC
+func undeleteCmd() *cobra.Command {
+	var id int64
+	cmd := &cobra.Command{
+		Use:   "undelete",
+		Short: "Take a task out of the trash as it was when it was deleted",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			printer, err := NewTaskPrinter(Output, Template)
+			if err != nil {
+				return err
+			}
+			c, release, err := clientFor(cmd)
+			if err != nil {
+				return err
+			}
+			defer release()
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), Timeout)
+			defer cancel()
+
+			task, err := c.UndeleteTask(ctx, id)
+			if err != nil {
+				return err
+			}
+			return printer.PrintOne(cmd.OutOrStdout(), task)
+		},
+	}
+	cmd.Flags().Int64Var(&id, "id", 0, "Task ID (required)")
+	cmd.MarkFlagRequired("id")
+	return cmd
+}
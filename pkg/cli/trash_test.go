@@ -0,0 +1,58 @@
+// pkg/cli/trash_test.go
+package cli_test
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "hearx/proto"
+)
+
+func (f *fakeServer) ListDeletedTasks(context.Context, *pb.ListDeletedTasksRequest) (*pb.ListDeletedTasksResponse, error) {
+	resp := &pb.ListDeletedTasksResponse{}
+	for _, t := range f.tasks {
+		if f.deleted[t.Id] {
+			trashed := proto.Clone(t).(*pb.Task)
+			trashed.DeletedAt = timestamppb.Now()
+			resp.Tasks = append(resp.Tasks, trashed)
+		}
+	}
+	return resp, nil
+}
+
+func (f *fakeServer) UndeleteTask(_ context.Context, req *pb.UndeleteTaskRequest) (*pb.UndeleteTaskResponse, error) {
+	if !f.deleted[req.Id] {
+		return nil, status.Error(codes.NotFound, "task not found")
+	}
+	delete(f.deleted, req.Id)
+	return &pb.UndeleteTaskResponse{Task: f.tasks[req.Id-1]}, nil
+}
+
+var _ = Describe("trash", func() {
+	var env *shellEnv
+
+	BeforeEach(func() { env = newShellEnv() })
+	AfterEach(func() { env.close() })
+
+	It("should list deleted tasks and undelete one", func() {
+		script := "add --title keep\nadd --title toss\ndelete --id 2\n"
+		Expect(env.sh.RunScript(strings.NewReader(script))).To(Succeed())
+
+		env.out.Reset()
+		Expect(env.sh.Exec("trash")).To(Succeed())
+		Expect(env.out.String()).To(ContainSubstring("DELETED"))
+		Expect(env.out.String()).To(ContainSubstring("toss"))
+		Expect(env.out.String()).NotTo(ContainSubstring("keep"))
+
+		Expect(env.sh.Exec("undelete --id 2")).To(Succeed())
+		Expect(env.fake.deleted[2]).To(BeFalse())
+		Expect(env.sh.Exec("undelete --id 2")).To(MatchError(ContainSubstring("not found")))
+	})
+})
@@ -0,0 +1,97 @@
+// pkg/client/admin.go
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"iter"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"hearx/pkg/model"
+	pb "hearx/proto"
+)
+
+// ListAuditEvents iterates over the recorded task changes matching f, oldest
+// first, fetching pages as it goes. Only admins may call it. Iteration stops
+// after the first error, which is yielded with a zero event. f.AfterID and
+// f.Limit are ignored: paging is handled here.
+func (c *Client) ListAuditEvents(ctx context.Context, f model.AuditFilter) iter.Seq2[model.AuditEvent, error] {
+	return func(yield func(model.AuditEvent, error) bool) {
+		req := &pb.ListAuditEventsRequest{
+			PageSize: c.pageSize,
+			Actor:    f.Actor,
+			Action:   f.Action,
+			TaskId:   f.TaskID,
+			Since:    toTimestamp(f.Since),
+			Until:    toTimestamp(f.Until),
+		}
+		for {
+			res, err := c.admin.ListAuditEvents(ctx, req)
+			if err != nil {
+				yield(model.AuditEvent{}, err)
+				return
+			}
+			for _, e := range res.Events {
+				if !yield(AuditEventFromProto(e), nil) {
+					return
+				}
+			}
+			if res.NextPageToken == "" {
+				return
+			}
+			req.PageToken = res.NextPageToken
+		}
+	}
+}
+
+// ExportAuditEvents writes the events matching f to w as JSON lines, one
+// event per line, and returns how many it wrote.
+func (c *Client) ExportAuditEvents(ctx context.Context, w io.Writer, f model.AuditFilter) (int, error) {
+	enc := json.NewEncoder(w)
+	n := 0
+	for e, err := range c.ListAuditEvents(ctx, f) {
+		if err != nil {
+			return n, err
+		}
+		if err := enc.Encode(e); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// ArchiveTasks archives the tasks completed longer ago than olderThan and
+// returns how many it archived. Only admins may call it.
+func (c *Client) ArchiveTasks(ctx context.Context, olderThan time.Duration) (int64, error) {
+	res, err := c.admin.ArchiveTasks(ctx, &pb.ArchiveTasksRequest{OlderThan: durationpb.New(olderThan)})
+	if err != nil {
+		return 0, err
+	}
+	return res.Archived, nil
+}
+
+// AuditEventFromProto converts a wire audit event into the internal model.
+func AuditEventFromProto(e *pb.AuditEvent) model.AuditEvent {
+	out := model.AuditEvent{
+		ID:         e.GetId(),
+		OccurredAt: fromTimestamp(e.GetOccurredAt()),
+		Actor:      e.GetActor(),
+		Action:     e.GetAction(),
+		TaskID:     e.GetTaskId(),
+		RequestID:  e.GetRequestId(),
+		Detail:     e.GetDetail(),
+	}
+	if e.Before != nil {
+		before := FromProto(e.Before)
+		out.Before = &before
+	}
+	if e.After != nil {
+		after := FromProto(e.After)
+		out.After = &after
+	}
+	return out
+}
@@ -0,0 +1,403 @@
+// pkg/client/client.go
+package client
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"hearx/pkg/auth"
+	"hearx/pkg/model"
+	pb "hearx/proto"
+)
+
+// Client is a typed client for TodoService. It is safe for concurrent use
+// and should be reused: the underlying connection is kept open until Close.
+type Client struct {
+	conn     *grpc.ClientConn
+	api      pb.TodoServiceClient
+	projects pb.ProjectServiceClient
+	comments pb.CommentServiceClient
+	admin    pb.AdminServiceClient
+	pageSize int32
+}
+
+// New creates a Client. The connection is established lazily, bounded by
+// the context of each call.
+func New(opts ...Option) (*Client, error) {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	conn, err := grpc.NewClient(target, dialOptions(o)...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		conn:     conn,
+		api:      pb.NewTodoServiceClient(conn),
+		projects: pb.NewProjectServiceClient(conn),
+		comments: pb.NewCommentServiceClient(conn),
+		admin:    pb.NewAdminServiceClient(conn),
+		pageSize: o.pageSize,
+	}, nil
+}
+
+// Close releases the connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Raw exposes the generated client for RPCs this package does not wrap.
+func (c *Client) Raw() pb.TodoServiceClient {
+	return c.api
+}
+
+// AddTask creates a task and returns it with its server-assigned ID.
+func (c *Client) AddTask(ctx context.Context, t model.Task) (model.Task, error) {
+	res, err := c.api.AddTask(ctx, &pb.AddTaskRequest{Task: ToProto(t)})
+	if err != nil {
+		return model.Task{}, err
+	}
+	return FromProto(res.Task), nil
+}
+
+// MaxBatchSize is the most tasks the server accepts in one AddTasks call.
+const MaxBatchSize = 500
+
+// AddTasks creates tasks in one transaction and returns them, in order, with
+// their IDs. At most MaxBatchSize tasks may be sent at once.
+func (c *Client) AddTasks(ctx context.Context, tasks []model.Task) ([]model.Task, error) {
+	req := &pb.AddTasksRequest{Tasks: make([]*pb.Task, 0, len(tasks))}
+	for _, t := range tasks {
+		req.Tasks = append(req.Tasks, ToProto(t))
+	}
+	res, err := c.api.AddTasks(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	created := make([]model.Task, 0, len(res.Tasks))
+	for _, t := range res.Tasks {
+		created = append(created, FromProto(t))
+	}
+	return created, nil
+}
+
+// CompleteTask marks a task completed and returns its new state.
+func (c *Client) CompleteTask(ctx context.Context, id int64) (model.Task, error) {
+	done, _, err := c.CompleteTaskNext(ctx, id)
+	return done, err
+}
+
+// CompleteTaskNext is CompleteTask that also returns the next occurrence the
+// server created for a recurring task, or the zero Task if there is none.
+func (c *Client) CompleteTaskNext(ctx context.Context, id int64) (done, next model.Task, err error) {
+	res, err := c.api.CompleteTask(ctx, &pb.CompleteTaskRequest{Id: id})
+	if err != nil {
+		return model.Task{}, model.Task{}, err
+	}
+	if res.Next != nil {
+		next = FromProto(res.Next)
+	}
+	return FromProto(res.Task), next, nil
+}
+
+// SetRecurrence sets how a task repeats, as an RRULE value such as
+// "FREQ=WEEKLY;BYDAY=MO"; an empty rule stops it repeating. A zero due keeps
+// the task's due date.
+func (c *Client) SetRecurrence(ctx context.Context, id int64, rule string, due time.Time) (model.Task, error) {
+	res, err := c.api.SetRecurrence(ctx, &pb.SetRecurrenceRequest{Id: id, Recurrence: rule, DueAt: toTimestamp(due)})
+	if err != nil {
+		return model.Task{}, err
+	}
+	return FromProto(res.Task), nil
+}
+
+// UpdateTask changes the title and description of the task with t.ID.
+func (c *Client) UpdateTask(ctx context.Context, t model.Task) (model.Task, error) {
+	res, err := c.api.UpdateTask(ctx, &pb.UpdateTaskRequest{Task: ToProto(t)})
+	if err != nil {
+		return model.Task{}, err
+	}
+	return FromProto(res.Task), nil
+}
+
+// ListChildren returns the direct subtasks of a task.
+func (c *Client) ListChildren(ctx context.Context, parentID int64) ([]model.Task, error) {
+	res, err := c.api.ListChildren(ctx, &pb.ListChildrenRequest{ParentId: parentID})
+	if err != nil {
+		return nil, err
+	}
+	return fromProtos(res.Tasks), nil
+}
+
+// MoveTask puts a task under parentID, or at the top level when it is 0.
+func (c *Client) MoveTask(ctx context.Context, id, parentID int64) (model.Task, error) {
+	res, err := c.api.MoveTask(ctx, &pb.MoveTaskRequest{Id: id, ParentId: parentID})
+	if err != nil {
+		return model.Task{}, err
+	}
+	return FromProto(res.Task), nil
+}
+
+// ListTasksPage fetches a single page. Pass the returned token back to get
+// the next page; an empty token means there are no more.
+func (c *Client) ListTasksPage(ctx context.Context, pageToken string) ([]model.Task, string, error) {
+	return c.listPage(ctx, &pb.ListTasksRequest{PageToken: pageToken})
+}
+
+// ListTasks iterates over every task, fetching pages as it goes. Iteration
+// stops after the first error, which is yielded with a zero task.
+func (c *Client) ListTasks(ctx context.Context) iter.Seq2[model.Task, error] {
+	return c.ListTasksFiltered(ctx, Filter{})
+}
+
+// ListReadyTasks iterates over the open tasks whose blockers are all
+// completed: what can be worked on next.
+func (c *Client) ListReadyTasks(ctx context.Context) iter.Seq2[model.Task, error] {
+	return c.ListTasksFiltered(ctx, Filter{Ready: true})
+}
+
+// Filter narrows ListTasksFiltered; the zero Filter matches every task that
+// has not been archived.
+type Filter struct {
+	Ready           bool   // only open tasks whose blockers are all completed
+	ProjectID       int64  // only tasks in this project
+	Assignee        string // only tasks assigned to this user
+	AssignedToMe    bool   // only tasks assigned to the caller; not with Assignee
+	IncludeArchived bool   // also list archived tasks
+}
+
+// ListTasksFiltered is ListTasks restricted to the tasks matching f.
+func (c *Client) ListTasksFiltered(ctx context.Context, f Filter) iter.Seq2[model.Task, error] {
+	return func(yield func(model.Task, error) bool) {
+		token := ""
+		for {
+			page, next, err := c.ListTasksFilteredPage(ctx, f, token)
+			if err != nil {
+				yield(model.Task{}, err)
+				return
+			}
+			for _, t := range page {
+				if !yield(t, nil) {
+					return
+				}
+			}
+			if next == "" {
+				return
+			}
+			token = next
+		}
+	}
+}
+
+// ListTasksFilteredPage is ListTasksPage restricted to the tasks matching f.
+// Use it to bound each page by its own deadline.
+func (c *Client) ListTasksFilteredPage(ctx context.Context, f Filter, pageToken string) ([]model.Task, string, error) {
+	return c.listPage(ctx, &pb.ListTasksRequest{
+		PageToken:       pageToken,
+		Ready:           f.Ready,
+		ProjectId:       f.ProjectID,
+		Assignee:        f.Assignee,
+		AssignedToMe:    f.AssignedToMe,
+		IncludeArchived: f.IncludeArchived,
+	})
+}
+
+func (c *Client) listPage(ctx context.Context, req *pb.ListTasksRequest) ([]model.Task, string, error) {
+	req.PageSize = c.pageSize
+	res, err := c.api.ListTasks(ctx, req)
+	if err != nil {
+		return nil, "", err
+	}
+	return fromProtos(res.Tasks), res.NextPageToken, nil
+}
+
+// SetTaskProject moves a task into a project, or out of any when projectID is 0.
+func (c *Client) SetTaskProject(ctx context.Context, id, projectID int64) (model.Task, error) {
+	res, err := c.api.SetTaskProject(ctx, &pb.SetTaskProjectRequest{Id: id, ProjectId: projectID})
+	if err != nil {
+		return model.Task{}, err
+	}
+	return FromProto(res.Task), nil
+}
+
+// AssignTask makes a registered user responsible for a task.
+func (c *Client) AssignTask(ctx context.Context, id int64, assignee string) (model.Task, error) {
+	res, err := c.api.AssignTask(ctx, &pb.AssignTaskRequest{Id: id, Assignee: assignee})
+	if err != nil {
+		return model.Task{}, err
+	}
+	return FromProto(res.Task), nil
+}
+
+// UnassignTask leaves a task without an assignee.
+func (c *Client) UnassignTask(ctx context.Context, id int64) (model.Task, error) {
+	res, err := c.api.UnassignTask(ctx, &pb.UnassignTaskRequest{Id: id})
+	if err != nil {
+		return model.Task{}, err
+	}
+	return FromProto(res.Task), nil
+}
+
+// ListUsers returns the users tasks can be assigned to, by name.
+func (c *Client) ListUsers(ctx context.Context) ([]model.User, error) {
+	res, err := c.api.ListUsers(ctx, &pb.ListUsersRequest{})
+	if err != nil {
+		return nil, err
+	}
+	users := make([]model.User, 0, len(res.Users))
+	for _, u := range res.Users {
+		users = append(users, model.User{Name: u.GetName(), CreatedAt: fromTimestamp(u.GetCreatedAt())})
+	}
+	return users, nil
+}
+
+// AddDependency makes taskID wait for blockerID. Adding it twice is harmless.
+func (c *Client) AddDependency(ctx context.Context, taskID, blockerID int64) error {
+	_, err := c.api.AddDependency(ctx, &pb.AddDependencyRequest{TaskId: taskID, BlockerId: blockerID})
+	return err
+}
+
+// RemoveDependency drops a dependency added with AddDependency.
+func (c *Client) RemoveDependency(ctx context.Context, taskID, blockerID int64) error {
+	_, err := c.api.RemoveDependency(ctx, &pb.RemoveDependencyRequest{TaskId: taskID, BlockerId: blockerID})
+	return err
+}
+
+// ListBlockers returns the tasks taskID waits for, completed or not.
+func (c *Client) ListBlockers(ctx context.Context, taskID int64) ([]model.Task, error) {
+	res, err := c.api.ListBlockers(ctx, &pb.ListBlockersRequest{TaskId: taskID})
+	if err != nil {
+		return nil, err
+	}
+	return fromProtos(res.Tasks), nil
+}
+
+// PlanTasks returns every open task matching f in dependency order: each
+// after the tasks it waits for, those ready now first. f.Ready and
+// f.IncludeArchived do not apply.
+func (c *Client) PlanTasks(ctx context.Context, f Filter) ([]model.Task, error) {
+	res, err := c.api.PlanTasks(ctx, &pb.PlanTasksRequest{
+		ProjectId:    f.ProjectID,
+		Assignee:     f.Assignee,
+		AssignedToMe: f.AssignedToMe,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fromProtos(res.Tasks), nil
+}
+
+// FromProto converts a wire task into the internal model.
+func FromProto(t *pb.Task) model.Task {
+	return model.Task{
+		ID:          t.GetId(),
+		Title:       t.GetTitle(),
+		Description: t.GetDescription(),
+		Completed:   t.GetCompleted(),
+		Owner:       t.GetOwner(),
+		Assignee:    t.GetAssignee(),
+		ParentID:    t.GetParentId(),
+		ProjectID:   t.GetProjectId(),
+		DueAt:       fromTimestamp(t.GetDueAt()),
+		Recurrence:  t.GetRecurrence(),
+		CreatedAt:   fromTimestamp(t.GetCreatedAt()),
+		UpdatedAt:   fromTimestamp(t.GetUpdatedAt()),
+		DeletedAt:   fromTimestamp(t.GetDeletedAt()),
+		ArchivedAt:  fromTimestamp(t.GetArchivedAt()),
+	}
+}
+
+func fromProtos(ts []*pb.Task) []model.Task {
+	tasks := make([]model.Task, 0, len(ts))
+	for _, t := range ts {
+		tasks = append(tasks, FromProto(t))
+	}
+	return tasks
+}
+
+// ToProto converts an internal task into its wire form.
+func ToProto(t model.Task) *pb.Task {
+	return &pb.Task{
+		Id:          t.ID,
+		Title:       t.Title,
+		Description: t.Description,
+		Completed:   t.Completed,
+		Owner:       t.Owner,
+		Assignee:    t.Assignee,
+		ParentId:    t.ParentID,
+		ProjectId:   t.ProjectID,
+		DueAt:       toTimestamp(t.DueAt),
+		Recurrence:  t.Recurrence,
+		CreatedAt:   toTimestamp(t.CreatedAt),
+		UpdatedAt:   toTimestamp(t.UpdatedAt),
+		DeletedAt:   toTimestamp(t.DeletedAt),
+		ArchivedAt:  toTimestamp(t.ArchivedAt),
+	}
+}
+
+// fromTimestamp maps an unset timestamp to the zero time, not the epoch.
+func fromTimestamp(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// target is resolved by the manual resolver installed in dialOptions.
+const target = "todo:///todo"
+
+// dialOptions installs a manual resolver holding the fixed address list, so
+// the round-robin balancer can spread calls over every server.
+func dialOptions(o options) []grpc.DialOption {
+	r := manual.NewBuilderWithScheme("todo")
+	state := resolver.State{}
+	for _, a := range o.addrs {
+		state.Addresses = append(state.Addresses, resolver.Address{Addr: a})
+	}
+	r.InitialState(state)
+
+	creds := insecure.NewCredentials()
+	if o.tls != nil {
+		creds = credentials.NewTLS(o.tls)
+	}
+
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithResolvers(r),
+		grpc.WithDefaultServiceConfig(serviceConfig(o.maxAttempts)),
+		grpc.WithConnectParams(grpc.ConnectParams{
+			Backoff:           backoff.DefaultConfig,
+			MinConnectTimeout: 5 * time.Second,
+		}),
+	}
+	if o.keepalive > 0 {
+		opts = append(opts, grpc.WithKeepaliveParams(keepalive.ClientParameters{
+			Time:                o.keepalive,
+			Timeout:             10 * time.Second,
+			PermitWithoutStream: true,
+		}))
+	}
+	if o.token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(auth.StaticTokenCreds{Token: o.token}))
+	}
+	return opts
+}
@@ -0,0 +1,299 @@
+// pkg/client/client_test.go
+package client_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"hearx/pkg/client"
+	"hearx/pkg/model"
+	pb "hearx/proto"
+)
+
+// fakeServer serves a fixed set of tasks, two per page.
+type fakeServer struct {
+	pb.UnimplementedTodoServiceServer
+	tasks []*pb.Task
+	auth  string
+	sizes []int32 // page_size of each ListTasks call
+
+	unavailable int // calls left to fail with Unavailable
+	calls       map[string]int
+}
+
+// fail counts a call to method and fails it while unavailable is above zero.
+func (f *fakeServer) fail(method string) error {
+	if f.calls == nil {
+		f.calls = map[string]int{}
+	}
+	f.calls[method]++
+	if f.unavailable > 0 {
+		f.unavailable--
+		return status.Error(codes.Unavailable, "restarting")
+	}
+	return nil
+}
+
+func (f *fakeServer) CompleteTask(_ context.Context, req *pb.CompleteTaskRequest) (*pb.CompleteTaskResponse, error) {
+	if err := f.fail("CompleteTask"); err != nil {
+		return nil, err
+	}
+	return &pb.CompleteTaskResponse{Task: &pb.Task{Id: req.Id, Completed: true}}, nil
+}
+
+func (f *fakeServer) RestoreTaskRevision(_ context.Context, req *pb.RestoreTaskRevisionRequest) (*pb.RestoreTaskRevisionResponse, error) {
+	if err := f.fail("RestoreTaskRevision"); err != nil {
+		return nil, err
+	}
+	return &pb.RestoreTaskRevisionResponse{Task: &pb.Task{Id: req.TaskId}}, nil
+}
+
+func (f *fakeServer) AddTask(ctx context.Context, req *pb.AddTaskRequest) (*pb.AddTaskResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get("authorization"); len(v) > 0 {
+		f.auth = v[0]
+	}
+	t := req.Task
+	t.Id = int64(len(f.tasks) + 1)
+	f.tasks = append(f.tasks, t)
+	return &pb.AddTaskResponse{Task: t}, nil
+}
+
+func (f *fakeServer) ListTasks(_ context.Context, req *pb.ListTasksRequest) (*pb.ListTasksResponse, error) {
+	f.sizes = append(f.sizes, req.PageSize)
+	tasks := f.tasks
+	if req.ProjectId != 0 || req.Assignee != "" {
+		tasks = nil
+		for _, t := range f.tasks {
+			if (req.ProjectId == 0 || t.ProjectId == req.ProjectId) && (req.Assignee == "" || t.Assignee == req.Assignee) {
+				tasks = append(tasks, t)
+			}
+		}
+	}
+	start := 0
+	if req.PageToken != "" {
+		start, _ = strconv.Atoi(req.PageToken)
+	}
+	end := min(start+2, len(tasks))
+	resp := &pb.ListTasksResponse{Tasks: tasks[start:end]}
+	if end < len(tasks) {
+		resp.NextPageToken = strconv.Itoa(end)
+	}
+	return resp, nil
+}
+
+func (f *fakeServer) AssignTask(_ context.Context, req *pb.AssignTaskRequest) (*pb.AssignTaskResponse, error) {
+	t := f.tasks[req.Id-1]
+	t.Assignee = req.Assignee
+	return &pb.AssignTaskResponse{Task: t}, nil
+}
+
+// fakeAdminServer serves three audit events, two per page, for task 1 only,
+// and archives one task per day of the requested threshold.
+type fakeAdminServer struct {
+	pb.UnimplementedAdminServiceServer
+}
+
+func (f *fakeAdminServer) ListAuditEvents(_ context.Context, req *pb.ListAuditEventsRequest) (*pb.ListAuditEventsResponse, error) {
+	if req.TaskId != 1 {
+		return &pb.ListAuditEventsResponse{}, nil
+	}
+	events := []*pb.AuditEvent{
+		{Id: 1, Action: "add", TaskId: 1, After: &pb.Task{Id: 1, Title: "T"}},
+		{Id: 2, Action: "update", TaskId: 1, Before: &pb.Task{Id: 1, Title: "T"}, After: &pb.Task{Id: 1, Title: "U"}},
+		{Id: 3, Action: "complete", TaskId: 1},
+	}
+	if req.PageToken == "" {
+		return &pb.ListAuditEventsResponse{Events: events[:2], NextPageToken: "2"}, nil
+	}
+	return &pb.ListAuditEventsResponse{Events: events[2:]}, nil
+}
+
+func (f *fakeAdminServer) ArchiveTasks(_ context.Context, req *pb.ArchiveTasksRequest) (*pb.ArchiveTasksResponse, error) {
+	return &pb.ArchiveTasksResponse{Archived: int64(req.OlderThan.AsDuration() / (24 * time.Hour))}, nil
+}
+
+// fakeProjectServer keeps projects in memory.
+type fakeProjectServer struct {
+	pb.UnimplementedProjectServiceServer
+	projects []*pb.Project
+}
+
+func (f *fakeProjectServer) CreateProject(_ context.Context, req *pb.CreateProjectRequest) (*pb.CreateProjectResponse, error) {
+	p := req.Project
+	p.Id = int64(len(f.projects) + 1)
+	f.projects = append(f.projects, p)
+	return &pb.CreateProjectResponse{Project: p}, nil
+}
+
+func (f *fakeProjectServer) ListProjects(_ context.Context, req *pb.ListProjectsRequest) (*pb.ListProjectsResponse, error) {
+	resp := &pb.ListProjectsResponse{}
+	for _, p := range f.projects {
+		if req.IncludeArchived || !p.Archived {
+			resp.Projects = append(resp.Projects, p)
+		}
+	}
+	return resp, nil
+}
+
+var _ = Describe("Client", func() {
+	var (
+		fake     *fakeServer
+		projects *fakeProjectServer
+		srv      *grpc.Server
+		c        *client.Client
+		ctx      context.Context
+	)
+
+	BeforeEach(func() {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).NotTo(HaveOccurred())
+
+		fake = &fakeServer{}
+		srv = grpc.NewServer()
+		pb.RegisterTodoServiceServer(srv, fake)
+		projects = &fakeProjectServer{}
+		pb.RegisterProjectServiceServer(srv, projects)
+		pb.RegisterAdminServiceServer(srv, &fakeAdminServer{})
+		go srv.Serve(lis)
+
+		c, err = client.New(client.WithAddresses(lis.Addr().String()), client.WithToken("secret"))
+		Expect(err).NotTo(HaveOccurred())
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		c.Close()
+		srv.Stop()
+	})
+
+	It("should add a task and return it as a model.Task", func() {
+		created, err := c.AddTask(ctx, model.Task{Title: "T1", Description: "D1"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(created).To(Equal(model.Task{ID: 1, Title: "T1", Description: "D1"}))
+		Expect(fake.auth).To(Equal("Bearer secret"))
+	})
+
+	It("should iterate over every page", func() {
+		for i := 0; i < 5; i++ {
+			fake.tasks = append(fake.tasks, &pb.Task{Id: int64(i + 1), Title: "T"})
+		}
+
+		var ids []int64
+		for t, err := range c.ListTasks(ctx) {
+			Expect(err).NotTo(HaveOccurred())
+			ids = append(ids, t.ID)
+		}
+		Expect(ids).To(Equal([]int64{1, 2, 3, 4, 5}))
+		// a request without a page size would get every task at once
+		Expect(fake.sizes).To(Equal([]int32{client.DefaultPageSize, client.DefaultPageSize, client.DefaultPageSize}))
+	})
+
+	It("should stop early when the caller breaks out", func() {
+		for i := 0; i < 5; i++ {
+			fake.tasks = append(fake.tasks, &pb.Task{Id: int64(i + 1)})
+		}
+
+		n := 0
+		for range c.ListTasks(ctx) {
+			n++
+			if n == 3 {
+				break
+			}
+		}
+		Expect(n).To(Equal(3))
+	})
+
+	It("should list only the tasks of the given project", func() {
+		for i := 0; i < 5; i++ {
+			fake.tasks = append(fake.tasks, &pb.Task{Id: int64(i + 1), ProjectId: int64(i % 2)})
+		}
+
+		var ids []int64
+		for t, err := range c.ListTasksFiltered(ctx, client.Filter{ProjectID: 1}) {
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t.ProjectID).To(Equal(int64(1)))
+			ids = append(ids, t.ID)
+		}
+		Expect(ids).To(Equal([]int64{2, 4}))
+	})
+
+	It("should assign a task and find it by assignee", func() {
+		for i := 0; i < 3; i++ {
+			fake.tasks = append(fake.tasks, &pb.Task{Id: int64(i + 1)})
+		}
+
+		assigned, err := c.AssignTask(ctx, 2, "alice")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(assigned.Assignee).To(Equal("alice"))
+
+		var ids []int64
+		for t, err := range c.ListTasksFiltered(ctx, client.Filter{Assignee: "alice"}) {
+			Expect(err).NotTo(HaveOccurred())
+			ids = append(ids, t.ID)
+		}
+		Expect(ids).To(Equal([]int64{2}))
+	})
+
+	It("should retry an idempotent call on Unavailable", func() {
+		fake.unavailable = 2
+
+		done, err := c.CompleteTask(ctx, 4)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(done.Completed).To(BeTrue())
+		Expect(fake.calls["CompleteTask"]).To(Equal(3))
+	})
+
+	It("should not retry restoring a revision", func() {
+		fake.unavailable = 1
+
+		_, err := c.RestoreTaskRevision(ctx, 4, 2)
+		Expect(status.Code(err)).To(Equal(codes.Unavailable))
+		Expect(fake.calls["RestoreTaskRevision"]).To(Equal(1))
+	})
+
+	It("should export audit events as JSON lines across pages", func() {
+		var buf bytes.Buffer
+		n, err := c.ExportAuditEvents(ctx, &buf, model.AuditFilter{TaskID: 1})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(n).To(Equal(3))
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		Expect(lines).To(HaveLen(3))
+		var e model.AuditEvent
+		Expect(json.Unmarshal([]byte(lines[1]), &e)).To(Succeed())
+		Expect(e.Before.Title).To(Equal("T"))
+		Expect(e.After.Title).To(Equal("U"))
+	})
+
+	It("should send the archive threshold to the admin service", func() {
+		n, err := c.ArchiveTasks(ctx, 30*24*time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(n).To(Equal(int64(30)))
+	})
+
+	It("should create and list projects on the project service", func() {
+		_, err := c.CreateProject(ctx, model.Project{Name: "Home"})
+		Expect(err).NotTo(HaveOccurred())
+		archived, err := c.CreateProject(ctx, model.Project{Name: "Old", Archived: true})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(archived.ID).To(Equal(int64(2)))
+
+		list, err := c.ListProjects(ctx, false)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list).To(Equal([]model.Project{{ID: 1, Name: "Home"}}))
+	})
+})
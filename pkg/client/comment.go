@@ -0,0 +1,76 @@
+// pkg/client/comment.go
+package client
+
+import (
+	"context"
+	"iter"
+
+	"hearx/pkg/model"
+	pb "hearx/proto"
+)
+
+// AddComment comments on a task as the caller.
+func (c *Client) AddComment(ctx context.Context, taskID int64, body string) (model.Comment, error) {
+	res, err := c.comments.AddComment(ctx, &pb.AddCommentRequest{TaskId: taskID, Body: body})
+	if err != nil {
+		return model.Comment{}, err
+	}
+	return CommentFromProto(res.Comment), nil
+}
+
+// ListCommentsPage fetches a single page of a task's comments, oldest first.
+// Pass the returned token back to get the next page; an empty token means
+// there are no more.
+func (c *Client) ListCommentsPage(ctx context.Context, taskID int64, pageToken string) ([]model.Comment, string, error) {
+	res, err := c.comments.ListComments(ctx, &pb.ListCommentsRequest{TaskId: taskID, PageSize: c.pageSize, PageToken: pageToken})
+	if err != nil {
+		return nil, "", err
+	}
+	list := make([]model.Comment, 0, len(res.Comments))
+	for _, cm := range res.Comments {
+		list = append(list, CommentFromProto(cm))
+	}
+	return list, res.NextPageToken, nil
+}
+
+// ListComments iterates over every comment on a task, fetching pages as it
+// goes. Iteration stops after the first error, which is yielded with a zero
+// comment.
+func (c *Client) ListComments(ctx context.Context, taskID int64) iter.Seq2[model.Comment, error] {
+	return func(yield func(model.Comment, error) bool) {
+		token := ""
+		for {
+			page, next, err := c.ListCommentsPage(ctx, taskID, token)
+			if err != nil {
+				yield(model.Comment{}, err)
+				return
+			}
+			for _, cm := range page {
+				if !yield(cm, nil) {
+					return
+				}
+			}
+			if next == "" {
+				return
+			}
+			token = next
+		}
+	}
+}
+
+// DeleteComment deletes one of the caller's comments.
+func (c *Client) DeleteComment(ctx context.Context, id int64) error {
+	_, err := c.comments.DeleteComment(ctx, &pb.DeleteCommentRequest{Id: id})
+	return err
+}
+
+// CommentFromProto converts a wire comment into the internal model.
+func CommentFromProto(cm *pb.Comment) model.Comment {
+	return model.Comment{
+		ID:        cm.GetId(),
+		TaskID:    cm.GetTaskId(),
+		Author:    cm.GetAuthor(),
+		Body:      cm.GetBody(),
+		CreatedAt: fromTimestamp(cm.GetCreatedAt()),
+	}
+}
@@ -0,0 +1,100 @@
+// pkg/client/history.go
+package client
+
+import (
+	"context"
+	"iter"
+
+	"hearx/pkg/model"
+	pb "hearx/proto"
+)
+
+// DeleteTask soft-deletes a task. RestoreTaskRevision brings it back.
+func (c *Client) DeleteTask(ctx context.Context, id int64) error {
+	_, err := c.api.DeleteTask(ctx, &pb.DeleteTaskRequest{Id: id})
+	return err
+}
+
+// TaskHistory iterates over the revisions of a task, oldest first, fetching
+// pages as it goes. Iteration stops after the first error, which is yielded
+// with a zero revision.
+func (c *Client) TaskHistory(ctx context.Context, id int64) iter.Seq2[model.Revision, error] {
+	return func(yield func(model.Revision, error) bool) {
+		req := &pb.GetTaskHistoryRequest{TaskId: id, PageSize: c.pageSize}
+		for {
+			res, err := c.api.GetTaskHistory(ctx, req)
+			if err != nil {
+				yield(model.Revision{}, err)
+				return
+			}
+			for _, r := range res.Revisions {
+				if !yield(RevisionFromProto(r), nil) {
+					return
+				}
+			}
+			if res.NextPageToken == "" {
+				return
+			}
+			req.PageToken = res.NextPageToken
+		}
+	}
+}
+
+// RestoreTaskRevision writes a task back as it was at revision, undeleting
+// it if it was deleted, and returns it as restored.
+func (c *Client) RestoreTaskRevision(ctx context.Context, id, revision int64) (model.Task, error) {
+	res, err := c.api.RestoreTaskRevision(ctx, &pb.RestoreTaskRevisionRequest{TaskId: id, Revision: revision})
+	if err != nil {
+		return model.Task{}, err
+	}
+	return FromProto(res.Task), nil
+}
+
+// RevisionFromProto converts a wire task revision into the internal model.
+func RevisionFromProto(r *pb.TaskRevision) model.Revision {
+	out := model.Revision{
+		TaskID:    r.GetTaskId(),
+		Number:    r.GetRevision(),
+		Task:      FromProto(r.GetTask()),
+		Deleted:   r.GetDeleted(),
+		CreatedAt: fromTimestamp(r.GetCreatedAt()),
+	}
+	for _, c := range r.GetChanges() {
+		out.Changes = append(out.Changes, model.FieldChange{Field: c.GetField(), From: c.GetFrom(), To: c.GetTo()})
+	}
+	return out
+}
+
+// ListDeletedTasks iterates over the trash, in the project when projectID is
+// not 0, fetching pages as it goes. Iteration stops after the first error,
+// which is yielded with a zero task.
+func (c *Client) ListDeletedTasks(ctx context.Context, projectID int64) iter.Seq2[model.Task, error] {
+	return func(yield func(model.Task, error) bool) {
+		req := &pb.ListDeletedTasksRequest{PageSize: c.pageSize, ProjectId: projectID}
+		for {
+			res, err := c.api.ListDeletedTasks(ctx, req)
+			if err != nil {
+				yield(model.Task{}, err)
+				return
+			}
+			for _, t := range res.Tasks {
+				if !yield(FromProto(t), nil) {
+					return
+				}
+			}
+			if res.NextPageToken == "" {
+				return
+			}
+			req.PageToken = res.NextPageToken
+		}
+	}
+}
+
+// UndeleteTask takes a task out of the trash as it was when it was deleted.
+func (c *Client) UndeleteTask(ctx context.Context, id int64) (model.Task, error) {
+	res, err := c.api.UndeleteTask(ctx, &pb.UndeleteTaskRequest{Id: id})
+	if err != nil {
+		return model.Task{}, err
+	}
+	return FromProto(res.Task), nil
+}
@@ -0,0 +1,73 @@
+// pkg/client/options.go
+package client
+
+import (
+	"crypto/tls"
+	"time"
+)
+
+// Option configures a Client.
+type Option func(*options)
+
+type options struct {
+	addrs       []string
+	token       string
+	tls         *tls.Config
+	maxAttempts int
+	pageSize    int32
+	keepalive   time.Duration
+}
+
+// DefaultPageSize is how many rows the list calls ask for per page.
+const DefaultPageSize = 100
+
+func defaultOptions() options {
+	return options{
+		addrs:       []string{"localhost:50051"},
+		maxAttempts: 5,
+		pageSize:    DefaultPageSize,
+		keepalive:   30 * time.Second,
+	}
+}
+
+// WithAddresses sets the servers to balance across, as host:port.
+func WithAddresses(addrs ...string) Option {
+	return func(o *options) {
+		if len(addrs) > 0 {
+			o.addrs = addrs
+		}
+	}
+}
+
+// WithToken authenticates every call with the given Bearer token.
+func WithToken(token string) Option {
+	return func(o *options) { o.token = token }
+}
+
+// WithTLS connects over TLS; without it the connection is plaintext.
+func WithTLS(cfg *tls.Config) Option {
+	return func(o *options) { o.tls = cfg }
+}
+
+// WithRetries sets how many attempts idempotent calls get when a server is
+// unavailable (gRPC caps this at 5); 1 disables retries.
+func WithRetries(maxAttempts int) Option {
+	return func(o *options) { o.maxAttempts = maxAttempts }
+}
+
+// WithPageSize sets the page size the list calls request; n < 1 keeps
+// DefaultPageSize. The client always pages, even though ListTasks returns
+// every task in one response to a request without a page size.
+func WithPageSize(n int32) Option {
+	return func(o *options) {
+		if n > 0 {
+			o.pageSize = n
+		}
+	}
+}
+
+// WithKeepalive sets the keepalive ping interval; 0 disables pings. The
+// server rejects pings more frequent than every 10s.
+func WithKeepalive(d time.Duration) Option {
+	return func(o *options) { o.keepalive = d }
+}
@@ -0,0 +1,98 @@
+// pkg/client/project.go
+package client
+
+import (
+	"context"
+
+	"hearx/pkg/model"
+	pb "hearx/proto"
+)
+
+// CreateProject creates a project owned by the caller; the name must be unused.
+func (c *Client) CreateProject(ctx context.Context, p model.Project) (model.Project, error) {
+	res, err := c.projects.CreateProject(ctx, &pb.CreateProjectRequest{Project: ProjectToProto(p)})
+	if err != nil {
+		return model.Project{}, err
+	}
+	return ProjectFromProto(res.Project), nil
+}
+
+// GetProject returns a project, archived or not.
+func (c *Client) GetProject(ctx context.Context, id int64) (model.Project, error) {
+	res, err := c.projects.GetProject(ctx, &pb.GetProjectRequest{Id: id})
+	if err != nil {
+		return model.Project{}, err
+	}
+	return ProjectFromProto(res.Project), nil
+}
+
+// UpdateProject replaces the name, description and archived flag of the
+// project with p.ID.
+func (c *Client) UpdateProject(ctx context.Context, p model.Project) (model.Project, error) {
+	res, err := c.projects.UpdateProject(ctx, &pb.UpdateProjectRequest{Project: ProjectToProto(p)})
+	if err != nil {
+		return model.Project{}, err
+	}
+	return ProjectFromProto(res.Project), nil
+}
+
+// ListProjects returns the projects by name, leaving archived ones out
+// unless includeArchived is set.
+func (c *Client) ListProjects(ctx context.Context, includeArchived bool) ([]model.Project, error) {
+	res, err := c.projects.ListProjects(ctx, &pb.ListProjectsRequest{IncludeArchived: includeArchived})
+	if err != nil {
+		return nil, err
+	}
+	list := make([]model.Project, 0, len(res.Projects))
+	for _, p := range res.Projects {
+		list = append(list, ProjectFromProto(p))
+	}
+	return list, nil
+}
+
+// DeleteProject deletes a project. Its tasks are kept, outside of any project.
+func (c *Client) DeleteProject(ctx context.Context, id int64) error {
+	_, err := c.projects.DeleteProject(ctx, &pb.DeleteProjectRequest{Id: id})
+	return err
+}
+
+// ProjectStats counts a project's tasks by state.
+func (c *Client) ProjectStats(ctx context.Context, id int64) (model.ProjectStats, error) {
+	res, err := c.projects.GetProjectStats(ctx, &pb.GetProjectStatsRequest{Id: id})
+	if err != nil {
+		return model.ProjectStats{}, err
+	}
+	s := res.GetStats()
+	return model.ProjectStats{
+		Total:     s.GetTotal(),
+		Completed: s.GetCompleted(),
+		Open:      s.GetOpen(),
+		Overdue:   s.GetOverdue(),
+	}, nil
+}
+
+// ProjectFromProto converts a wire project into the internal model.
+func ProjectFromProto(p *pb.Project) model.Project {
+	return model.Project{
+		ID:          p.GetId(),
+		Name:        p.GetName(),
+		Description: p.GetDescription(),
+		Archived:    p.GetArchived(),
+		Owner:       p.GetOwner(),
+		CreatedAt:   fromTimestamp(p.GetCreatedAt()),
+		UpdatedAt:   fromTimestamp(p.GetUpdatedAt()),
+	}
+}
+
+// ProjectToProto converts an internal project into its wire form.
+func ProjectToProto(p model.Project) *pb.Project {
+	return &pb.Project{
+		Id:          p.ID,
+		Name:        p.Name,
+		Description: p.Description,
+		Archived:    p.Archived,
+		Owner:       p.Owner,
+		CreatedAt:   toTimestamp(p.CreatedAt),
+		UpdatedAt:   toTimestamp(p.UpdatedAt),
+	}
+}
@@ -0,0 +1,75 @@
+// pkg/client/serviceconfig.go
+package client
+
+import (
+	"encoding/json"
+)
+
+// idempotentMethods may be retried safely. AddTask is not among them since
+// a retry could create a duplicate, nor DeleteTask and UndeleteTask, whose
+// retry would fail once the first attempt had succeeded, nor
+// RestoreTaskRevision, whose retry would record a second restore and undo
+// any change made in between.
+var idempotentMethods = []string{"ListTasks", "CompleteTask", "UpdateTask", "ListChildren", "MoveTask", "AddDependency", "ListBlockers", "PlanTasks", "SetRecurrence", "SetTaskProject", "AssignTask", "UnassignTask", "ListUsers", "GetTaskHistory", "ListDeletedTasks"}
+
+// idempotentProjectMethods are the ProjectService methods that may be
+// retried; CreateProject and DeleteProject would fail on a second attempt.
+var idempotentProjectMethods = []string{"GetProject", "UpdateProject", "ListProjects", "GetProjectStats"}
+
+// idempotentCommentMethods are the CommentService methods that may be
+// retried; a retried AddComment could post twice.
+var idempotentCommentMethods = []string{"ListComments"}
+
+// idempotentAdminMethods are the AdminService methods that may be retried.
+var idempotentAdminMethods = []string{"ListAuditEvents", "ArchiveTasks"}
+
+// serviceConfig balances round-robin across every server address and
+// retries the idempotent methods on UNAVAILABLE, e.g. while a server restarts.
+func serviceConfig(maxAttempts int) string {
+	type name struct {
+		Service string `json:"service"`
+		Method  string `json:"method"`
+	}
+	type retryPolicy struct {
+		MaxAttempts          int      `json:"maxAttempts"`
+		InitialBackoff       string   `json:"initialBackoff"`
+		MaxBackoff           string   `json:"maxBackoff"`
+		BackoffMultiplier    float64  `json:"backoffMultiplier"`
+		RetryableStatusCodes []string `json:"retryableStatusCodes"`
+	}
+	type methodConfig struct {
+		Name         []name       `json:"name"`
+		WaitForReady bool         `json:"waitForReady"`
+		RetryPolicy  *retryPolicy `json:"retryPolicy,omitempty"`
+	}
+
+	mc := methodConfig{WaitForReady: true}
+	for _, m := range idempotentMethods {
+		mc.Name = append(mc.Name, name{Service: "todo.TodoService", Method: m})
+	}
+	for _, m := range idempotentProjectMethods {
+		mc.Name = append(mc.Name, name{Service: "todo.ProjectService", Method: m})
+	}
+	for _, m := range idempotentCommentMethods {
+		mc.Name = append(mc.Name, name{Service: "todo.CommentService", Method: m})
+	}
+	for _, m := range idempotentAdminMethods {
+		mc.Name = append(mc.Name, name{Service: "todo.AdminService", Method: m})
+	}
+	// gRPC requires at least 2 attempts in a retry policy
+	if maxAttempts >= 2 {
+		mc.RetryPolicy = &retryPolicy{
+			MaxAttempts:          maxAttempts,
+			InitialBackoff:       "0.1s",
+			MaxBackoff:           "2s",
+			BackoffMultiplier:    2,
+			RetryableStatusCodes: []string{"UNAVAILABLE"},
+		}
+	}
+
+	b, _ := json.Marshal(map[string]any{
+		"loadBalancingConfig": []map[string]any{{"round_robin": map[string]any{}}},
+		"methodConfig":        []methodConfig{mc},
+	})
+	return string(b)
+}
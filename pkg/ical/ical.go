@@ -0,0 +1,295 @@
+// pkg/ical/ical.go
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"hearx/pkg/model"
+)
+
+// ContentType is the MIME type of an iCalendar document.
+const ContentType = "text/calendar; charset=utf-8"
+
+const (
+	prodID      = "-//hearx//todo//EN"
+	uidDomain   = "hearx"
+	maxLineLen  = 75 // octets, excluding the CRLF (RFC 5545 §3.1)
+	utcDateTime = "20060102T150405Z"
+)
+
+// Encoder writes tasks as VTODOs of one VCALENDAR, streaming them so a
+// feed never holds every task in memory. Close ends the calendar.
+type Encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+// NewEncoder writes the calendar header to w.
+func NewEncoder(w io.Writer) *Encoder {
+	e := &Encoder{w: bufio.NewWriter(w)}
+	e.line("BEGIN:VCALENDAR")
+	e.line("VERSION:2.0")
+	e.line("PRODID:" + prodID)
+	e.line("CALSCALE:GREGORIAN")
+	return e
+}
+
+// Encode writes one task. Its UID derives from the task ID, so calendar apps
+// update the same item when the feed is refreshed.
+func (e *Encoder) Encode(t model.Task) error {
+	stamp := t.UpdatedAt
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+	e.line("BEGIN:VTODO")
+	e.line(fmt.Sprintf("UID:task-%d@%s", t.ID, uidDomain))
+	e.line("DTSTAMP:" + formatTime(stamp))
+	if !t.CreatedAt.IsZero() {
+		e.line("CREATED:" + formatTime(t.CreatedAt))
+	}
+	if !t.UpdatedAt.IsZero() {
+		e.line("LAST-MODIFIED:" + formatTime(t.UpdatedAt))
+	}
+	e.line("SUMMARY:" + escape(t.Title))
+	if t.Description != "" {
+		e.line("DESCRIPTION:" + escape(t.Description))
+	}
+	if !t.DueAt.IsZero() {
+		e.line("DUE:" + formatTime(t.DueAt))
+	}
+	if t.Recurrence != "" {
+		e.line("RRULE:" + t.Recurrence)
+	}
+	if t.Completed {
+		e.line("STATUS:COMPLETED")
+		// the last update is when the task was completed, as far as we know
+		if !t.UpdatedAt.IsZero() {
+			e.line("COMPLETED:" + formatTime(t.UpdatedAt))
+		}
+	} else {
+		e.line("STATUS:NEEDS-ACTION")
+	}
+	e.line("END:VTODO")
+	return e.err
+}
+
+// Close ends the calendar and flushes it.
+func (e *Encoder) Close() error {
+	e.line("END:VCALENDAR")
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+// line writes a content line, folded to 75 octets without splitting a
+// UTF-8 sequence.
+func (e *Encoder) line(s string) {
+	if e.err != nil {
+		return
+	}
+	limit := maxLineLen
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		if _, e.err = e.w.WriteString(s[:cut] + "\r\n "); e.err != nil {
+			return
+		}
+		s = s[cut:]
+		limit = maxLineLen - 1 // the leading space counts
+	}
+	_, e.err = e.w.WriteString(s + "\r\n")
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(utcDateTime)
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// escape encodes a TEXT value (RFC 5545 §3.3.11).
+func escape(s string) string {
+	return escaper.Replace(s)
+}
+
+// unescape decodes a TEXT value.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// Decode reads every VTODO in r, in any calendar it contains. Other
+// components (VEVENT, VTIMEZONE, VALARM inside a VTODO) are skipped.
+func Decode(r io.Reader) ([]model.Task, error) {
+	var (
+		tasks []model.Task
+		cur   *model.Task
+		depth int // components open inside the current VTODO
+	)
+	err := eachLine(r, func(n int, line string) error {
+		name, params, value, err := parseLine(line)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", n, err)
+		}
+		switch {
+		case name == "BEGIN" && cur == nil && strings.EqualFold(value, "VTODO"):
+			cur = &model.Task{}
+			return nil
+		case name == "BEGIN" && cur != nil:
+			depth++
+			return nil
+		case name == "END" && cur != nil && depth > 0:
+			depth--
+			return nil
+		case name == "END" && cur != nil:
+			tasks = append(tasks, *cur)
+			cur = nil
+			return nil
+		case cur == nil || depth > 0:
+			return nil
+		}
+
+		switch name {
+		case "SUMMARY":
+			cur.Title = unescape(value)
+		case "DESCRIPTION":
+			cur.Description = unescape(value)
+		case "RRULE":
+			cur.Recurrence = value
+		case "STATUS":
+			// a COMPLETED date marks the task done whatever the status says,
+			// and in whichever order the two come
+			cur.Completed = cur.Completed || strings.EqualFold(value, "COMPLETED")
+		case "COMPLETED":
+			cur.Completed = true
+		case "CREATED", "LAST-MODIFIED", "DUE":
+			t, err := parseTime(value, params["TZID"])
+			if err != nil {
+				return fmt.Errorf("line %d: %s: %w", n, name, err)
+			}
+			switch name {
+			case "CREATED":
+				cur.CreatedAt = t
+			case "LAST-MODIFIED":
+				cur.UpdatedAt = t
+			default:
+				cur.DueAt = t
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if cur != nil {
+		return nil, errors.New("unterminated VTODO")
+	}
+	return tasks, nil
+}
+
+// eachLine calls fn with every unfolded content line and its starting line
+// number. Both CRLF and bare LF line endings are accepted.
+func eachLine(r io.Reader, fn func(n int, line string) error) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	var (
+		cur   strings.Builder
+		start int
+	)
+	flush := func() error {
+		if cur.Len() == 0 {
+			return nil
+		}
+		defer cur.Reset()
+		return fn(start, cur.String())
+	}
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSuffix(sc.Text(), "\r")
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			cur.WriteString(line[1:])
+			continue
+		}
+		if err := flush(); err != nil {
+			return err
+		}
+		start = n
+		cur.WriteString(line)
+	}
+	if err := sc.Err(); err != nil {
+		return err
+	}
+	return flush()
+}
+
+// parseLine splits `NAME;PARAM=x;PARAM="y:z":value`. Names and parameter
+// names are upper-cased.
+func parseLine(line string) (name string, params map[string]string, value string, err error) {
+	inQuote := false
+	colon := -1
+	for i := 0; i < len(line) && colon < 0; i++ {
+		switch line[i] {
+		case '"':
+			inQuote = !inQuote
+		case ':':
+			if !inQuote {
+				colon = i
+			}
+		}
+	}
+	if colon < 0 {
+		return "", nil, "", fmt.Errorf("malformed content line %q", line)
+	}
+	head, value := line[:colon], line[colon+1:]
+	parts := strings.Split(head, ";")
+	name = strings.ToUpper(parts[0])
+	params = map[string]string{}
+	for _, p := range parts[1:] {
+		k, v, _ := strings.Cut(p, "=")
+		params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+	return name, params, value, nil
+}
+
+// parseTime accepts UTC, floating and TZID-qualified DATE-TIMEs and DATEs.
+// Floating times are read in TZID, else the local zone.
+func parseTime(value, tzid string) (time.Time, error) {
+	if t, err := time.Parse(utcDateTime, value); err == nil {
+		return t, nil
+	}
+	loc := time.Local
+	if tzid != "" {
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+	for _, layout := range []string{"20060102T150405", "20060102"} {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date-time %q", value)
+}
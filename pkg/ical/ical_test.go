@@ -0,0 +1,135 @@
+// pkg/ical/ical_test.go
+package ical_test
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"hearx/pkg/ical"
+	"hearx/pkg/model"
+)
+
+var _ = Describe("iCalendar", func() {
+	created := time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC)
+	updated := time.Date(2026, 3, 2, 18, 0, 5, 0, time.UTC)
+
+	encode := func(tasks ...model.Task) string {
+		var buf bytes.Buffer
+		enc := ical.NewEncoder(&buf)
+		for _, t := range tasks {
+			Expect(enc.Encode(t)).To(Succeed())
+		}
+		Expect(enc.Close()).To(Succeed())
+		return buf.String()
+	}
+
+	It("should write a VTODO per task", func() {
+		out := encode(model.Task{ID: 7, Title: "Pay rent", Completed: true, CreatedAt: created, UpdatedAt: updated})
+
+		Expect(out).To(HavePrefix("BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+		Expect(out).To(HaveSuffix("END:VCALENDAR\r\n"))
+		Expect(out).To(ContainSubstring("BEGIN:VTODO\r\nUID:task-7@hearx\r\nDTSTAMP:20260302T180005Z\r\n" +
+			"CREATED:20260301T093000Z\r\nLAST-MODIFIED:20260302T180005Z\r\nSUMMARY:Pay rent\r\n" +
+			"STATUS:COMPLETED\r\nCOMPLETED:20260302T180005Z\r\nEND:VTODO\r\n"))
+	})
+
+	It("should round-trip text, status and timestamps", func() {
+		tasks := []model.Task{
+			{ID: 1, Title: "Call Bob; then Alice, maybe", Description: "line one\nback\\slash", CreatedAt: created, UpdatedAt: updated},
+			{ID: 2, Title: "Done", Completed: true, CreatedAt: created, UpdatedAt: updated},
+		}
+		got, err := ical.Decode(strings.NewReader(encode(tasks...)))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(got).To(Equal([]model.Task{
+			{Title: tasks[0].Title, Description: tasks[0].Description, CreatedAt: created, UpdatedAt: updated},
+			{Title: "Done", Completed: true, CreatedAt: created, UpdatedAt: updated},
+		}))
+	})
+
+	It("should round-trip the due date and recurrence", func() {
+		due := time.Date(2026, 10, 26, 8, 0, 0, 0, time.UTC)
+		out := encode(model.Task{ID: 3, Title: "Weekly review", DueAt: due, Recurrence: "FREQ=WEEKLY;BYDAY=MO", UpdatedAt: updated})
+		Expect(out).To(ContainSubstring("DUE:20261026T080000Z\r\nRRULE:FREQ=WEEKLY;BYDAY=MO\r\n"))
+
+		got, err := ical.Decode(strings.NewReader(out))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(got[0].DueAt).To(Equal(due))
+		Expect(got[0].Recurrence).To(Equal("FREQ=WEEKLY;BYDAY=MO"))
+	})
+
+	It("should fold long lines at 75 octets without splitting characters", func() {
+		title := strings.Repeat("é", 100) // 200 octets
+		out := encode(model.Task{ID: 1, Title: title, UpdatedAt: updated})
+
+		for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+			Expect(len(line)).To(BeNumerically("<=", 75))
+			Expect(strings.ToValidUTF8(line, "?")).To(Equal(line))
+		}
+		got, err := ical.Decode(strings.NewReader(out))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(got[0].Title).To(Equal(title))
+	})
+
+	It("should read VTODOs written by other calendar apps", func() {
+		doc := strings.Join([]string{
+			"BEGIN:VCALENDAR",
+			"PRODID:-//Example//EN",
+			"BEGIN:VTIMEZONE",
+			"TZID:Europe/Berlin",
+			"END:VTIMEZONE",
+			"BEGIN:VEVENT",
+			"SUMMARY:Not a task",
+			"END:VEVENT",
+			"BEGIN:VTODO",
+			"UID:abc@example.com",
+			"CREATED;TZID=Europe/Berlin:20260301T103000",
+			"summary:Water the plants",
+			"DESCRIPTION;LANGUAGE=en:Balcony and",
+			"  kitchen",
+			"BEGIN:VALARM",
+			"DESCRIPTION:alarm text",
+			"END:VALARM",
+			"COMPLETED:20260302T080000Z",
+			"END:VTODO",
+			"END:VCALENDAR",
+		}, "\n")
+
+		got, err := ical.Decode(strings.NewReader(doc))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(got).To(Equal([]model.Task{{
+			Title:       "Water the plants",
+			Description: "Balcony and kitchen",
+			Completed:   true,
+			CreatedAt:   time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC),
+		}}))
+	})
+
+	It("should treat a COMPLETED date as done whichever order STATUS comes in", func() {
+		for _, lines := range [][]string{
+			{"COMPLETED:20260302T080000Z", "STATUS:NEEDS-ACTION"},
+			{"STATUS:NEEDS-ACTION", "COMPLETED:20260302T080000Z"},
+		} {
+			doc := "BEGIN:VTODO\nSUMMARY:x\n" + strings.Join(lines, "\n") + "\nEND:VTODO\n"
+			got, err := ical.Decode(strings.NewReader(doc))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(got).To(HaveLen(1))
+			Expect(got[0].Completed).To(BeTrue(), strings.Join(lines, ", "))
+		}
+
+		got, err := ical.Decode(strings.NewReader("BEGIN:VTODO\nSUMMARY:x\nSTATUS:NEEDS-ACTION\nEND:VTODO\n"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(got[0].Completed).To(BeFalse())
+	})
+
+	It("should reject malformed input", func() {
+		_, err := ical.Decode(strings.NewReader("BEGIN:VTODO\nSUMMARY:x\n"))
+		Expect(err).To(MatchError("unterminated VTODO"))
+
+		_, err = ical.Decode(strings.NewReader("BEGIN:VTODO\nno colon here\nEND:VTODO\n"))
+		Expect(err).To(MatchError(ContainSubstring("line 2")))
+	})
+})
@@ -0,0 +1,273 @@
+// pkg/ical/rrule.go
+package ical
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurrence frequencies understood by ParseRule.
+const (
+	Daily   = "DAILY"
+	Weekly  = "WEEKLY"
+	Monthly = "MONTHLY"
+	Yearly  = "YEARLY"
+)
+
+// maxSkips bounds the search for a month or year that has the wanted day,
+// e.g. the 30th of a month every 12 months starting in February.
+const maxSkips = 48
+
+// Rule is the subset of an RFC 5545 RRULE that tasks support: FREQ (DAILY,
+// WEEKLY, MONTHLY or YEARLY) with INTERVAL, COUNT and UNTIL, BYDAY weekdays
+// for WEEKLY and BYMONTHDAY for MONTHLY, where negative days count from the
+// end of the month.
+type Rule struct {
+	Freq       string
+	Interval   int            // at least 1
+	Count      int            // occurrences left, this one included; 0 for no limit
+	Until      time.Time      // last possible occurrence; zero for no end
+	ByDay      []time.Weekday // sorted from Monday
+	ByMonthDay []int          // sorted
+}
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+var weekdayCodes = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// ParseRule parses an RRULE value such as "FREQ=WEEKLY;BYDAY=MO,TH". A
+// leading "RRULE:" is accepted. Parts outside the supported subset are
+// rejected rather than ignored, so a rule never silently means less.
+func ParseRule(s string) (Rule, error) {
+	s = strings.TrimSpace(s)
+	if len(s) >= 6 && strings.EqualFold(s[:6], "RRULE:") {
+		s = s[6:]
+	}
+	r := Rule{Interval: 1}
+	for _, part := range strings.Split(s, ";") {
+		if part == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(part, "=")
+		if !ok {
+			return Rule{}, fmt.Errorf("rrule: malformed part %q", part)
+		}
+		v = strings.ToUpper(strings.TrimSpace(v))
+		var err error
+		switch strings.ToUpper(strings.TrimSpace(k)) {
+		case "FREQ":
+			switch v {
+			case Daily, Weekly, Monthly, Yearly:
+				r.Freq = v
+			default:
+				return Rule{}, fmt.Errorf("rrule: unsupported FREQ %q", v)
+			}
+		case "INTERVAL":
+			r.Interval, err = positive("INTERVAL", v)
+		case "COUNT":
+			r.Count, err = positive("COUNT", v)
+		case "UNTIL":
+			r.Until, err = parseTime(v, "")
+		case "BYDAY":
+			r.ByDay, err = parseWeekdays(v)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseMonthDays(v)
+		case "WKST":
+			if v != "MO" {
+				err = fmt.Errorf("rrule: only WKST=MO is supported")
+			}
+		default:
+			err = fmt.Errorf("rrule: unsupported part %q", k)
+		}
+		if err != nil {
+			return Rule{}, err
+		}
+	}
+	switch {
+	case r.Freq == "":
+		return Rule{}, fmt.Errorf("rrule: FREQ is required")
+	case len(r.ByDay) > 0 && r.Freq != Weekly:
+		return Rule{}, fmt.Errorf("rrule: BYDAY is only supported with FREQ=WEEKLY")
+	case len(r.ByMonthDay) > 0 && r.Freq != Monthly:
+		return Rule{}, fmt.Errorf("rrule: BYMONTHDAY is only supported with FREQ=MONTHLY")
+	case r.Count > 0 && !r.Until.IsZero():
+		return Rule{}, fmt.Errorf("rrule: COUNT and UNTIL are mutually exclusive")
+	}
+	return r, nil
+}
+
+func positive(name, v string) (int, error) {
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("rrule: %s must be a positive integer, got %q", name, v)
+	}
+	return n, nil
+}
+
+func parseWeekdays(v string) ([]time.Weekday, error) {
+	seen := map[time.Weekday]bool{}
+	var days []time.Weekday
+	for _, code := range strings.Split(v, ",") {
+		d, ok := weekdays[code]
+		if !ok {
+			return nil, fmt.Errorf("rrule: unsupported BYDAY %q", code)
+		}
+		if !seen[d] {
+			seen[d] = true
+			days = append(days, d)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return fromMonday(days[i]) < fromMonday(days[j]) })
+	return days, nil
+}
+
+func parseMonthDays(v string) ([]int, error) {
+	seen := map[int]bool{}
+	var days []int
+	for _, s := range strings.Split(v, ",") {
+		d, err := strconv.Atoi(s)
+		if err != nil || d == 0 || d < -31 || d > 31 {
+			return nil, fmt.Errorf("rrule: invalid BYMONTHDAY %q", s)
+		}
+		if !seen[d] {
+			seen[d] = true
+			days = append(days, d)
+		}
+	}
+	sort.Ints(days)
+	return days, nil
+}
+
+// String formats the rule in a canonical order, without the "RRULE:" prefix.
+func (r Rule) String() string {
+	parts := []string{"FREQ=" + r.Freq}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		codes := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			codes[i] = weekdayCodes[d]
+		}
+		parts = append(parts, "BYDAY="+strings.Join(codes, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			days[i] = strconv.Itoa(d)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+formatTime(r.Until))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the first occurrence after prev, keeping prev's time of day,
+// and the rule that applies from there on: COUNT is one less. ok is false
+// when prev was the last occurrence.
+func (r Rule) Next(prev time.Time) (next time.Time, rest Rule, ok bool) {
+	if r.Count == 1 {
+		return time.Time{}, r, false
+	}
+	interval := max(r.Interval, 1)
+	switch r.Freq {
+	case Daily:
+		next, ok = prev.AddDate(0, 0, interval), true
+	case Weekly:
+		next, ok = r.nextWeekly(prev, interval), true
+	case Monthly:
+		next, ok = r.nextMonthly(prev, interval)
+	case Yearly:
+		next, ok = nextYearly(prev, interval)
+	}
+	if !ok || (!r.Until.IsZero() && next.After(r.Until)) {
+		return time.Time{}, r, false
+	}
+	if r.Count > 0 {
+		r.Count--
+	}
+	return next, r, true
+}
+
+func (r Rule) nextWeekly(prev time.Time, interval int) time.Time {
+	if len(r.ByDay) == 0 {
+		return prev.AddDate(0, 0, 7*interval)
+	}
+	cur := fromMonday(prev.Weekday())
+	for _, d := range r.ByDay {
+		if off := fromMonday(d); off > cur {
+			return prev.AddDate(0, 0, off-cur)
+		}
+	}
+	// first listed day of the week interval weeks on
+	return prev.AddDate(0, 0, 7*interval-cur+fromMonday(r.ByDay[0]))
+}
+
+func (r Rule) nextMonthly(prev time.Time, interval int) (time.Time, bool) {
+	days := r.ByMonthDay
+	if len(days) == 0 {
+		days = []int{prev.Day()}
+	}
+	// later in the same month first, then every interval months
+	if d, ok := firstDay(prev.Year(), prev.Month(), days, prev.Day()); ok {
+		return at(prev, prev.Year(), prev.Month(), d), true
+	}
+	for k := 1; k <= maxSkips; k++ {
+		first := time.Date(prev.Year(), prev.Month()+time.Month(k*interval), 1, 0, 0, 0, 0, prev.Location())
+		if d, ok := firstDay(first.Year(), first.Month(), days, 0); ok {
+			return at(prev, first.Year(), first.Month(), d), true
+		}
+	}
+	return time.Time{}, false
+}
+
+func nextYearly(prev time.Time, interval int) (time.Time, bool) {
+	for k := 1; k <= maxSkips; k++ {
+		y := prev.Year() + k*interval
+		if prev.Day() <= daysIn(y, prev.Month()) {
+			return at(prev, y, prev.Month(), prev.Day()), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// firstDay returns the earliest of days, resolved in the given month, that
+// is after the day after.
+func firstDay(year int, month time.Month, days []int, after int) (int, bool) {
+	n := daysIn(year, month)
+	best := 0
+	for _, d := range days {
+		if d < 0 {
+			d = n + d + 1
+		}
+		if d >= 1 && d <= n && d > after && (best == 0 || d < best) {
+			best = d
+		}
+	}
+	return best, best != 0
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// at is the given date at prev's time of day and location.
+func at(prev time.Time, year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, prev.Hour(), prev.Minute(), prev.Second(), prev.Nanosecond(), prev.Location())
+}
+
+// fromMonday numbers weekdays from Monday (0) to Sunday (6).
+func fromMonday(d time.Weekday) int {
+	return (int(d) + 6) % 7
+}
@@ -0,0 +1,103 @@
+// pkg/ical/rrule_test.go
+package ical_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"hearx/pkg/ical"
+)
+
+var _ = Describe("Rule", func() {
+	day := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 9, 0, 0, 0, time.UTC)
+	}
+
+	// occurrences lists the n occurrences after start.
+	occurrences := func(rule string, start time.Time, n int) []time.Time {
+		r, err := ical.ParseRule(rule)
+		Expect(err).NotTo(HaveOccurred())
+		var out []time.Time
+		for cur := start; len(out) < n; {
+			next, rest, ok := r.Next(cur)
+			if !ok {
+				break
+			}
+			out = append(out, next)
+			cur, r = next, rest
+		}
+		return out
+	}
+
+	Describe("ParseRule", func() {
+		It("should normalise a rule", func() {
+			r, err := ical.ParseRule("RRULE:freq=weekly;byday=FR,MO,MO;interval=2")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.String()).To(Equal("FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR"))
+		})
+
+		It("should keep COUNT and UNTIL", func() {
+			r, err := ical.ParseRule("FREQ=DAILY;UNTIL=20261231")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.Until).To(Equal(time.Date(2026, 12, 31, 0, 0, 0, 0, time.Local).UTC()))
+
+			r, err = ical.ParseRule("FREQ=DAILY;COUNT=3")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r.String()).To(Equal("FREQ=DAILY;COUNT=3"))
+		})
+
+		It("should reject rules outside the subset", func() {
+			for _, rule := range []string{
+				"INTERVAL=2",
+				"FREQ=HOURLY",
+				"FREQ=WEEKLY;BYDAY=1MO",
+				"FREQ=MONTHLY;BYDAY=MO",
+				"FREQ=MONTHLY;BYMONTHDAY=32",
+				"FREQ=DAILY;BYSETPOS=1",
+				"FREQ=DAILY;INTERVAL=0",
+				"FREQ=DAILY;COUNT=2;UNTIL=20260101T000000Z",
+			} {
+				_, err := ical.ParseRule(rule)
+				Expect(err).To(HaveOccurred(), rule)
+			}
+		})
+	})
+
+	Describe("Next", func() {
+		It("should step daily by the interval", func() {
+			Expect(occurrences("FREQ=DAILY;INTERVAL=3", day(2026, 2, 27), 2)).To(Equal(
+				[]time.Time{day(2026, 3, 2), day(2026, 3, 5)}))
+		})
+
+		It("should visit the listed weekdays, skipping weeks by the interval", func() {
+			// 2026-10-19 is a Monday
+			Expect(occurrences("FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH", day(2026, 10, 19), 3)).To(Equal(
+				[]time.Time{day(2026, 10, 22), day(2026, 11, 2), day(2026, 11, 5)}))
+		})
+
+		It("should skip months without the day", func() {
+			Expect(occurrences("FREQ=MONTHLY", day(2026, 1, 31), 2)).To(Equal(
+				[]time.Time{day(2026, 3, 31), day(2026, 5, 31)}))
+		})
+
+		It("should count negative month days from the end", func() {
+			Expect(occurrences("FREQ=MONTHLY;BYMONTHDAY=1,-1", day(2026, 2, 1), 3)).To(Equal(
+				[]time.Time{day(2026, 2, 28), day(2026, 3, 1), day(2026, 3, 31)}))
+		})
+
+		It("should skip years without February 29", func() {
+			Expect(occurrences("FREQ=YEARLY", day(2024, 2, 29), 1)).To(Equal([]time.Time{day(2028, 2, 29)}))
+		})
+
+		It("should stop after COUNT occurrences in total", func() {
+			Expect(occurrences("FREQ=DAILY;COUNT=3", day(2026, 1, 1), 5)).To(HaveLen(2))
+		})
+
+		It("should stop at UNTIL", func() {
+			Expect(occurrences("FREQ=WEEKLY;UNTIL=20260115T090000Z", day(2026, 1, 1), 5)).To(Equal(
+				[]time.Time{day(2026, 1, 8), day(2026, 1, 15)}))
+		})
+	})
+})
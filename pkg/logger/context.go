@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"hearx/pkg/auth"
+	"hearx/pkg/requestid"
+)
+
+type ctxKey struct{}
+
+// WithContext attaches a request-scoped logger to ctx.
+func WithContext(ctx context.Context, l *zap.Logger) context.Context {
+	return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext returns the request-scoped logger in ctx, or fallback when the
+// call did not come through UnaryServerInterceptor.
+func FromContext(ctx context.Context, fallback *zap.Logger) *zap.Logger {
+	if l, ok := ctx.Value(ctxKey{}).(*zap.Logger); ok {
+		return l
+	}
+	return fallback
+}
+
+// UnaryServerInterceptor injects a logger carrying the method, request ID and
+// caller identity into the handler's context. It must run after auth.
+func UnaryServerInterceptor(base *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		ctx, id := requestid.Ensure(ctx)
+		fields := []zap.Field{
+			zap.String("method", info.FullMethod),
+			zap.String("request_id", id),
+		}
+		if caller, ok := auth.CallerFromContext(ctx); ok {
+			fields = append(fields, zap.String("caller", caller))
+		}
+		return handler(WithContext(ctx, base.With(fields...)), req)
+	}
+}
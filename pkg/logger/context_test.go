@@ -0,0 +1,45 @@
+// pkg/logger/context_test.go
+package logger_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"hearx/pkg/auth"
+	"hearx/pkg/logger"
+)
+
+var _ = Describe("request-scoped logger", func() {
+	It("should fall back when the context carries no logger", func() {
+		fallback := zap.NewNop()
+		Expect(logger.FromContext(context.Background(), fallback)).To(BeIdenticalTo(fallback))
+	})
+
+	It("should inject method, request ID and caller", func() {
+		core, logs := observer.New(zapcore.DebugLevel)
+		interceptor := logger.UnaryServerInterceptor(zap.New(core))
+
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-request-id", "req-1"))
+		ctx = auth.WithCaller(ctx, "alice")
+		info := &grpc.UnaryServerInfo{FullMethod: "/todo.TodoService/AddTask"}
+
+		_, err := interceptor(ctx, nil, info, func(ctx context.Context, _ interface{}) (interface{}, error) {
+			logger.FromContext(ctx, zap.NewNop()).Info("handled")
+			return nil, nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		fields := logs.All()[0].ContextMap()
+		Expect(fields["method"]).To(Equal("/todo.TodoService/AddTask"))
+		Expect(fields["request_id"]).To(Equal("req-1"))
+		Expect(fields["caller"]).To(Equal("alice"))
+	})
+})
@@ -1,15 +1,57 @@
 package logger
 
 import (
+	"fmt"
+
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-func NewLogger() (*zap.Logger, error) {
-	cfg := zap.NewProductionConfig()
-	cfg.EncoderConfig.TimeKey = "timestamp"
-	cfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
-	cfg.EncoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
-	cfg.EncoderConfig.EncodeCaller = zapcore.ShortCallerEncoder
-	return cfg.Build()
+// Config selects the level, encoding, sampling and destination of the logs.
+type Config struct {
+	Level       string   // debug|info|warn|error
+	Format      string   // json|console
+	Sampling    bool     // drop repeated entries under load
+	OutputPaths []string // files or stdout/stderr
+}
+
+// DefaultConfig matches the production JSON logger at info level.
+func DefaultConfig() Config {
+	return Config{
+		Level:       "info",
+		Format:      "json",
+		Sampling:    true,
+		OutputPaths: []string{"stderr"},
+	}
+}
+
+// NewAtomicLevel parses the configured level into a zap.AtomicLevel that
+// can be changed at runtime (it doubles as an http.Handler).
+func NewAtomicLevel(cfg Config) (zap.AtomicLevel, error) {
+	return zap.ParseAtomicLevel(cfg.Level)
+}
+
+func NewLogger(cfg Config, level zap.AtomicLevel) (*zap.Logger, error) {
+	zcfg := zap.NewProductionConfig()
+	zcfg.Level = level
+	zcfg.EncoderConfig.TimeKey = "timestamp"
+	zcfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	zcfg.EncoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+	zcfg.EncoderConfig.EncodeCaller = zapcore.ShortCallerEncoder
+
+	switch cfg.Format {
+	case "", "json":
+		zcfg.Encoding = "json"
+	case "console":
+		zcfg.Encoding = "console"
+	default:
+		return nil, fmt.Errorf("unknown log format %q", cfg.Format)
+	}
+	if !cfg.Sampling {
+		zcfg.Sampling = nil
+	}
+	if len(cfg.OutputPaths) > 0 {
+		zcfg.OutputPaths = cfg.OutputPaths
+	}
+	return zcfg.Build(zap.WrapCore(NewRedactingCore))
 }
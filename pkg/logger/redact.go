@@ -0,0 +1,132 @@
+package logger
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/go-sql-driver/mysql"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const redacted = "[REDACTED]"
+
+// sensitiveKeys are matched case-insensitively against the segments of field
+// keys: a field whose key has one of them as a whole segment, as in
+// "auth_token", "X-Auth-Token" or "accessToken", has its value replaced
+// before encoding, while "tokens_left" or "max_tokens" are kept. Only the
+// field's own key is checked; values nested inside zap.Any, zap.Object or
+// zap.Reflect fields are not redacted, so never log a struct that holds a
+// credential.
+var sensitiveKeys = []string{"password", "passwd", "secret", "token", "authorization"}
+
+// DSN returns a field holding the given MySQL DSN with its password masked.
+func DSN(key, dsn string) zap.Field {
+	return zap.String(key, RedactDSN(dsn))
+}
+
+// RedactDSN masks the password of a MySQL DSN. Unparseable input is dropped
+// entirely rather than risk leaking it.
+func RedactDSN(dsn string) string {
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return redacted
+	}
+	if cfg.Passwd != "" {
+		cfg.Passwd = redacted
+	}
+	return cfg.FormatDSN()
+}
+
+// redactingCore wraps a zapcore.Core and scrubs credentials from fields
+// before they reach the encoder, whichever logger call site added them.
+type redactingCore struct {
+	zapcore.Core
+}
+
+// NewRedactingCore wraps core so that sensitive fields never hit the output.
+func NewRedactingCore(core zapcore.Core) zapcore.Core {
+	return &redactingCore{Core: core}
+}
+
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactingCore{Core: c.Core.With(redactFields(fields))}
+}
+
+func (c *redactingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *redactingCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(ent, redactFields(fields))
+}
+
+func redactFields(fields []zapcore.Field) []zapcore.Field {
+	var out []zapcore.Field
+	for i, f := range fields {
+		r, changed := redactField(f)
+		if !changed {
+			if out != nil {
+				out = append(out, f)
+			}
+			continue
+		}
+		if out == nil {
+			out = make([]zapcore.Field, i, len(fields))
+			copy(out, fields[:i])
+		}
+		out = append(out, r)
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+func redactField(f zapcore.Field) (zapcore.Field, bool) {
+	key := strings.ToLower(f.Key)
+	if key == "dsn" && f.Type == zapcore.StringType {
+		return zap.String(f.Key, RedactDSN(f.String)), true
+	}
+	for _, seg := range keySegments(f.Key) {
+		if slices.Contains(sensitiveKeys, seg) {
+			return zap.String(f.Key, redacted), true
+		}
+	}
+	return f, false
+}
+
+// keySegments splits a field key into lower-case words at punctuation and
+// at lower-to-upper case changes: "X-Auth-Token" and "authToken" both give
+// "auth" and "token".
+func keySegments(key string) []string {
+	var (
+		segs []string
+		cur  []rune
+		prev rune
+	)
+	flush := func() {
+		if len(cur) > 0 {
+			segs = append(segs, strings.ToLower(string(cur)))
+			cur = cur[:0]
+		}
+	}
+	for _, r := range key {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+			flush()
+			cur = append(cur, r)
+		default:
+			cur = append(cur, r)
+		}
+		prev = r
+	}
+	flush()
+	return segs
+}
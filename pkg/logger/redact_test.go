@@ -0,0 +1,73 @@
+// pkg/logger/redact_test.go
+package logger_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"hearx/pkg/logger"
+)
+
+var _ = Describe("redaction", func() {
+	var (
+		logs *observer.ObservedLogs
+		log  *zap.Logger
+	)
+
+	BeforeEach(func() {
+		var core zapcore.Core
+		core, logs = observer.New(zapcore.DebugLevel)
+		log = zap.New(logger.NewRedactingCore(core))
+	})
+
+	It("should mask the password of a DSN", func() {
+		Expect(logger.RedactDSN("user:s3cr3t@tcp(db:3306)/app?parseTime=true")).
+			To(Equal("user:[REDACTED]@tcp(db:3306)/app?parseTime=true"))
+	})
+
+	It("should drop DSNs it cannot parse", func() {
+		Expect(logger.RedactDSN("not a dsn")).To(Equal("[REDACTED]"))
+	})
+
+	It("should scrub sensitive fields on write", func() {
+		log.Info("connecting",
+			zap.String("dsn", "user:s3cr3t@tcp(db:3306)/app"),
+			zap.String("authorization", "Bearer abc"),
+			zap.String("title", "keep me"),
+		)
+
+		ctx := logs.All()[0].ContextMap()
+		Expect(ctx["dsn"]).To(Equal("user:[REDACTED]@tcp(db:3306)/app"))
+		Expect(ctx["authorization"]).To(Equal("[REDACTED]"))
+		Expect(ctx["title"]).To(Equal("keep me"))
+	})
+
+	It("should match whole key segments only", func() {
+		log.Info("call",
+			zap.String("X-Auth-Token", "abc"),
+			zap.String("accessToken", "abc"),
+			zap.String("db.password", "abc"),
+			zap.String("client_secret", "abc"),
+			zap.Int("tokens_left", 3),
+			zap.Int("max_tokens", 100),
+			zap.String("secretary", "keep me"),
+		)
+
+		ctx := logs.All()[0].ContextMap()
+		for _, key := range []string{"X-Auth-Token", "accessToken", "db.password", "client_secret"} {
+			Expect(ctx[key]).To(Equal("[REDACTED]"), key)
+		}
+		Expect(ctx["tokens_left"]).To(Equal(int64(3)))
+		Expect(ctx["max_tokens"]).To(Equal(int64(100)))
+		Expect(ctx["secretary"]).To(Equal("keep me"))
+	})
+
+	It("should scrub fields attached with With", func() {
+		log.With(zap.String("auth_token", "abc")).Info("call")
+		Expect(logs.All()[0].ContextMap()["auth_token"]).To(Equal("[REDACTED]"))
+	})
+})
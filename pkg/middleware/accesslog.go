@@ -0,0 +1,66 @@
+// pkg/middleware/accesslog.go
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"hearx/pkg/requestid"
+)
+
+// AccessLog writes one structured line per call with the method, peer,
+// status code, latency and request/response sizes.
+func AccessLog(log *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		code := status.Code(err)
+
+		fields := []zap.Field{
+			zap.String("method", info.FullMethod),
+			zap.String("request_id", requestid.FromContext(ctx)),
+			zap.String("code", code.String()),
+			zap.Duration("latency", time.Since(start)),
+			zap.Int("req_bytes", size(req)),
+			zap.Int("resp_bytes", size(resp)),
+		}
+		if p, ok := peer.FromContext(ctx); ok {
+			fields = append(fields, zap.String("peer", p.Addr.String()))
+		}
+		if err != nil {
+			fields = append(fields, zap.Error(err))
+		}
+		log.Check(accessLevel(code), "access").Write(fields...)
+		return resp, err
+	}
+}
+
+// accessLevel reports server-side failures at error, everything else at info.
+func accessLevel(code codes.Code) zapcore.Level {
+	switch code {
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unimplemented:
+		return zapcore.ErrorLevel
+	default:
+		return zapcore.InfoLevel
+	}
+}
+
+func size(v interface{}) int {
+	if m, ok := v.(proto.Message); ok {
+		return proto.Size(m)
+	}
+	return 0
+}
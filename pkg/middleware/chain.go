@@ -0,0 +1,15 @@
+// pkg/middleware/chain.go
+package middleware
+
+import (
+	"google.golang.org/grpc"
+)
+
+// Chain is an ordered list of unary interceptors. The first entry is the
+// outermost: it sees the request first and the response last.
+type Chain []grpc.UnaryServerInterceptor
+
+// ServerOption installs the chain on a grpc.Server.
+func (c Chain) ServerOption() grpc.ServerOption {
+	return grpc.ChainUnaryInterceptor(c...)
+}
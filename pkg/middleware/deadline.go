@@ -0,0 +1,112 @@
+// pkg/middleware/deadline.go
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Timeouts bounds a call: Default applies when the client sent no deadline,
+// Max caps whatever deadline the client asked for, or the call itself when
+// there is neither. Zero disables either.
+type Timeouts struct {
+	Default time.Duration
+	Max     time.Duration
+}
+
+// Validate rejects a Default longer than the Max that would cap it.
+func (t Timeouts) Validate() error {
+	if t.Max > 0 && t.Default > t.Max {
+		return fmt.Errorf("default timeout %s is longer than the maximum %s", t.Default, t.Max)
+	}
+	return nil
+}
+
+// DeadlineConfig holds the server-wide timeouts and per-method overrides,
+// keyed by the short method name (e.g. "ListTasks").
+type DeadlineConfig struct {
+	Timeouts
+	Methods map[string]Timeouts
+}
+
+// ParseTimeouts parses overrides written as "ListTasks=2s:10s,AddTask=1s"
+// (default:max; the max defaults to the default).
+func ParseTimeouts(v string) (map[string]Timeouts, error) {
+	out := map[string]Timeouts{}
+	for _, item := range strings.Split(v, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, spec, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("timeout %q: want Method=default[:max]", item)
+		}
+		defStr, maxStr, hasMax := strings.Cut(spec, ":")
+		def, err := time.ParseDuration(defStr)
+		if err != nil {
+			return nil, fmt.Errorf("timeout %q: %w", item, err)
+		}
+		t := Timeouts{Default: def, Max: def}
+		if hasMax {
+			if t.Max, err = time.ParseDuration(maxStr); err != nil {
+				return nil, fmt.Errorf("timeout %q: %w", item, err)
+			}
+		}
+		if err := t.Validate(); err != nil {
+			return nil, fmt.Errorf("timeout %q: %w", item, err)
+		}
+		out[name] = t
+	}
+	return out, nil
+}
+
+func (c DeadlineConfig) forMethod(method string) Timeouts {
+	if t, ok := c.Methods[path.Base(method)]; ok {
+		return t
+	}
+	return c.Timeouts
+}
+
+// Deadline gives every call a bounded lifetime so that no client can hold a
+// database connection indefinitely. The resulting context flows into the
+// database/sql calls, which abort when it expires.
+func Deadline(cfg DeadlineConfig) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		t := cfg.forMethod(info.FullMethod)
+
+		deadline, hasDeadline := ctx.Deadline()
+		var limit time.Duration
+		switch {
+		case !hasDeadline:
+			limit = t.Default
+			if limit == 0 || (t.Max > 0 && limit > t.Max) {
+				limit = t.Max
+			}
+		case t.Max > 0 && time.Until(deadline) > t.Max:
+			limit = t.Max
+		}
+		if limit > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, limit)
+			defer cancel()
+		}
+
+		// the client may have given up while the call was queued
+		if err := ctx.Err(); err != nil {
+			return nil, status.FromContextError(err).Err()
+		}
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,190 @@
+// pkg/middleware/middleware_test.go
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"hearx/pkg/middleware"
+	"hearx/pkg/requestid"
+	pb "hearx/proto"
+)
+
+var _ = Describe("interceptors", func() {
+	var info *grpc.UnaryServerInfo
+
+	BeforeEach(func() {
+		info = &grpc.UnaryServerInfo{FullMethod: "/todo.TodoService/ListTasks"}
+	})
+
+	Describe("RequestID", func() {
+		It("should keep the ID sent by the client", func() {
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(requestid.Header, "abc"))
+
+			var seen string
+			_, err := middleware.RequestID()(ctx, nil, info, func(ctx context.Context, _ interface{}) (interface{}, error) {
+				seen = requestid.FromContext(ctx)
+				return nil, nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(seen).To(Equal("abc"))
+		})
+
+		It("should generate an ID when none is sent", func() {
+			var seen string
+			_, err := middleware.RequestID()(context.Background(), nil, info, func(ctx context.Context, _ interface{}) (interface{}, error) {
+				seen = requestid.FromContext(ctx)
+				return nil, nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(seen).To(HaveLen(32))
+		})
+
+		It("should replace an ID that is too long or not printable", func() {
+			for _, sent := range []string{strings.Repeat("a", requestid.MaxLen+1), "abc\ndef", "abc def", "ïd"} {
+				ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(requestid.Header, sent))
+
+				var seen string
+				_, err := middleware.RequestID()(ctx, nil, info, func(ctx context.Context, _ interface{}) (interface{}, error) {
+					seen = requestid.FromContext(ctx)
+					return nil, nil
+				})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(seen).To(HaveLen(32), "sent %q", sent)
+			}
+		})
+
+		It("should keep an ID of exactly the maximum length", func() {
+			sent := strings.Repeat("a", requestid.MaxLen)
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(requestid.Header, sent))
+
+			var seen string
+			_, err := middleware.RequestID()(ctx, nil, info, func(ctx context.Context, _ interface{}) (interface{}, error) {
+				seen = requestid.FromContext(ctx)
+				return nil, nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(seen).To(Equal(sent))
+		})
+	})
+
+	Describe("Recovery", func() {
+		It("should convert a panic into an Internal error", func() {
+			_, err := middleware.Recovery(zap.NewNop())(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) {
+				panic("boom")
+			})
+			Expect(status.Code(err)).To(Equal(codes.Internal))
+		})
+	})
+
+	Describe("Deadline", func() {
+		var cfg middleware.DeadlineConfig
+
+		BeforeEach(func() {
+			cfg = middleware.DeadlineConfig{Timeouts: middleware.Timeouts{Default: time.Second, Max: 2 * time.Second}}
+		})
+
+		remaining := func(ctx context.Context) time.Duration {
+			var left time.Duration
+			_, err := middleware.Deadline(cfg)(ctx, nil, info, func(ctx context.Context, _ interface{}) (interface{}, error) {
+				deadline, ok := ctx.Deadline()
+				Expect(ok).To(BeTrue())
+				left = time.Until(deadline)
+				return nil, nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			return left
+		}
+
+		It("should apply the default when the client sent no deadline", func() {
+			Expect(remaining(context.Background())).To(BeNumerically("~", time.Second, 100*time.Millisecond))
+		})
+
+		It("should cap a deadline beyond the maximum", func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+			defer cancel()
+			Expect(remaining(ctx)).To(BeNumerically("~", 2*time.Second, 100*time.Millisecond))
+		})
+
+		It("should prefer per-method overrides", func() {
+			methods, err := middleware.ParseTimeouts("ListTasks=3s:5s")
+			Expect(err).NotTo(HaveOccurred())
+			cfg.Methods = methods
+			Expect(remaining(context.Background())).To(BeNumerically("~", 3*time.Second, 100*time.Millisecond))
+		})
+
+		It("should use the maximum when there is no default and no client deadline", func() {
+			cfg.Default = 0
+			Expect(remaining(context.Background())).To(BeNumerically("~", 2*time.Second, 100*time.Millisecond))
+		})
+
+		It("should cap a default beyond the maximum", func() {
+			cfg.Default = time.Hour
+			Expect(remaining(context.Background())).To(BeNumerically("~", 2*time.Second, 100*time.Millisecond))
+		})
+
+		It("should reject a per-method default beyond its maximum", func() {
+			_, err := middleware.ParseTimeouts("ListTasks=5s:3s")
+			Expect(err).To(MatchError(ContainSubstring("ListTasks=5s:3s")))
+
+			_, err = middleware.ParseTimeouts("ListTasks=5s:0s")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should validate the server-wide timeouts", func() {
+			Expect(middleware.Timeouts{Default: 3 * time.Second, Max: time.Second}.Validate()).NotTo(Succeed())
+			Expect(middleware.Timeouts{Default: time.Second, Max: time.Second}.Validate()).To(Succeed())
+			Expect(middleware.Timeouts{Default: time.Hour}.Validate()).To(Succeed())
+		})
+
+		It("should refuse calls whose context is already done", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			_, err := middleware.Deadline(cfg)(ctx, nil, info, func(context.Context, interface{}) (interface{}, error) {
+				Fail("handler should not run")
+				return nil, nil
+			})
+			Expect(status.Code(err)).To(Equal(codes.Canceled))
+		})
+	})
+
+	Describe("AccessLog", func() {
+		It("should log method, code and payload sizes", func() {
+			core, logs := observer.New(zapcore.InfoLevel)
+			resp := &pb.ListTasksResponse{Tasks: []*pb.Task{{Id: 1, Title: "A"}}}
+
+			_, err := middleware.AccessLog(zap.New(core))(context.Background(), &pb.ListTasksRequest{}, info, func(context.Context, interface{}) (interface{}, error) {
+				return resp, nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			fields := logs.All()[0].ContextMap()
+			Expect(fields["method"]).To(Equal(info.FullMethod))
+			Expect(fields["code"]).To(Equal("OK"))
+			Expect(fields["resp_bytes"]).To(BeNumerically(">", 0))
+		})
+
+		It("should log server failures at error level", func() {
+			core, logs := observer.New(zapcore.InfoLevel)
+
+			_, err := middleware.AccessLog(zap.New(core))(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) {
+				return nil, errors.New("db down")
+			})
+			Expect(err).To(HaveOccurred())
+			Expect(logs.All()[0].Level).To(Equal(zapcore.ErrorLevel))
+		})
+	})
+})
@@ -0,0 +1,37 @@
+// pkg/middleware/recovery.go
+package middleware
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"hearx/pkg/requestid"
+)
+
+// Recovery turns a panic in any inner interceptor or handler into an
+// Internal error instead of crashing the process.
+func Recovery(log *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Error("handler panicked",
+					zap.String("method", info.FullMethod),
+					zap.String("request_id", requestid.FromContext(ctx)),
+					zap.Any("panic", r),
+					zap.Stack("stack"),
+				)
+				resp, err = nil, status.Error(codes.Internal, "internal error")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,28 @@
+// pkg/middleware/requestid.go
+package middleware
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"hearx/pkg/requestid"
+)
+
+// RequestID takes the x-request-id sent by the client, or generates one if
+// it is missing, too long or not printable, stores it in the context and
+// echoes it back in the response header.
+func RequestID() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		ctx, id := requestid.Ensure(ctx)
+		// best effort: fails only if headers were already sent
+		_ = grpc.SetHeader(ctx, metadata.Pairs(requestid.Header, id))
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+// Actions recorded in the audit trail, one per kind of task change.
+// Comment events name the comment in their detail.
+const (
+	ActionAdd              = "add"
+	ActionUpdate           = "update"
+	ActionComplete         = "complete"
+	ActionMove             = "move"
+	ActionAddDependency    = "add_dependency"
+	ActionRemoveDependency = "remove_dependency"
+	ActionSetRecurrence    = "set_recurrence"
+	ActionSetProject       = "set_project"
+	ActionAssign           = "assign"
+	ActionUnassign         = "unassign"
+	ActionDelete           = "delete"
+	ActionRestore          = "restore"
+	ActionUndelete         = "undelete"
+	ActionArchive          = "archive"
+	ActionPurge            = "purge"
+	ActionCommentAdd       = "comment_add"
+	ActionCommentDelete    = "comment_delete"
+)
+
+// AuditEvent records one change to a task: who made it, through which
+// request, and the task as it was before and after.
+type AuditEvent struct {
+	ID         int64     `json:"id" yaml:"id"`
+	OccurredAt time.Time `json:"occurred_at" yaml:"occurred_at"`
+	Actor      string    `json:"actor" yaml:"actor"`
+	Action     string    `json:"action" yaml:"action"`
+	TaskID     int64     `json:"task_id" yaml:"task_id"`
+	RequestID  string    `json:"request_id,omitempty" yaml:"request_id,omitempty"`
+	Detail     string    `json:"detail,omitempty" yaml:"detail,omitempty"`
+	Before     *Task     `json:"before,omitempty" yaml:"before,omitempty"` // nil when the task was added
+	After      *Task     `json:"after,omitempty" yaml:"after,omitempty"`
+}
+
+// AuditFilter narrows and pages an audit listing. Results are ordered by ID,
+// which is the order events were recorded in.
+type AuditFilter struct {
+	AfterID int64     // only events with a greater ID, for keyset pagination
+	Limit   int       // maximum number of events; zero means no limit
+	Actor   string    // only events by this caller; empty means any
+	Action  string    // only events of this action; empty means any
+	TaskID  int64     // only events about this task; zero means any
+	Since   time.Time // only events at or after this time; zero means any
+	Until   time.Time // only events before this time; zero means any
+}
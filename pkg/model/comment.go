@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// Comment is a note left on a task, kept apart from its description.
+type Comment struct {
+	ID        int64     `json:"id" yaml:"id"`
+	TaskID    int64     `json:"task_id" yaml:"task_id"`
+	Author    string    `json:"author,omitempty" yaml:"author,omitempty"`
+	Body      string    `json:"body" yaml:"body"`
+	CreatedAt time.Time `json:"created_at,omitzero" yaml:"created_at,omitempty"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// Project groups tasks, e.g. a team's backlog or a shopping list.
+type Project struct {
+	ID          int64     `json:"id" yaml:"id"`
+	Name        string    `json:"name" yaml:"name"`
+	Description string    `json:"description,omitempty" yaml:"description,omitempty"`
+	Archived    bool      `json:"archived" yaml:"archived"`
+	Owner       string    `json:"owner,omitempty" yaml:"owner,omitempty"`
+	CreatedAt   time.Time `json:"created_at,omitzero" yaml:"created_at,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at,omitzero" yaml:"updated_at,omitempty"`
+}
+
+// ProjectStats counts the live tasks of a project.
+type ProjectStats struct {
+	Total     int64 `json:"total" yaml:"total"`
+	Completed int64 `json:"completed" yaml:"completed"`
+	Open      int64 `json:"open" yaml:"open"`
+	Overdue   int64 `json:"overdue" yaml:"overdue"` // open and past their due date
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// Revision is a task as one write left it. Revisions of a task are numbered
+// from 1, the task as created.
+type Revision struct {
+	TaskID    int64         `json:"task_id" yaml:"task_id"`
+	Number    int64         `json:"revision" yaml:"revision"`
+	Task      Task          `json:"task" yaml:"task"`
+	Deleted   bool          `json:"deleted,omitempty" yaml:"deleted,omitempty"` // the write soft-deleted the task
+	CreatedAt time.Time     `json:"created_at" yaml:"created_at"`
+	Changes   []FieldChange `json:"changes,omitempty" yaml:"changes,omitempty"` // against the revision before
+}
+
+// FieldChange is one field that differs between two revisions, with both
+// values formatted as text.
+type FieldChange struct {
+	Field string `json:"field" yaml:"field"`
+	From  string `json:"from" yaml:"from"`
+	To    string `json:"to" yaml:"to"`
+}
@@ -1,8 +1,39 @@
 package model
 
+import "time"
+
 type Task struct {
-	ID          int64  `json:"id"`
-	Title       string `json:"title"`
-	Description string `json:"description,omitempty"`
-	Completed   bool   `json:"completed"`
+	ID          int64     `json:"id" yaml:"id"`
+	Title       string    `json:"title" yaml:"title"`
+	Description string    `json:"description,omitempty" yaml:"description,omitempty"`
+	Completed   bool      `json:"completed" yaml:"completed"`
+	Owner       string    `json:"owner,omitempty" yaml:"owner,omitempty"`
+	Assignee    string    `json:"assignee,omitempty" yaml:"assignee,omitempty"`     // empty when unassigned
+	ParentID    int64     `json:"parent_id,omitempty" yaml:"parent_id,omitempty"`   // zero for top-level tasks
+	ProjectID   int64     `json:"project_id,omitempty" yaml:"project_id,omitempty"` // zero when in no project
+	DueAt       time.Time `json:"due_at,omitzero" yaml:"due_at,omitempty"`
+	Recurrence  string    `json:"recurrence,omitempty" yaml:"recurrence,omitempty"` // RFC 5545 RRULE value, e.g. FREQ=WEEKLY;BYDAY=MO
+	CreatedAt   time.Time `json:"created_at,omitzero" yaml:"created_at,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at,omitzero" yaml:"updated_at,omitempty"`
+	DeletedAt   time.Time `json:"deleted_at,omitzero" yaml:"deleted_at,omitempty"`   // zero unless the task is in the trash
+	ArchivedAt  time.Time `json:"archived_at,omitzero" yaml:"archived_at,omitempty"` // zero unless the task is completed and archived
+}
+
+// Dependency is an edge of the dependency graph: TaskID waits for BlockerID.
+type Dependency struct {
+	TaskID    int64
+	BlockerID int64
+}
+
+// TaskFilter narrows and pages a task listing. Results are ordered by ID.
+type TaskFilter struct {
+	AfterID   int64  // only tasks with a greater ID, for keyset pagination
+	Limit     int    // maximum number of tasks; zero means no limit
+	Owner     string // only tasks created by this caller; empty means any
+	Ready     bool   // only open tasks whose blockers are all completed
+	ProjectID int64  // only tasks in this project; zero means any
+	Assignee  string // only tasks assigned to this user; empty means any
+	Deleted   bool   // the trash: only soft-deleted tasks instead of live ones
+	// IncludeArchived also lists archived tasks, which are left out by default
+	IncludeArchived bool
 }
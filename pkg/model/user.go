@@ -0,0 +1,9 @@
+package model
+
+import "time"
+
+// User is someone tasks can be assigned to. The server keeps the registry.
+type User struct {
+	Name      string    `json:"name" yaml:"name"`
+	CreatedAt time.Time `json:"created_at,omitzero" yaml:"created_at,omitempty"`
+}
@@ -0,0 +1,73 @@
+// pkg/profile/profile_test.go
+package profile_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"hearx/pkg/profile"
+)
+
+var _ = Describe("Config", func() {
+	var dir, path string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "profile")
+		Expect(err).NotTo(HaveOccurred())
+		path = filepath.Join(dir, "todo", "config.yaml")
+	})
+
+	AfterEach(func() { os.RemoveAll(dir) })
+
+	It("should treat a missing file as empty", func() {
+		cfg, err := profile.Load(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Names()).To(BeEmpty())
+
+		p, err := cfg.Get("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p).To(Equal(profile.Profile{}))
+	})
+
+	It("should round-trip profiles and keep the file private", func() {
+		cfg, _ := profile.Load(path)
+		cfg.Profiles["staging"] = profile.Profile{Addrs: []string{"a:1", "b:1"}, Token: "s3cret"}
+		cfg.Profiles["prod"] = profile.Profile{Addrs: []string{"p:1"}}
+		Expect(cfg.Use("staging")).To(Succeed())
+		Expect(cfg.Save(path)).To(Succeed())
+
+		fi, err := os.Stat(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fi.Mode().Perm()).To(Equal(os.FileMode(0o600)))
+
+		loaded, err := profile.Load(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(loaded.Names()).To(Equal([]string{"prod", "staging"}))
+		p, err := loaded.Get("")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.Token).To(Equal("s3cret"))
+		p, err = loaded.Get("prod")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(p.Addrs).To(Equal([]string{"p:1"}))
+	})
+
+	It("should refuse a file other users can read", func() {
+		Expect(os.MkdirAll(filepath.Dir(path), 0o700)).To(Succeed())
+		Expect(os.WriteFile(path, []byte("current: x\n"), 0o644)).To(Succeed())
+
+		_, err := profile.Load(path)
+		Expect(err).To(MatchError(ContainSubstring("chmod 600")))
+	})
+
+	It("should report unknown profiles", func() {
+		cfg, _ := profile.Load(path)
+		_, err := cfg.Get("nope")
+		Expect(errors.Is(err, profile.ErrNotFound)).To(BeTrue())
+		Expect(errors.Is(cfg.Use("nope"), profile.ErrNotFound)).To(BeTrue())
+	})
+})
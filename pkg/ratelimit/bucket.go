@@ -0,0 +1,36 @@
+// pkg/ratelimit/bucket.go
+package ratelimit
+
+import (
+	"time"
+)
+
+// bucket is a classic token bucket: it holds up to burst tokens and refills
+// at rate tokens per second. It is not safe for concurrent use on its own.
+type bucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newBucket(l Limit, now time.Time) *bucket {
+	return &bucket{rate: l.Rate, burst: float64(l.Burst), tokens: float64(l.Burst), last: now}
+}
+
+// take consumes one token if available. Otherwise it reports how long the
+// caller has to wait before the next token is due.
+func (b *bucket) take(now time.Time) (bool, time.Duration) {
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	missing := 1 - b.tokens
+	return false, time.Duration(missing / b.rate * float64(time.Second))
+}
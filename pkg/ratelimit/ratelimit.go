@@ -0,0 +1,141 @@
+// pkg/ratelimit/ratelimit.go
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"hearx/pkg/auth"
+)
+
+// RetryAfterHeader carries the number of seconds to wait before retrying.
+const RetryAfterHeader = "retry-after"
+
+// Limit is a sustained rate in requests per second plus the burst allowed
+// on top of it. A zero Rate means unlimited.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Config holds the default limit and per-method overrides, keyed by the
+// short method name (e.g. "AddTask").
+type Config struct {
+	Default Limit
+	Methods map[string]Limit
+}
+
+// ParseMethods parses overrides written as "AddTask=1:5,ListTasks=20:40"
+// (rate:burst; the burst defaults to the rounded-up rate).
+func ParseMethods(v string) (map[string]Limit, error) {
+	out := map[string]Limit{}
+	for _, item := range strings.Split(v, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, spec, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("rate limit %q: want Method=rate[:burst]", item)
+		}
+		rateStr, burstStr, hasBurst := strings.Cut(spec, ":")
+		rate, err := strconv.ParseFloat(rateStr, 64)
+		if err != nil {
+			return nil, fmt.Errorf("rate limit %q: %w", item, err)
+		}
+		l := Limit{Rate: rate, Burst: int(math.Ceil(rate))}
+		if hasBurst {
+			if l.Burst, err = strconv.Atoi(burstStr); err != nil {
+				return nil, fmt.Errorf("rate limit %q: %w", item, err)
+			}
+		}
+		// a bucket that holds no token would reject every call
+		if l.Rate > 0 && l.Burst < 1 {
+			return nil, fmt.Errorf("rate limit %q: burst must be at least 1", item)
+		}
+		out[name] = l
+	}
+	return out, nil
+}
+
+// Limiter keeps one token bucket per caller and method. Callers come from
+// the configured auth tokens, so the number of buckets stays small.
+type Limiter struct {
+	cfg Config
+	now func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+// NewLimiter builds a Limiter for cfg.
+func NewLimiter(cfg Config) *Limiter {
+	return &Limiter{cfg: cfg, now: time.Now, buckets: map[string]*bucket{}}
+}
+
+func (l *Limiter) limitFor(method string) Limit {
+	if lim, ok := l.cfg.Methods[path.Base(method)]; ok {
+		return lim
+	}
+	return l.cfg.Default
+}
+
+// Allow reports whether caller may call method now, and if not, how long
+// until it may.
+func (l *Limiter) Allow(caller, method string) (bool, time.Duration) {
+	lim := l.limitFor(method)
+	if lim.Rate <= 0 {
+		return true, 0
+	}
+
+	now := l.now()
+	key := caller + "|" + method
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.buckets[key]
+	if !ok {
+		b = newBucket(lim, now)
+		l.buckets[key] = b
+	}
+	return b.take(now)
+}
+
+// UnaryServerInterceptor rejects calls over the limit with ResourceExhausted,
+// a RetryInfo detail and a retry-after trailer. It must run after auth so the
+// caller identity is known.
+func UnaryServerInterceptor(l *Limiter) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		caller, _ := auth.CallerFromContext(ctx)
+		ok, wait := l.Allow(caller, info.FullMethod)
+		if ok {
+			return handler(ctx, req)
+		}
+
+		secs := int(math.Ceil(wait.Seconds()))
+		_ = grpc.SetTrailer(ctx, metadata.Pairs(RetryAfterHeader, strconv.Itoa(secs)))
+		st := status.New(codes.ResourceExhausted, "rate limit exceeded")
+		if detailed, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(wait)}); err == nil {
+			st = detailed
+		}
+		return nil, st.Err()
+	}
+}
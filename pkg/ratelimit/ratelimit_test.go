@@ -0,0 +1,81 @@
+// pkg/ratelimit/ratelimit_test.go
+package ratelimit_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"hearx/pkg/auth"
+	"hearx/pkg/ratelimit"
+)
+
+var _ = Describe("Limiter", func() {
+	const addTask = "/todo.TodoService/AddTask"
+
+	It("should allow the burst and then refuse with a wait time", func() {
+		l := ratelimit.NewLimiter(ratelimit.Config{Default: ratelimit.Limit{Rate: 1, Burst: 2}})
+
+		ok, _ := l.Allow("alice", addTask)
+		Expect(ok).To(BeTrue())
+		ok, _ = l.Allow("alice", addTask)
+		Expect(ok).To(BeTrue())
+
+		ok, wait := l.Allow("alice", addTask)
+		Expect(ok).To(BeFalse())
+		Expect(wait).To(BeNumerically(">", 0))
+	})
+
+	It("should keep separate buckets per caller", func() {
+		l := ratelimit.NewLimiter(ratelimit.Config{Default: ratelimit.Limit{Rate: 1, Burst: 1}})
+
+		ok, _ := l.Allow("alice", addTask)
+		Expect(ok).To(BeTrue())
+		ok, _ = l.Allow("bob", addTask)
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should apply per-method overrides by short name", func() {
+		methods, err := ratelimit.ParseMethods("AddTask=0.5:1")
+		Expect(err).NotTo(HaveOccurred())
+		l := ratelimit.NewLimiter(ratelimit.Config{Default: ratelimit.Limit{Rate: 100, Burst: 100}, Methods: methods})
+
+		ok, _ := l.Allow("alice", addTask)
+		Expect(ok).To(BeTrue())
+		ok, _ = l.Allow("alice", addTask)
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should reject malformed overrides", func() {
+		_, err := ratelimit.ParseMethods("AddTask")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should reject an override whose burst would refuse every call", func() {
+		_, err := ratelimit.ParseMethods("AddTask=1:0")
+		Expect(err).To(MatchError(ContainSubstring("burst")))
+
+		// a zero rate with no burst turns the limit off for the method
+		_, err = ratelimit.ParseMethods("ListTasks=0:0")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should answer ResourceExhausted from the interceptor", func() {
+		l := ratelimit.NewLimiter(ratelimit.Config{Default: ratelimit.Limit{Rate: 1, Burst: 1}})
+		interceptor := ratelimit.UnaryServerInterceptor(l)
+		ctx := auth.WithCaller(context.Background(), "alice")
+		info := &grpc.UnaryServerInfo{FullMethod: addTask}
+		handler := func(context.Context, interface{}) (interface{}, error) { return "ok", nil }
+
+		_, err := interceptor(ctx, nil, info, handler)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, err = interceptor(ctx, nil, info, handler)
+		Expect(status.Code(err)).To(Equal(codes.ResourceExhausted))
+	})
+})
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	hlog "hearx/pkg/logger"
+	"hearx/pkg/model"
+
+	"go.uber.org/zap"
+)
+
+// Archive marks up to limit live tasks archived that were completed and
+// last changed before completedBefore, oldest first, and returns how many
+// it marked. Each is recorded as an archive audit event in the same
+// transaction. updated_at is kept, so an archived task still shows when it
+// was last changed. Archiving records no revision: it changes none of the
+// fields a revision tracks.
+func (r *mysqlTaskRepository) Archive(ctx context.Context, completedBefore time.Time, limit int) (int64, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("archiving completed tasks", zap.Time("completed_before", completedBefore), zap.Int("limit", limit))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.Primary().BeginTx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", zap.Error(err))
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	tasks, err := lockTasks(ctx, tx,
+		`archived_at IS NULL AND completed = TRUE AND deleted_at IS NULL AND updated_at < ?`,
+		completedBefore, limit,
+	)
+	if err != nil {
+		log.Error("failed to query archivable tasks", zap.Error(err))
+		return 0, err
+	}
+	if len(tasks) == 0 {
+		return 0, nil
+	}
+	ts := now()
+	ids := make([]any, len(tasks))
+	events := make([]model.AuditEvent, len(tasks))
+	for i := range tasks {
+		ids[i] = tasks[i].ID
+		after := tasks[i]
+		after.ArchivedAt = ts
+		events[i] = changeEvent(ctx, model.ActionArchive, tasks[i].ID, &tasks[i], &after)
+	}
+	res, err := tx.ExecContext(ctx,
+		`UPDATE tasks
+         SET archived_at = ?, updated_at = updated_at
+         WHERE id IN (`+placeholders(len(ids))+`)`,
+		append([]any{ts}, ids...)...,
+	)
+	if err != nil {
+		log.Error("failed to archive tasks", zap.Error(err))
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if err := addAuditEvents(ctx, tx, events...); err != nil {
+		log.Error("failed to record audit events", zap.Error(err))
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit", zap.Error(err))
+		return 0, err
+	}
+	return n, nil
+}
@@ -0,0 +1,199 @@
+//go:generate mockgen -source=audit.go -destination=mock_repository/mock_audit_repository.go -package=mock_repository
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"hearx/pkg/auth"
+	hlog "hearx/pkg/logger"
+	"hearx/pkg/model"
+	"hearx/pkg/requestid"
+	"hearx/pkg/storage"
+
+	"go.uber.org/zap"
+)
+
+// auditColumns is the column list every audit SELECT reads, in scanAuditEvent order.
+const auditColumns = `id, occurred_at, actor, action, task_id, request_id, detail, before_state, after_state`
+
+// AuditRepository reads the audit trail. Events are written by the
+// TaskRepository and CommentRepository, in the transaction of the change
+// they record, and there is no way to change or remove one.
+type AuditRepository interface {
+	FindAll(ctx context.Context, filter model.AuditFilter) ([]model.AuditEvent, error)
+}
+
+// mysqlAuditRepository is the MySQL implementation of AuditRepository.
+type mysqlAuditRepository struct {
+	db           *storage.Cluster
+	queryTimeout time.Duration
+	logger       *zap.Logger
+}
+
+// NewAuditRepository constructs a MySQL-backed AuditRepository.
+func NewAuditRepository(db *storage.Cluster, cfg storage.Config, logger *zap.Logger) AuditRepository {
+	return &mysqlAuditRepository{db: db, queryTimeout: cfg.QueryTimeout, logger: logger}
+}
+
+func (r *mysqlAuditRepository) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return boundQuery(ctx, r.queryTimeout)
+}
+
+type actionKey struct{}
+
+type action struct{ name, detail string }
+
+// WithAction names the audit action, and an optional detail, that task
+// writes made with ctx are recorded as. Without it each TaskRepository
+// write is recorded as its own kind of change, e.g. Update as
+// model.ActionUpdate and SetParent as model.ActionMove.
+func WithAction(ctx context.Context, name, detail string) context.Context {
+	return context.WithValue(ctx, actionKey{}, action{name: name, detail: detail})
+}
+
+// ActionFromContext returns what WithAction stored in ctx, if anything.
+func ActionFromContext(ctx context.Context) (name, detail string, ok bool) {
+	a, ok := ctx.Value(actionKey{}).(action)
+	return a.name, a.detail, ok
+}
+
+// changeEvent is the audit event for a write to task id, named by ctx or
+// else by fallback.
+func changeEvent(ctx context.Context, fallback string, id int64, before, after *model.Task) model.AuditEvent {
+	name, detail, ok := ActionFromContext(ctx)
+	if !ok {
+		name, detail = fallback, ""
+	}
+	return model.AuditEvent{Action: name, Detail: detail, TaskID: id, Before: before, After: after}
+}
+
+// addAuditEvents records events in tx, in one statement, stamped with the
+// caller and request ID in ctx. Like addRevision it runs in the write's own
+// transaction, so a change is committed with its events or not at all.
+func addAuditEvents(ctx context.Context, tx *sql.Tx, events ...model.AuditEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	actor, _ := auth.CallerFromContext(ctx)
+	reqID := requestid.FromContext(ctx)
+	ts := now()
+	args := make([]any, 0, len(events)*8)
+	for _, e := range events {
+		before, err := snapshot(e.Before)
+		if err != nil {
+			return err
+		}
+		after, err := snapshot(e.After)
+		if err != nil {
+			return err
+		}
+		args = append(args, ts, actor, e.Action, e.TaskID, reqID, e.Detail, before, after)
+	}
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO audit_events (occurred_at, actor, action, task_id, request_id, detail, before_state, after_state)
+         VALUES `+strings.TrimSuffix(strings.Repeat("(?, ?, ?, ?, ?, ?, ?, ?), ", len(events)), ", "),
+		args...,
+	)
+	return err
+}
+
+// FindAll lists the events matching filter in the order they were recorded.
+func (r *mysqlAuditRepository) FindAll(ctx context.Context, filter model.AuditFilter) ([]model.AuditEvent, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("querying audit events", zap.Int64("after_id", filter.AfterID), zap.Int("limit", filter.Limit))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT ` + auditColumns + ` FROM audit_events WHERE id > ?`
+	args := []any{filter.AfterID}
+	if filter.Actor != "" {
+		query += ` AND actor = ?`
+		args = append(args, filter.Actor)
+	}
+	if filter.Action != "" {
+		query += ` AND action = ?`
+		args = append(args, filter.Action)
+	}
+	if filter.TaskID != 0 {
+		query += ` AND task_id = ?`
+		args = append(args, filter.TaskID)
+	}
+	if !filter.Since.IsZero() {
+		query += ` AND occurred_at >= ?`
+		args = append(args, filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		query += ` AND occurred_at < ?`
+		args = append(args, filter.Until)
+	}
+	query += ` ORDER BY id`
+	if filter.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error("failed to query audit events", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []model.AuditEvent
+	for rows.Next() {
+		e, err := scanAuditEvent(rows)
+		if err != nil {
+			log.Error("failed to scan audit event", zap.Error(err))
+			return nil, err
+		}
+		list = append(list, e)
+	}
+	return list, rows.Err()
+}
+
+func scanAuditEvent(row rowScanner) (model.AuditEvent, error) {
+	var (
+		e             model.AuditEvent
+		before, after []byte
+	)
+	err := row.Scan(&e.ID, &e.OccurredAt, &e.Actor, &e.Action, &e.TaskID, &e.RequestID, &e.Detail, &before, &after)
+	if err != nil {
+		return model.AuditEvent{}, err
+	}
+	if e.Before, err = fromSnapshot(before); err != nil {
+		return model.AuditEvent{}, err
+	}
+	if e.After, err = fromSnapshot(after); err != nil {
+		return model.AuditEvent{}, err
+	}
+	return e, nil
+}
+
+// snapshot encodes a task for a JSON column; nil stays NULL.
+func snapshot(t *model.Task) (sql.NullString, error) {
+	if t == nil {
+		return sql.NullString{}, nil
+	}
+	b, err := json.Marshal(t)
+	if err != nil {
+		return sql.NullString{}, err
+	}
+	return sql.NullString{String: string(b), Valid: true}, nil
+}
+
+// fromSnapshot decodes what snapshot wrote; NULL reads back as nil.
+func fromSnapshot(b []byte) (*model.Task, error) {
+	if b == nil {
+		return nil, nil
+	}
+	var t model.Task
+	if err := json.Unmarshal(b, &t); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
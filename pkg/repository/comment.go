@@ -0,0 +1,188 @@
+//go:generate mockgen -source=comment.go -destination=mock_repository/mock_comment_repository.go -package=mock_repository
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	hlog "hearx/pkg/logger"
+	"hearx/pkg/model"
+	"hearx/pkg/storage"
+
+	"go.uber.org/zap"
+)
+
+// ErrCommentNotFound is returned when no comment has the requested ID.
+var ErrCommentNotFound = errors.New("comment not found")
+
+// commentColumns is the column list every comment SELECT reads, in scanComment order.
+const commentColumns = `id, task_id, author, body, created_at`
+
+// CommentRepository defines DB operations for task comments.
+type CommentRepository interface {
+	Create(ctx context.Context, comment model.Comment) (model.Comment, error)
+	FindByID(ctx context.Context, id int64) (model.Comment, error)
+	FindByTask(ctx context.Context, taskID, afterID int64, limit int) ([]model.Comment, error)
+	Delete(ctx context.Context, id int64) error
+}
+
+// mysqlCommentRepository is the MySQL implementation of CommentRepository.
+type mysqlCommentRepository struct {
+	db           *storage.Cluster
+	queryTimeout time.Duration
+	logger       *zap.Logger
+}
+
+// NewCommentRepository constructs a MySQL-backed CommentRepository.
+func NewCommentRepository(db *storage.Cluster, cfg storage.Config, logger *zap.Logger) CommentRepository {
+	return &mysqlCommentRepository{db: db, queryTimeout: cfg.QueryTimeout, logger: logger}
+}
+
+func (r *mysqlCommentRepository) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return boundQuery(ctx, r.queryTimeout)
+}
+
+func (r *mysqlCommentRepository) Create(ctx context.Context, c model.Comment) (model.Comment, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("inserting comment", zap.Int64("task_id", c.TaskID))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.Primary().BeginTx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", zap.Error(err))
+		return model.Comment{}, err
+	}
+	defer tx.Rollback()
+
+	c.CreatedAt = now()
+	res, err := tx.ExecContext(ctx,
+		`INSERT INTO task_comments (task_id, author, body, created_at)
+         VALUES (?, ?, ?, ?)`,
+		c.TaskID, c.Author, c.Body, c.CreatedAt,
+	)
+	if err != nil {
+		log.Error("failed to insert comment", zap.Error(err), zap.Int64("task_id", c.TaskID))
+		return model.Comment{}, err
+	}
+	if c.ID, err = res.LastInsertId(); err != nil {
+		log.Error("failed to read comment id", zap.Error(err))
+		return model.Comment{}, err
+	}
+	if err := addAuditEvents(ctx, tx, commentEvent(model.ActionCommentAdd, c)); err != nil {
+		log.Error("failed to record audit event", zap.Error(err), zap.Int64("id", c.ID))
+		return model.Comment{}, err
+	}
+	return c, tx.Commit()
+}
+
+func (r *mysqlCommentRepository) FindByID(ctx context.Context, id int64) (model.Comment, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("querying comment by id", zap.Int64("id", id))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	row := r.db.Reader(ctx).QueryRowContext(ctx,
+		`SELECT `+commentColumns+`
+         FROM task_comments
+         WHERE id = ?`,
+		id,
+	)
+	c, err := scanComment(row)
+	if err != nil {
+		log.Error("failed to query comment by id", zap.Error(err), zap.Int64("id", id))
+		return model.Comment{}, err
+	}
+	return c, nil
+}
+
+// FindByTask lists a task's comments oldest first, after afterID for keyset
+// pagination. A limit of zero or less means no limit.
+func (r *mysqlCommentRepository) FindByTask(ctx context.Context, taskID, afterID int64, limit int) ([]model.Comment, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("querying comments", zap.Int64("task_id", taskID), zap.Int64("after_id", afterID))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT ` + commentColumns + `
+         FROM task_comments
+         WHERE task_id = ? AND id > ?
+         ORDER BY id`
+	args := []any{taskID, afterID}
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error("failed to query comments", zap.Error(err), zap.Int64("task_id", taskID))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []model.Comment
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			log.Error("failed to scan comment", zap.Error(err))
+			return nil, err
+		}
+		list = append(list, c)
+	}
+	return list, rows.Err()
+}
+
+func (r *mysqlCommentRepository) Delete(ctx context.Context, id int64) error {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("deleting comment", zap.Int64("id", id))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.Primary().BeginTx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", zap.Error(err))
+		return err
+	}
+	defer tx.Rollback()
+
+	// lock the comment so the event names the task of the row deleted
+	c, err := scanComment(tx.QueryRowContext(ctx,
+		`SELECT `+commentColumns+`
+         FROM task_comments
+         WHERE id = ?
+         FOR UPDATE`,
+		id,
+	))
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM task_comments WHERE id = ?`, id); err != nil {
+		log.Error("failed to delete comment", zap.Error(err), zap.Int64("id", id))
+		return err
+	}
+	if err := addAuditEvents(ctx, tx, commentEvent(model.ActionCommentDelete, c)); err != nil {
+		log.Error("failed to record audit event", zap.Error(err), zap.Int64("id", id))
+		return err
+	}
+	return tx.Commit()
+}
+
+// commentEvent is the audit event for a comment written to or removed
+// from its task. The task itself is unchanged, so it has no before or
+// after state; the detail names the comment.
+func commentEvent(name string, c model.Comment) model.AuditEvent {
+	return model.AuditEvent{Action: name, TaskID: c.TaskID, Detail: fmt.Sprintf("comment %d", c.ID)}
+}
+
+func scanComment(row rowScanner) (model.Comment, error) {
+	var c model.Comment
+	err := row.Scan(&c.ID, &c.TaskID, &c.Author, &c.Body, &c.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = ErrCommentNotFound
+	}
+	return c, err
+}
@@ -8,6 +8,7 @@ import (
 	context "context"
 	model "hearx/pkg/model"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,34 +36,153 @@ func (m *MockTaskRepository) EXPECT() *MockTaskRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddDependency mocks base method.
+func (m *MockTaskRepository) AddDependency(ctx context.Context, taskID, blockerID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDependency", ctx, taskID, blockerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddDependency indicates an expected call of AddDependency.
+func (mr *MockTaskRepositoryMockRecorder) AddDependency(ctx, taskID, blockerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDependency", reflect.TypeOf((*MockTaskRepository)(nil).AddDependency), ctx, taskID, blockerID)
+}
+
+// Archive mocks base method.
+func (m *MockTaskRepository) Archive(ctx context.Context, completedBefore time.Time, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Archive", ctx, completedBefore, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Archive indicates an expected call of Archive.
+func (mr *MockTaskRepositoryMockRecorder) Archive(ctx, completedBefore, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Archive", reflect.TypeOf((*MockTaskRepository)(nil).Archive), ctx, completedBefore, limit)
+}
+
+// CountOpen mocks base method.
+func (m *MockTaskRepository) CountOpen(ctx context.Context, owner string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOpen", ctx, owner)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOpen indicates an expected call of CountOpen.
+func (mr *MockTaskRepositoryMockRecorder) CountOpen(ctx, owner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOpen", reflect.TypeOf((*MockTaskRepository)(nil).CountOpen), ctx, owner)
+}
+
+// CountOpenBlockers mocks base method.
+func (m *MockTaskRepository) CountOpenBlockers(ctx context.Context, taskID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOpenBlockers", ctx, taskID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOpenBlockers indicates an expected call of CountOpenBlockers.
+func (mr *MockTaskRepositoryMockRecorder) CountOpenBlockers(ctx, taskID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOpenBlockers", reflect.TypeOf((*MockTaskRepository)(nil).CountOpenBlockers), ctx, taskID)
+}
+
+// CountOpenChildren mocks base method.
+func (m *MockTaskRepository) CountOpenChildren(ctx context.Context, parentID int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOpenChildren", ctx, parentID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOpenChildren indicates an expected call of CountOpenChildren.
+func (mr *MockTaskRepositoryMockRecorder) CountOpenChildren(ctx, parentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOpenChildren", reflect.TypeOf((*MockTaskRepository)(nil).CountOpenChildren), ctx, parentID)
+}
+
 // Create mocks base method.
-func (m *MockTaskRepository) Create(ctx context.Context, task model.Task) (model.Task, error) {
+func (m *MockTaskRepository) Create(ctx context.Context, task model.Task, maxOpen int64) (model.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Create", ctx, task)
+	ret := m.ctrl.Call(m, "Create", ctx, task, maxOpen)
 	ret0, _ := ret[0].(model.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Create indicates an expected call of Create.
-func (mr *MockTaskRepositoryMockRecorder) Create(ctx, task interface{}) *gomock.Call {
+func (mr *MockTaskRepositoryMockRecorder) Create(ctx, task, maxOpen interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTaskRepository)(nil).Create), ctx, task, maxOpen)
+}
+
+// CreateBatch mocks base method.
+func (m *MockTaskRepository) CreateBatch(ctx context.Context, tasks []model.Task, maxOpen int64) ([]model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", ctx, tasks, maxOpen)
+	ret0, _ := ret[0].([]model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockTaskRepositoryMockRecorder) CreateBatch(ctx, tasks, maxOpen interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockTaskRepository)(nil).CreateBatch), ctx, tasks, maxOpen)
+}
+
+// Delete mocks base method.
+func (m *MockTaskRepository) Delete(ctx context.Context, id int64) (model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockTaskRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockTaskRepository)(nil).Create), ctx, task)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTaskRepository)(nil).Delete), ctx, id)
 }
 
 // FindAll mocks base method.
-func (m *MockTaskRepository) FindAll(ctx context.Context) ([]model.Task, error) {
+func (m *MockTaskRepository) FindAll(ctx context.Context, filter model.TaskFilter) ([]model.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAll", ctx)
+	ret := m.ctrl.Call(m, "FindAll", ctx, filter)
 	ret0, _ := ret[0].([]model.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // FindAll indicates an expected call of FindAll.
-func (mr *MockTaskRepositoryMockRecorder) FindAll(ctx interface{}) *gomock.Call {
+func (mr *MockTaskRepositoryMockRecorder) FindAll(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockTaskRepository)(nil).FindAll), ctx, filter)
+}
+
+// FindBlockers mocks base method.
+func (m *MockTaskRepository) FindBlockers(ctx context.Context, taskID int64) ([]model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindBlockers", ctx, taskID)
+	ret0, _ := ret[0].([]model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindBlockers indicates an expected call of FindBlockers.
+func (mr *MockTaskRepositoryMockRecorder) FindBlockers(ctx, taskID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockTaskRepository)(nil).FindAll), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindBlockers", reflect.TypeOf((*MockTaskRepository)(nil).FindBlockers), ctx, taskID)
 }
 
 // FindByID mocks base method.
@@ -80,6 +200,215 @@ func (mr *MockTaskRepositoryMockRecorder) FindByID(ctx, id interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockTaskRepository)(nil).FindByID), ctx, id)
 }
 
+// FindChildren mocks base method.
+func (m *MockTaskRepository) FindChildren(ctx context.Context, parentID int64) ([]model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindChildren", ctx, parentID)
+	ret0, _ := ret[0].([]model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindChildren indicates an expected call of FindChildren.
+func (mr *MockTaskRepositoryMockRecorder) FindChildren(ctx, parentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindChildren", reflect.TypeOf((*MockTaskRepository)(nil).FindChildren), ctx, parentID)
+}
+
+// FindDeletedByID mocks base method.
+func (m *MockTaskRepository) FindDeletedByID(ctx context.Context, id int64) (model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDeletedByID", ctx, id)
+	ret0, _ := ret[0].(model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDeletedByID indicates an expected call of FindDeletedByID.
+func (mr *MockTaskRepositoryMockRecorder) FindDeletedByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDeletedByID", reflect.TypeOf((*MockTaskRepository)(nil).FindDeletedByID), ctx, id)
+}
+
+// FindDependents mocks base method.
+func (m *MockTaskRepository) FindDependents(ctx context.Context, blockerID int64) ([]model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDependents", ctx, blockerID)
+	ret0, _ := ret[0].([]model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDependents indicates an expected call of FindDependents.
+func (mr *MockTaskRepositoryMockRecorder) FindDependents(ctx, blockerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDependents", reflect.TypeOf((*MockTaskRepository)(nil).FindDependents), ctx, blockerID)
+}
+
+// FindOpenDependencies mocks base method.
+func (m *MockTaskRepository) FindOpenDependencies(ctx context.Context) ([]model.Dependency, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindOpenDependencies", ctx)
+	ret0, _ := ret[0].([]model.Dependency)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindOpenDependencies indicates an expected call of FindOpenDependencies.
+func (mr *MockTaskRepositoryMockRecorder) FindOpenDependencies(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindOpenDependencies", reflect.TypeOf((*MockTaskRepository)(nil).FindOpenDependencies), ctx)
+}
+
+// FindRevision mocks base method.
+func (m *MockTaskRepository) FindRevision(ctx context.Context, taskID, revision int64) (model.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRevision", ctx, taskID, revision)
+	ret0, _ := ret[0].(model.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRevision indicates an expected call of FindRevision.
+func (mr *MockTaskRepositoryMockRecorder) FindRevision(ctx, taskID, revision interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRevision", reflect.TypeOf((*MockTaskRepository)(nil).FindRevision), ctx, taskID, revision)
+}
+
+// FindRevisions mocks base method.
+func (m *MockTaskRepository) FindRevisions(ctx context.Context, taskID, afterRevision int64, limit int) ([]model.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRevisions", ctx, taskID, afterRevision, limit)
+	ret0, _ := ret[0].([]model.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRevisions indicates an expected call of FindRevisions.
+func (mr *MockTaskRepositoryMockRecorder) FindRevisions(ctx, taskID, afterRevision, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRevisions", reflect.TypeOf((*MockTaskRepository)(nil).FindRevisions), ctx, taskID, afterRevision, limit)
+}
+
+// Purge mocks base method.
+func (m *MockTaskRepository) Purge(ctx context.Context, deletedBefore time.Time, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, deletedBefore, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockTaskRepositoryMockRecorder) Purge(ctx, deletedBefore, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockTaskRepository)(nil).Purge), ctx, deletedBefore, limit)
+}
+
+// RemoveDependency mocks base method.
+func (m *MockTaskRepository) RemoveDependency(ctx context.Context, taskID, blockerID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveDependency", ctx, taskID, blockerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveDependency indicates an expected call of RemoveDependency.
+func (mr *MockTaskRepositoryMockRecorder) RemoveDependency(ctx, taskID, blockerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveDependency", reflect.TypeOf((*MockTaskRepository)(nil).RemoveDependency), ctx, taskID, blockerID)
+}
+
+// Restore mocks base method.
+func (m *MockTaskRepository) Restore(ctx context.Context, state model.Task) (model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, state)
+	ret0, _ := ret[0].(model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockTaskRepositoryMockRecorder) Restore(ctx, state interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockTaskRepository)(nil).Restore), ctx, state)
+}
+
+// SetAssignee mocks base method.
+func (m *MockTaskRepository) SetAssignee(ctx context.Context, id int64, assignee string) (model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetAssignee", ctx, id, assignee)
+	ret0, _ := ret[0].(model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetAssignee indicates an expected call of SetAssignee.
+func (mr *MockTaskRepositoryMockRecorder) SetAssignee(ctx, id, assignee interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetAssignee", reflect.TypeOf((*MockTaskRepository)(nil).SetAssignee), ctx, id, assignee)
+}
+
+// SetParent mocks base method.
+func (m *MockTaskRepository) SetParent(ctx context.Context, id, parentID int64) (model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetParent", ctx, id, parentID)
+	ret0, _ := ret[0].(model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetParent indicates an expected call of SetParent.
+func (mr *MockTaskRepositoryMockRecorder) SetParent(ctx, id, parentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetParent", reflect.TypeOf((*MockTaskRepository)(nil).SetParent), ctx, id, parentID)
+}
+
+// SetProject mocks base method.
+func (m *MockTaskRepository) SetProject(ctx context.Context, id, projectID int64) (model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetProject", ctx, id, projectID)
+	ret0, _ := ret[0].(model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetProject indicates an expected call of SetProject.
+func (mr *MockTaskRepositoryMockRecorder) SetProject(ctx, id, projectID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProject", reflect.TypeOf((*MockTaskRepository)(nil).SetProject), ctx, id, projectID)
+}
+
+// SetText mocks base method.
+func (m *MockTaskRepository) SetText(ctx context.Context, id int64, title, description string) (model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetText", ctx, id, title, description)
+	ret0, _ := ret[0].(model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetText indicates an expected call of SetText.
+func (mr *MockTaskRepositoryMockRecorder) SetText(ctx, id, title, description interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetText", reflect.TypeOf((*MockTaskRepository)(nil).SetText), ctx, id, title, description)
+}
+
+// Undelete mocks base method.
+func (m *MockTaskRepository) Undelete(ctx context.Context, id int64) (model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Undelete", ctx, id)
+	ret0, _ := ret[0].(model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Undelete indicates an expected call of Undelete.
+func (mr *MockTaskRepositoryMockRecorder) Undelete(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undelete", reflect.TypeOf((*MockTaskRepository)(nil).Undelete), ctx, id)
+}
+
 // Update mocks base method.
 func (m *MockTaskRepository) Update(ctx context.Context, task model.Task) (model.Task, error) {
 	m.ctrl.T.Helper()
@@ -94,3 +423,19 @@ func (mr *MockTaskRepositoryMockRecorder) Update(ctx, task interface{}) *gomock.
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockTaskRepository)(nil).Update), ctx, task)
 }
+
+// UpdateAndCreate mocks base method.
+func (m *MockTaskRepository) UpdateAndCreate(ctx context.Context, task, next model.Task) (model.Task, model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAndCreate", ctx, task, next)
+	ret0, _ := ret[0].(model.Task)
+	ret1, _ := ret[1].(model.Task)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// UpdateAndCreate indicates an expected call of UpdateAndCreate.
+func (mr *MockTaskRepositoryMockRecorder) UpdateAndCreate(ctx, task, next interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAndCreate", reflect.TypeOf((*MockTaskRepository)(nil).UpdateAndCreate), ctx, task, next)
+}
@@ -5,100 +5,397 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
 
+	hlog "hearx/pkg/logger"
 	"hearx/pkg/model"
+	"hearx/pkg/storage"
 
 	"go.uber.org/zap"
 )
 
 // TaskRepository defines DB operations for tasks.
 type TaskRepository interface {
-	Create(ctx context.Context, task model.Task) (model.Task, error)
+	Create(ctx context.Context, task model.Task, maxOpen int64) (model.Task, error)
+	CreateBatch(ctx context.Context, tasks []model.Task, maxOpen int64) ([]model.Task, error)
 	Update(ctx context.Context, task model.Task) (model.Task, error)
-	FindAll(ctx context.Context) ([]model.Task, error)
+	SetText(ctx context.Context, id int64, title, description string) (model.Task, error)
+	UpdateAndCreate(ctx context.Context, task, next model.Task) (model.Task, model.Task, error)
+	FindAll(ctx context.Context, filter model.TaskFilter) ([]model.Task, error)
 	FindByID(ctx context.Context, id int64) (model.Task, error)
+	FindChildren(ctx context.Context, parentID int64) ([]model.Task, error)
+	SetParent(ctx context.Context, id, parentID int64) (model.Task, error)
+	SetProject(ctx context.Context, id, projectID int64) (model.Task, error)
+	SetAssignee(ctx context.Context, id int64, assignee string) (model.Task, error)
+	CountOpen(ctx context.Context, owner string) (int64, error)
+	CountOpenChildren(ctx context.Context, parentID int64) (int64, error)
+	AddDependency(ctx context.Context, taskID, blockerID int64) error
+	RemoveDependency(ctx context.Context, taskID, blockerID int64) error
+	FindBlockers(ctx context.Context, taskID int64) ([]model.Task, error)
+	FindDependents(ctx context.Context, blockerID int64) ([]model.Task, error)
+	FindOpenDependencies(ctx context.Context) ([]model.Dependency, error)
+	CountOpenBlockers(ctx context.Context, taskID int64) (int64, error)
+	Delete(ctx context.Context, id int64) (model.Task, error)
+	Restore(ctx context.Context, state model.Task) (model.Task, error)
+	FindRevisions(ctx context.Context, taskID, afterRevision int64, limit int) ([]model.Revision, error)
+	FindRevision(ctx context.Context, taskID, revision int64) (model.Revision, error)
+	FindDeletedByID(ctx context.Context, id int64) (model.Task, error)
+	Undelete(ctx context.Context, id int64) (model.Task, error)
+	Purge(ctx context.Context, deletedBefore time.Time, limit int) (int64, error)
+	Archive(ctx context.Context, completedBefore time.Time, limit int) (int64, error)
 }
 
 // mysqlTaskRepository is the MySQL implementation of TaskRepository.
+// Writes and the read-after-write in Update go to the primary; plain reads
+// are routed to a replica when one is healthy. Every write to a task records
+// the row it leaves in task_revisions, in the same transaction.
 type mysqlTaskRepository struct {
-	db     *sql.DB
-	logger *zap.Logger
+	db           *storage.Cluster
+	queryTimeout time.Duration
+	logger       *zap.Logger
 }
 
 // NewTaskRepository constructs a MySQL-backed TaskRepository.
-func NewTaskRepository(db *sql.DB, logger *zap.Logger) TaskRepository {
-	return &mysqlTaskRepository{db: db, logger: logger}
+func NewTaskRepository(db *storage.Cluster, cfg storage.Config, logger *zap.Logger) TaskRepository {
+	return &mysqlTaskRepository{db: db, queryTimeout: cfg.QueryTimeout, logger: logger}
 }
 
-func (r *mysqlTaskRepository) Create(ctx context.Context, task model.Task) (model.Task, error) {
-	r.logger.Info("creating task", zap.String("title", task.Title))
-	res, err := r.db.ExecContext(ctx,
-		`INSERT INTO tasks (title, description, completed)
-         VALUES (?, ?, ?)`,
-		task.Title, task.Description, task.Completed,
-	)
+// now is the creation timestamp, at the second precision TIMESTAMP stores.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+// withTimeout bounds a single query by the configured per-query timeout.
+func (r *mysqlTaskRepository) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return boundQuery(ctx, r.queryTimeout)
+}
+
+// boundQuery applies a per-query timeout; zero or less disables it.
+func boundQuery(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// insertTask and updateTask write every column a caller may set; the
+// matching insertArgs and updateArgs supply the values in order. Reopening
+// an archived task takes it out of the archive: MySQL assigns left to
+// right, so archived_at sees the new completed.
+const (
+	insertTask = `INSERT INTO tasks (title, description, completed, owner, assignee, parent_id, project_id, due_at, recurrence, created_at, updated_at)
+         VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	updateTask = `UPDATE tasks
+         SET title = ?, description = ?, completed = ?, archived_at = IF(completed, archived_at, NULL),
+             due_at = ?, recurrence = ?, updated_at = CURRENT_TIMESTAMP
+         WHERE id = ?`
+)
+
+func insertArgs(t model.Task) []any {
+	return []any{t.Title, t.Description, t.Completed, t.Owner, nullString(t.Assignee), nullID(t.ParentID), nullID(t.ProjectID), nullTime(t.DueAt), t.Recurrence, t.CreatedAt, t.UpdatedAt}
+}
+
+func updateArgs(t model.Task) []any {
+	return []any{t.Title, t.Description, t.Completed, nullTime(t.DueAt), t.Recurrence, t.ID}
+}
+
+// Create inserts a task. With maxOpen above zero it fails with
+// ErrQuotaExceeded, and inserts nothing, if an open task would take its
+// owner over maxOpen open tasks.
+func (r *mysqlTaskRepository) Create(ctx context.Context, task model.Task, maxOpen int64) (model.Task, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("creating task", zap.String("title", task.Title))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	created, err := r.create(ctx, []model.Task{task}, maxOpen)
 	if err != nil {
-		r.logger.Error("failed to create task", zap.Error(err), zap.String("title", task.Title))
+		log.Error("failed to create task", zap.Error(err), zap.String("title", task.Title))
 		return model.Task{}, err
 	}
-	id, err := res.LastInsertId()
+	log.Debug("task created", zap.Int64("id", created[0].ID))
+	return created[0], nil
+}
+
+// CreateBatch inserts tasks in one transaction: either all are created or
+// none. maxOpen applies to the batch as a whole, as in Create.
+func (r *mysqlTaskRepository) CreateBatch(ctx context.Context, tasks []model.Task, maxOpen int64) ([]model.Task, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("creating tasks", zap.Int("count", len(tasks)))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	created, err := r.create(ctx, tasks, maxOpen)
 	if err != nil {
-		r.logger.Error("failed to retrieve last insert id", zap.Error(err))
-		return model.Task{}, err
+		log.Error("failed to create tasks", zap.Error(err), zap.Int("count", len(tasks)))
+		return nil, err
 	}
-	task.ID = id
-	r.logger.Info("task created", zap.Int64("id", task.ID))
-	return task, nil
+	log.Debug("tasks created", zap.Int("count", len(created)))
+	return created, nil
+}
+
+// maxDeadlockAttempts bounds how often create runs a transaction that
+// MySQL rolled back to break a deadlock.
+const maxDeadlockAttempts = 3
+
+// create runs insertTasks in a transaction, again if MySQL picked it as a
+// deadlock victim: two first tasks of an owner lock the same gap of the
+// owner index, and MySQL breaks the tie by rolling one back.
+func (r *mysqlTaskRepository) create(ctx context.Context, tasks []model.Task, maxOpen int64) ([]model.Task, error) {
+	for attempt := 1; ; attempt++ {
+		created, err := r.insertTasks(ctx, tasks, maxOpen)
+		if !isDeadlock(err) || attempt == maxDeadlockAttempts {
+			return created, err
+		}
+		hlog.FromContext(ctx, r.logger).Warn("create deadlocked, retrying", zap.Int("attempt", attempt))
+	}
+}
+
+// insertTasks checks the quota of every owner in tasks and inserts them,
+// with their revisions and audit events, in one transaction. Rows are
+// inserted one by one so each gets its own LastInsertId; timestamps are set
+// here rather than defaulted so the caller gets them back without a second
+// query.
+func (r *mysqlTaskRepository) insertTasks(ctx context.Context, tasks []model.Task, maxOpen int64) ([]model.Task, error) {
+	tx, err := r.db.Primary().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if maxOpen > 0 {
+		if err := checkQuota(ctx, tx, tasks, maxOpen); err != nil {
+			return nil, err
+		}
+	}
+	stmt, err := tx.PrepareContext(ctx, insertTask)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+
+	created := make([]model.Task, 0, len(tasks))
+	events := make([]model.AuditEvent, 0, len(tasks))
+	ts := now()
+	for _, task := range tasks {
+		task.CreatedAt, task.UpdatedAt = ts, ts
+		res, err := stmt.ExecContext(ctx, insertArgs(task)...)
+		if err != nil {
+			return nil, err
+		}
+		if task.ID, err = res.LastInsertId(); err != nil {
+			return nil, err
+		}
+		if err := addRevision(ctx, tx, task, false); err != nil {
+			return nil, err
+		}
+		created = append(created, task)
+	}
+	for i := range created {
+		events = append(events, changeEvent(ctx, model.ActionAdd, created[i].ID, nil, &created[i]))
+	}
+	if err := addAuditEvents(ctx, tx, events...); err != nil {
+		return nil, err
+	}
+	return created, tx.Commit()
+}
+
+// checkQuota fails with ErrQuotaExceeded if the open tasks among tasks
+// would take any owner over maxOpen. It counts with a locking read, which
+// holds the owner's range of idx_tasks_owner_completed until tx ends, so a
+// concurrent create for the same owner waits for this one instead of
+// counting the same rows. Owners are locked in name order.
+func checkQuota(ctx context.Context, tx *sql.Tx, tasks []model.Task, maxOpen int64) error {
+	adding := map[string]int64{}
+	for _, t := range tasks {
+		if !t.Completed {
+			adding[t.Owner]++
+		}
+	}
+	owners := make([]string, 0, len(adding))
+	for owner := range adding {
+		owners = append(owners, owner)
+	}
+	sort.Strings(owners)
+	for _, owner := range owners {
+		var open int64
+		err := tx.QueryRowContext(ctx,
+			`SELECT COUNT(*)
+         FROM tasks
+         WHERE owner = ? AND completed = FALSE AND deleted_at IS NULL
+         FOR UPDATE`,
+			owner,
+		).Scan(&open)
+		if err != nil {
+			return err
+		}
+		if open+adding[owner] > maxOpen {
+			return fmt.Errorf("%w: %d of %d", ErrQuotaExceeded, open, maxOpen)
+		}
+	}
+	return nil
 }
 
 func (r *mysqlTaskRepository) Update(ctx context.Context, task model.Task) (model.Task, error) {
-	r.logger.Info("updating task", zap.Int64("id", task.ID))
-	_, err := r.db.ExecContext(ctx,
-		`UPDATE tasks
-         SET title = ?, description = ?, completed = ?, updated_at = CURRENT_TIMESTAMP
-         WHERE id = ?`,
-		task.Title, task.Description, task.Completed, task.ID,
-	)
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("updating task", zap.Int64("id", task.ID))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	updated, err := r.update(ctx, task.ID, false, model.ActionUpdate, updateTask, updateArgs(task)...)
 	if err != nil {
-		r.logger.Error("failed to update task", zap.Error(err), zap.Int64("id", task.ID))
+		log.Error("failed to update task", zap.Error(err), zap.Int64("id", task.ID))
 		return model.Task{}, err
 	}
 
-	// fetch the updated record directly
-	var updated model.Task
-	row := r.db.QueryRowContext(ctx,
-		`SELECT id, title, description, completed
-         FROM tasks
+	log.Debug("task update fetched", zap.Any("task", updated))
+	return updated, nil
+}
+
+// SetText changes only the title and description of a task, under its row
+// lock, so a concurrent write to any other column is never undone.
+func (r *mysqlTaskRepository) SetText(ctx context.Context, id int64, title, description string) (model.Task, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("updating task text", zap.Int64("id", id))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	updated, err := r.update(ctx, id, false, model.ActionUpdate,
+		`UPDATE tasks
+         SET title = ?, description = ?, updated_at = CURRENT_TIMESTAMP
          WHERE id = ? AND deleted_at IS NULL`,
-		task.ID,
+		title, description, id,
 	)
-	if scanErr := row.Scan(&updated.ID, &updated.Title, &updated.Description, &updated.Completed); scanErr != nil {
-		r.logger.Error("failed to fetch updated task", zap.Error(scanErr), zap.Int64("id", task.ID))
-		return model.Task{}, scanErr
+	if err != nil {
+		log.Error("failed to update task text", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
 	}
-
-	r.logger.Info("task update fetched", zap.Any("task", updated))
 	return updated, nil
 }
 
-func (r *mysqlTaskRepository) FindAll(ctx context.Context) ([]model.Task, error) {
-	r.logger.Info("querying all tasks")
-	rows, err := r.db.QueryContext(ctx,
-		`SELECT id, title, description, completed
+// UpdateAndCreate saves task and inserts next in one transaction, e.g. a
+// completed occurrence of a recurring task and the occurrence after it.
+// The update is recorded as completing task, unless ctx names another
+// action, and next as added. The row is locked first; if task was already
+// completed by then, a concurrent call got there first and nothing is
+// written: the stored task comes back with a zero next, so only one
+// successor is ever created.
+func (r *mysqlTaskRepository) UpdateAndCreate(ctx context.Context, task, next model.Task) (model.Task, model.Task, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("updating task and creating its successor", zap.Int64("id", task.ID))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.Primary().BeginTx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", zap.Error(err))
+		return model.Task{}, model.Task{}, err
+	}
+	defer tx.Rollback()
+
+	before, err := lockTask(ctx, tx, task.ID)
+	if err != nil {
+		log.Error("failed to lock task", zap.Error(err), zap.Int64("id", task.ID))
+		return model.Task{}, model.Task{}, err
+	}
+	if before.Completed {
+		log.Debug("task already completed, successor not created", zap.Int64("id", task.ID))
+		return before, model.Task{}, nil
+	}
+	if _, err := tx.ExecContext(ctx, updateTask, updateArgs(task)...); err != nil {
+		log.Error("failed to update task", zap.Error(err), zap.Int64("id", task.ID))
+		return model.Task{}, model.Task{}, err
+	}
+	next.CreatedAt = now()
+	next.UpdatedAt = next.CreatedAt
+	res, err := tx.ExecContext(ctx, insertTask, insertArgs(next)...)
+	if err != nil {
+		log.Error("failed to create task", zap.Error(err), zap.String("title", next.Title))
+		return model.Task{}, model.Task{}, err
+	}
+	if next.ID, err = res.LastInsertId(); err != nil {
+		log.Error("failed to retrieve last insert id", zap.Error(err))
+		return model.Task{}, model.Task{}, err
+	}
+	if err := addRevision(ctx, tx, next, false); err != nil {
+		log.Error("failed to record task revision", zap.Error(err), zap.Int64("id", next.ID))
+		return model.Task{}, model.Task{}, err
+	}
+	updated, err := reviseTask(ctx, tx, task.ID, false)
+	if err != nil {
+		log.Error("failed to fetch updated task", zap.Error(err), zap.Int64("id", task.ID))
+		return model.Task{}, model.Task{}, err
+	}
+	added := model.AuditEvent{
+		Action: model.ActionAdd, TaskID: next.ID, After: &next,
+		Detail: fmt.Sprintf("next occurrence of task %d", task.ID),
+	}
+	if err := addAuditEvents(ctx, tx, changeEvent(ctx, model.ActionComplete, task.ID, &before, &updated), added); err != nil {
+		log.Error("failed to record audit events", zap.Error(err), zap.Int64("id", task.ID))
+		return model.Task{}, model.Task{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit", zap.Error(err))
+		return model.Task{}, model.Task{}, err
+	}
+	log.Debug("task updated and successor created", zap.Int64("id", task.ID), zap.Int64("next_id", next.ID))
+	return updated, next, nil
+}
+
+func (r *mysqlTaskRepository) FindAll(ctx context.Context, filter model.TaskFilter) ([]model.Task, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("querying tasks", zap.Any("filter", filter))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	where := []string{"deleted_at IS NULL", "id > ?"}
+	switch {
+	case filter.Deleted:
+		where[0] = "deleted_at IS NOT NULL"
+	case !filter.IncludeArchived:
+		where = append(where, "archived_at IS NULL")
+	}
+	args := []any{filter.AfterID}
+	if filter.Owner != "" {
+		where = append(where, "owner = ?")
+		args = append(args, filter.Owner)
+	}
+	if filter.ProjectID != 0 {
+		where = append(where, "project_id = ?")
+		args = append(args, filter.ProjectID)
+	}
+	if filter.Assignee != "" {
+		where = append(where, "assignee = ?")
+		args = append(args, filter.Assignee)
+	}
+	if filter.Ready {
+		where = append(where, "completed = FALSE", openBlockerFree)
+	}
+	query := `SELECT ` + taskColumns + `
          FROM tasks
-         WHERE deleted_at IS NULL`,
-	)
+         WHERE ` + strings.Join(where, " AND ") + `
+         ORDER BY id`
+	if filter.Limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, query, args...)
 	if err != nil {
-		r.logger.Error("failed to query tasks", zap.Error(err))
+		log.Error("failed to query tasks", zap.Error(err))
 		return nil, err
 	}
 	defer rows.Close()
 
 	var list []model.Task
 	for rows.Next() {
-		var t model.Task
-		if err := rows.Scan(&t.ID, &t.Title, &t.Description, &t.Completed); err != nil {
-			r.logger.Error("row scan error", zap.Error(err))
+		t, err := scanTask(rows)
+		if err != nil {
+			log.Error("row scan error", zap.Error(err))
 			return nil, err
 		}
 		list = append(list, t)
@@ -107,17 +404,365 @@ func (r *mysqlTaskRepository) FindAll(ctx context.Context) ([]model.Task, error)
 }
 
 func (r *mysqlTaskRepository) FindByID(ctx context.Context, id int64) (model.Task, error) {
-	r.logger.Info("querying task by id", zap.Int64("id", id))
-	row := r.db.QueryRowContext(ctx,
-		`SELECT id, title, description, completed
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("querying task by id", zap.Int64("id", id))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	row := r.db.Reader(ctx).QueryRowContext(ctx,
+		`SELECT `+taskColumns+`
          FROM tasks
          WHERE id = ? AND deleted_at IS NULL`,
 		id,
 	)
-	var t model.Task
-	if err := row.Scan(&t.ID, &t.Title, &t.Description, &t.Completed); err != nil {
-		r.logger.Error("failed to query task by id", zap.Error(err), zap.Int64("id", id))
+	t, err := scanTask(row)
+	if err != nil {
+		log.Error("failed to query task by id", zap.Error(err), zap.Int64("id", id))
 		return model.Task{}, err
 	}
 	return t, nil
 }
+
+// FindChildren returns the direct children of a task, ordered by ID. It reads
+// from a replica like FindAll.
+func (r *mysqlTaskRepository) FindChildren(ctx context.Context, parentID int64) ([]model.Task, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("querying child tasks", zap.Int64("parent_id", parentID))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.db.Reader(ctx).QueryContext(ctx,
+		`SELECT `+taskColumns+`
+         FROM tasks
+         WHERE parent_id = ? AND deleted_at IS NULL
+         ORDER BY id`,
+		parentID,
+	)
+	if err != nil {
+		log.Error("failed to query child tasks", zap.Error(err), zap.Int64("parent_id", parentID))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []model.Task
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			log.Error("row scan error", zap.Error(err))
+			return nil, err
+		}
+		list = append(list, t)
+	}
+	return list, rows.Err()
+}
+
+// SetParent moves a task under parentID, or to the top level when it is zero.
+func (r *mysqlTaskRepository) SetParent(ctx context.Context, id, parentID int64) (model.Task, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("moving task", zap.Int64("id", id), zap.Int64("parent_id", parentID))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	moved, err := r.update(ctx, id, false, model.ActionMove,
+		`UPDATE tasks
+         SET parent_id = ?, updated_at = CURRENT_TIMESTAMP
+         WHERE id = ? AND deleted_at IS NULL`,
+		nullID(parentID), id,
+	)
+	if err != nil {
+		log.Error("failed to move task", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	return moved, nil
+}
+
+// SetProject moves a task into a project, or out of any when it is zero.
+func (r *mysqlTaskRepository) SetProject(ctx context.Context, id, projectID int64) (model.Task, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("moving task to project", zap.Int64("id", id), zap.Int64("project_id", projectID))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	moved, err := r.update(ctx, id, false, model.ActionSetProject,
+		`UPDATE tasks
+         SET project_id = ?, updated_at = CURRENT_TIMESTAMP
+         WHERE id = ? AND deleted_at IS NULL`,
+		nullID(projectID), id,
+	)
+	if err != nil {
+		log.Error("failed to move task to project", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	return moved, nil
+}
+
+// SetAssignee makes a user responsible for a task, or nobody when assignee
+// is empty.
+func (r *mysqlTaskRepository) SetAssignee(ctx context.Context, id int64, assignee string) (model.Task, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("assigning task", zap.Int64("id", id), zap.String("assignee", assignee))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	kind := model.ActionAssign
+	if assignee == "" {
+		kind = model.ActionUnassign
+	}
+	assigned, err := r.update(ctx, id, false, kind,
+		`UPDATE tasks
+         SET assignee = ?, updated_at = CURRENT_TIMESTAMP
+         WHERE id = ? AND deleted_at IS NULL`,
+		nullString(assignee), id,
+	)
+	if err != nil {
+		log.Error("failed to assign task", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	return assigned, nil
+}
+
+// CountOpen counts the owner's tasks that are neither completed nor deleted.
+// It reads from the primary, but takes no lock: Create and CreateBatch
+// enforce the quota themselves, atomically.
+func (r *mysqlTaskRepository) CountOpen(ctx context.Context, owner string) (int64, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("counting open tasks", zap.String("owner", owner))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var n int64
+	err := r.db.Primary().QueryRowContext(ctx,
+		`SELECT COUNT(*)
+         FROM tasks
+         WHERE owner = ? AND completed = FALSE AND deleted_at IS NULL`,
+		owner,
+	).Scan(&n)
+	if err != nil {
+		log.Error("failed to count open tasks", zap.Error(err), zap.String("owner", owner))
+		return 0, err
+	}
+	return n, nil
+}
+
+// CountOpenChildren counts the uncompleted direct children of a task. Like
+// CountOpen it reads from the primary, as it decides a write.
+func (r *mysqlTaskRepository) CountOpenChildren(ctx context.Context, parentID int64) (int64, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("counting open child tasks", zap.Int64("parent_id", parentID))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var n int64
+	err := r.db.Primary().QueryRowContext(ctx,
+		`SELECT COUNT(*)
+         FROM tasks
+         WHERE parent_id = ? AND completed = FALSE AND deleted_at IS NULL`,
+		parentID,
+	).Scan(&n)
+	if err != nil {
+		log.Error("failed to count open child tasks", zap.Error(err), zap.Int64("parent_id", parentID))
+		return 0, err
+	}
+	return n, nil
+}
+
+// openBlockerFree matches tasks with no open blocker; tasks is the outer table.
+const openBlockerFree = `NOT EXISTS (
+           SELECT 1 FROM task_dependencies d JOIN tasks b ON b.id = d.blocker_id
+           WHERE d.task_id = tasks.id AND b.completed = FALSE AND b.deleted_at IS NULL)`
+
+// AddDependency records that taskID cannot be completed before blockerID.
+// Adding an existing dependency is a no-op and records no audit event.
+func (r *mysqlTaskRepository) AddDependency(ctx context.Context, taskID, blockerID int64) error {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("adding dependency", zap.Int64("task_id", taskID), zap.Int64("blocker_id", blockerID))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	err := r.changeDependency(ctx, model.ActionAddDependency, taskID, blockerID,
+		`INSERT IGNORE INTO task_dependencies (task_id, blocker_id) VALUES (?, ?)`,
+	)
+	if errors.Is(err, errNoChange) {
+		return nil
+	}
+	if err != nil {
+		log.Error("failed to add dependency", zap.Error(err), zap.Int64("task_id", taskID))
+	}
+	return err
+}
+
+// RemoveDependency deletes a dependency, or returns ErrNotFound if there is
+// none.
+func (r *mysqlTaskRepository) RemoveDependency(ctx context.Context, taskID, blockerID int64) error {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("removing dependency", zap.Int64("task_id", taskID), zap.Int64("blocker_id", blockerID))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	err := r.changeDependency(ctx, model.ActionRemoveDependency, taskID, blockerID,
+		`DELETE FROM task_dependencies WHERE task_id = ? AND blocker_id = ?`,
+	)
+	if errors.Is(err, errNoChange) {
+		return fmt.Errorf("%w: task %d does not depend on %d", ErrNotFound, taskID, blockerID)
+	}
+	if err != nil {
+		log.Error("failed to remove dependency", zap.Error(err), zap.Int64("task_id", taskID))
+	}
+	return err
+}
+
+// errNoChange is returned by changeDependency when query changed no row.
+var errNoChange = errors.New("no row changed")
+
+// changeDependency runs query with taskID and blockerID and, if it changed
+// a row, records an audit event naming the blocker in the same
+// transaction. Dependencies do not change the task itself, so the event
+// carries no snapshots.
+func (r *mysqlTaskRepository) changeDependency(ctx context.Context, fallback string, taskID, blockerID int64, query string) error {
+	tx, err := r.db.Primary().BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, query, taskID, blockerID)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return errNoChange
+	}
+	e := changeEvent(ctx, fallback, taskID, nil, nil)
+	e.Detail = fmt.Sprintf("blocker %d", blockerID)
+	if err := addAuditEvents(ctx, tx, e); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// FindBlockers returns the live tasks taskID depends on, completed or not,
+// ordered by ID.
+func (r *mysqlTaskRepository) FindBlockers(ctx context.Context, taskID int64) ([]model.Task, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("querying blockers", zap.Int64("task_id", taskID))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.db.Reader(ctx).QueryContext(ctx,
+		`SELECT `+taskColumns+`
+         FROM tasks
+         WHERE deleted_at IS NULL
+           AND id IN (SELECT blocker_id FROM task_dependencies WHERE task_id = ?)
+         ORDER BY id`,
+		taskID,
+	)
+	if err != nil {
+		log.Error("failed to query blockers", zap.Error(err), zap.Int64("task_id", taskID))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []model.Task
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			log.Error("row scan error", zap.Error(err))
+			return nil, err
+		}
+		list = append(list, t)
+	}
+	return list, rows.Err()
+}
+
+// FindDependents returns the live tasks that depend on blockerID, ordered by
+// ID. blockerID itself may be deleted.
+func (r *mysqlTaskRepository) FindDependents(ctx context.Context, blockerID int64) ([]model.Task, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("querying dependents", zap.Int64("blocker_id", blockerID))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.db.Reader(ctx).QueryContext(ctx,
+		`SELECT `+taskColumns+`
+         FROM tasks
+         WHERE deleted_at IS NULL
+           AND id IN (SELECT task_id FROM task_dependencies WHERE blocker_id = ?)
+         ORDER BY id`,
+		blockerID,
+	)
+	if err != nil {
+		log.Error("failed to query dependents", zap.Error(err), zap.Int64("blocker_id", blockerID))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []model.Task
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			log.Error("row scan error", zap.Error(err))
+			return nil, err
+		}
+		list = append(list, t)
+	}
+	return list, rows.Err()
+}
+
+// FindOpenDependencies returns the dependencies between live, open tasks,
+// ordered by task and blocker ID.
+func (r *mysqlTaskRepository) FindOpenDependencies(ctx context.Context) ([]model.Dependency, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("querying open dependencies")
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.db.Reader(ctx).QueryContext(ctx,
+		`SELECT d.task_id, d.blocker_id
+         FROM task_dependencies d
+         JOIN tasks t ON t.id = d.task_id
+         JOIN tasks b ON b.id = d.blocker_id
+         WHERE t.completed = FALSE AND t.deleted_at IS NULL
+           AND b.completed = FALSE AND b.deleted_at IS NULL
+         ORDER BY d.task_id, d.blocker_id`,
+	)
+	if err != nil {
+		log.Error("failed to query open dependencies", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []model.Dependency
+	for rows.Next() {
+		var d model.Dependency
+		if err := rows.Scan(&d.TaskID, &d.BlockerID); err != nil {
+			log.Error("row scan error", zap.Error(err))
+			return nil, err
+		}
+		list = append(list, d)
+	}
+	return list, rows.Err()
+}
+
+// CountOpenBlockers counts the uncompleted tasks taskID depends on. It reads
+// from the primary, as it decides a write.
+func (r *mysqlTaskRepository) CountOpenBlockers(ctx context.Context, taskID int64) (int64, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("counting open blockers", zap.Int64("task_id", taskID))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var n int64
+	err := r.db.Primary().QueryRowContext(ctx,
+		`SELECT COUNT(*)
+         FROM task_dependencies d JOIN tasks b ON b.id = d.blocker_id
+         WHERE d.task_id = ? AND b.completed = FALSE AND b.deleted_at IS NULL`,
+		taskID,
+	).Scan(&n)
+	if err != nil {
+		log.Error("failed to count open blockers", zap.Error(err), zap.Int64("task_id", taskID))
+		return 0, err
+	}
+	return n, nil
+}
@@ -0,0 +1,227 @@
+//go:generate mockgen -source=project.go -destination=mock_repository/mock_project_repository.go -package=mock_repository
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	hlog "hearx/pkg/logger"
+	"hearx/pkg/model"
+	"hearx/pkg/storage"
+
+	"github.com/go-sql-driver/mysql"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrProjectNotFound is returned when no project has the requested ID.
+	ErrProjectNotFound = errors.New("project not found")
+	// ErrProjectExists is returned when another project already has the name.
+	ErrProjectExists = errors.New("project name already in use")
+)
+
+// errDuplicateKey is MySQL's ER_DUP_ENTRY.
+const errDuplicateKey = 1062
+
+// errDeadlock is MySQL's ER_LOCK_DEADLOCK: the transaction was rolled back.
+const errDeadlock = 1213
+
+// projectColumns is the column list every project SELECT reads, in scanProject order.
+const projectColumns = `id, name, description, archived, owner, created_at, updated_at`
+
+// ProjectRepository defines DB operations for projects.
+type ProjectRepository interface {
+	Create(ctx context.Context, project model.Project) (model.Project, error)
+	Update(ctx context.Context, project model.Project) (model.Project, error)
+	FindByID(ctx context.Context, id int64) (model.Project, error)
+	FindAll(ctx context.Context, includeArchived bool) ([]model.Project, error)
+	Delete(ctx context.Context, id int64) error
+	Stats(ctx context.Context, id int64) (model.ProjectStats, error)
+}
+
+// mysqlProjectRepository is the MySQL implementation of ProjectRepository.
+type mysqlProjectRepository struct {
+	db           *storage.Cluster
+	queryTimeout time.Duration
+	logger       *zap.Logger
+}
+
+// NewProjectRepository constructs a MySQL-backed ProjectRepository.
+func NewProjectRepository(db *storage.Cluster, cfg storage.Config, logger *zap.Logger) ProjectRepository {
+	return &mysqlProjectRepository{db: db, queryTimeout: cfg.QueryTimeout, logger: logger}
+}
+
+func (r *mysqlProjectRepository) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return boundQuery(ctx, r.queryTimeout)
+}
+
+func (r *mysqlProjectRepository) Create(ctx context.Context, p model.Project) (model.Project, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("inserting project", zap.String("name", p.Name))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	p.CreatedAt = now()
+	p.UpdatedAt = p.CreatedAt
+	res, err := r.db.Primary().ExecContext(ctx,
+		`INSERT INTO projects (name, description, archived, owner, created_at, updated_at)
+         VALUES (?, ?, ?, ?, ?, ?)`,
+		p.Name, p.Description, p.Archived, p.Owner, p.CreatedAt, p.UpdatedAt,
+	)
+	if err != nil {
+		log.Error("failed to insert project", zap.Error(err))
+		return model.Project{}, duplicateName(err)
+	}
+	if p.ID, err = res.LastInsertId(); err != nil {
+		log.Error("failed to read project id", zap.Error(err))
+		return model.Project{}, err
+	}
+	return p, nil
+}
+
+func (r *mysqlProjectRepository) Update(ctx context.Context, p model.Project) (model.Project, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("updating project", zap.Int64("id", p.ID))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	_, err := r.db.Primary().ExecContext(ctx,
+		`UPDATE projects
+         SET name = ?, description = ?, archived = ?, updated_at = CURRENT_TIMESTAMP
+         WHERE id = ?`,
+		p.Name, p.Description, p.Archived, p.ID,
+	)
+	if err != nil {
+		log.Error("failed to update project", zap.Error(err), zap.Int64("id", p.ID))
+		return model.Project{}, duplicateName(err)
+	}
+	return r.FindByID(storage.WithPrimary(ctx), p.ID)
+}
+
+func (r *mysqlProjectRepository) FindByID(ctx context.Context, id int64) (model.Project, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("querying project by id", zap.Int64("id", id))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	row := r.db.Reader(ctx).QueryRowContext(ctx,
+		`SELECT `+projectColumns+`
+         FROM projects
+         WHERE id = ?`,
+		id,
+	)
+	p, err := scanProject(row)
+	if err != nil {
+		log.Error("failed to query project by id", zap.Error(err), zap.Int64("id", id))
+		return model.Project{}, err
+	}
+	return p, nil
+}
+
+// FindAll lists projects by name. Archived ones are left out unless asked for.
+func (r *mysqlProjectRepository) FindAll(ctx context.Context, includeArchived bool) ([]model.Project, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("querying projects", zap.Bool("include_archived", includeArchived))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT ` + projectColumns + ` FROM projects`
+	if !includeArchived {
+		query += ` WHERE archived = FALSE`
+	}
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, query+` ORDER BY name`)
+	if err != nil {
+		log.Error("failed to query projects", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []model.Project
+	for rows.Next() {
+		p, err := scanProject(rows)
+		if err != nil {
+			log.Error("failed to scan project", zap.Error(err))
+			return nil, err
+		}
+		list = append(list, p)
+	}
+	return list, rows.Err()
+}
+
+// Delete removes a project. Its tasks stay, outside of any project.
+func (r *mysqlProjectRepository) Delete(ctx context.Context, id int64) error {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("deleting project", zap.Int64("id", id))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	res, err := r.db.Primary().ExecContext(ctx, `DELETE FROM projects WHERE id = ?`, id)
+	if err != nil {
+		log.Error("failed to delete project", zap.Error(err), zap.Int64("id", id))
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrProjectNotFound
+	}
+	return nil
+}
+
+// Stats counts the live tasks of a project by state.
+func (r *mysqlProjectRepository) Stats(ctx context.Context, id int64) (model.ProjectStats, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("counting project tasks", zap.Int64("id", id))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var s model.ProjectStats
+	err := r.db.Reader(ctx).QueryRowContext(ctx,
+		`SELECT COUNT(*),
+                COALESCE(SUM(completed), 0),
+                COALESCE(SUM(NOT completed AND due_at < CURRENT_TIMESTAMP), 0)
+         FROM tasks
+         WHERE project_id = ? AND deleted_at IS NULL`,
+		id,
+	).Scan(&s.Total, &s.Completed, &s.Overdue)
+	if err != nil {
+		log.Error("failed to count project tasks", zap.Error(err), zap.Int64("id", id))
+		return model.ProjectStats{}, err
+	}
+	s.Open = s.Total - s.Completed
+	return s, nil
+}
+
+func scanProject(row rowScanner) (model.Project, error) {
+	var (
+		p    model.Project
+		desc sql.NullString
+	)
+	err := row.Scan(&p.ID, &p.Name, &desc, &p.Archived, &p.Owner, &p.CreatedAt, &p.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = ErrProjectNotFound
+	}
+	p.Description = desc.String
+	return p, err
+}
+
+// isDeadlock reports whether err is MySQL rolling a transaction back to
+// break a deadlock; running it again normally succeeds.
+func isDeadlock(err error) bool {
+	var me *mysql.MySQLError
+	return errors.As(err, &me) && me.Number == errDeadlock
+}
+
+// duplicateName turns a unique key violation on the name into ErrProjectExists.
+func duplicateName(err error) error {
+	var me *mysql.MySQLError
+	if errors.As(err, &me) && me.Number == errDuplicateKey {
+		return ErrProjectExists
+	}
+	return err
+}
@@ -0,0 +1,249 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	hlog "hearx/pkg/logger"
+	"hearx/pkg/model"
+
+	"go.uber.org/zap"
+)
+
+// revisionColumns is the column list every revision SELECT reads, in
+// scanRevision order.
+const revisionColumns = `task_id, revision, state, deleted, created_at`
+
+// addRevision records task, as the write in tx left it, as the task's next
+// revision. The write holds the task's row lock, so numbering cannot race.
+func addRevision(ctx context.Context, tx *sql.Tx, task model.Task, deleted bool) error {
+	state, err := snapshot(&task)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO task_revisions (task_id, revision, state, deleted, created_at)
+         SELECT ?, COALESCE(MAX(revision), 0) + 1, ?, ?, ?
+         FROM task_revisions
+         WHERE task_id = ?`,
+		task.ID, state, deleted, now(), task.ID,
+	)
+	return err
+}
+
+// reviseTask reads task id back inside tx and records it as a revision.
+// deleted says whether the write soft-deleted the task; a row in the other
+// state returns ErrNotFound.
+func reviseTask(ctx context.Context, tx *sql.Tx, id int64, deleted bool) (model.Task, error) {
+	cond := "deleted_at IS NULL"
+	if deleted {
+		cond = "deleted_at IS NOT NULL"
+	}
+	row := tx.QueryRowContext(ctx,
+		`SELECT `+taskColumns+`
+         FROM tasks
+         WHERE id = ? AND `+cond,
+		id,
+	)
+	t, err := scanTask(row)
+	if err != nil {
+		return model.Task{}, err
+	}
+	if err := addRevision(ctx, tx, t, deleted); err != nil {
+		return model.Task{}, err
+	}
+	return t, nil
+}
+
+// lockTask reads task id, deleted or not, inside tx and locks its row until
+// tx ends, so it is the state the write that follows starts from.
+func lockTask(ctx context.Context, tx *sql.Tx, id int64) (model.Task, error) {
+	row := tx.QueryRowContext(ctx,
+		`SELECT `+taskColumns+`
+         FROM tasks
+         WHERE id = ?
+         FOR UPDATE`,
+		id,
+	)
+	return scanTask(row)
+}
+
+// update runs query, an UPDATE of the single task id, and records the row
+// it leaves as a revision and as an audit event of kind fallback, unless
+// ctx names another, all in one transaction. The row is read back from the
+// primary, so replica lag cannot hide the write.
+func (r *mysqlTaskRepository) update(ctx context.Context, id int64, deleted bool, fallback, query string, args ...any) (model.Task, error) {
+	tx, err := r.db.Primary().BeginTx(ctx, nil)
+	if err != nil {
+		return model.Task{}, err
+	}
+	defer tx.Rollback()
+
+	before, err := lockTask(ctx, tx, id)
+	if err != nil {
+		return model.Task{}, err
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return model.Task{}, err
+	}
+	t, err := reviseTask(ctx, tx, id, deleted)
+	if err != nil {
+		return model.Task{}, err
+	}
+	if err := addAuditEvents(ctx, tx, changeEvent(ctx, fallback, id, &before, &t)); err != nil {
+		return model.Task{}, err
+	}
+	return t, tx.Commit()
+}
+
+// Delete soft-deletes a task: it disappears from every listing but keeps
+// its history and can be restored. A task that is already deleted returns
+// ErrNotFound.
+func (r *mysqlTaskRepository) Delete(ctx context.Context, id int64) (model.Task, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("deleting task", zap.Int64("id", id))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.Primary().BeginTx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", zap.Error(err))
+		return model.Task{}, err
+	}
+	defer tx.Rollback()
+
+	before, err := lockTask(ctx, tx, id)
+	if err != nil {
+		return model.Task{}, err
+	}
+	if !before.DeletedAt.IsZero() {
+		return model.Task{}, fmt.Errorf("%w: %d", ErrNotFound, id)
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE tasks
+         SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+         WHERE id = ?`,
+		id,
+	); err != nil {
+		log.Error("failed to delete task", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	deleted, err := reviseTask(ctx, tx, id, true)
+	if err != nil {
+		log.Error("failed to record deleted task", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	// the task is gone from every listing, so the event has no after state
+	if err := addAuditEvents(ctx, tx, changeEvent(ctx, model.ActionDelete, id, &before, nil)); err != nil {
+		log.Error("failed to record audit event", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit", zap.Error(err))
+		return model.Task{}, err
+	}
+	return deleted, nil
+}
+
+// Restore writes every field a caller may set back from state, undeleting
+// the task if it was deleted, and records the result as a new revision.
+func (r *mysqlTaskRepository) Restore(ctx context.Context, state model.Task) (model.Task, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("restoring task", zap.Int64("id", state.ID))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	restored, err := r.update(ctx, state.ID, false, model.ActionRestore,
+		`UPDATE tasks
+         SET title = ?, description = ?, completed = ?, archived_at = IF(completed, archived_at, NULL),
+             assignee = ?, parent_id = ?, project_id = ?, due_at = ?, recurrence = ?,
+             deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+         WHERE id = ?`,
+		state.Title, state.Description, state.Completed, nullString(state.Assignee), nullID(state.ParentID), nullID(state.ProjectID),
+		nullTime(state.DueAt), state.Recurrence, state.ID,
+	)
+	if err != nil {
+		log.Error("failed to restore task", zap.Error(err), zap.Int64("id", state.ID))
+		return model.Task{}, err
+	}
+	return restored, nil
+}
+
+// FindRevisions pages through a task's revisions, deleted task or not,
+// oldest first.
+func (r *mysqlTaskRepository) FindRevisions(ctx context.Context, taskID, afterRevision int64, limit int) ([]model.Revision, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("querying task revisions", zap.Int64("task_id", taskID), zap.Int64("after", afterRevision))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	query := `SELECT ` + revisionColumns + `
+         FROM task_revisions
+         WHERE task_id = ? AND revision > ?
+         ORDER BY revision`
+	args := []any{taskID, afterRevision}
+	if limit > 0 {
+		query += ` LIMIT ?`
+		args = append(args, limit)
+	}
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		log.Error("failed to query task revisions", zap.Error(err), zap.Int64("task_id", taskID))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []model.Revision
+	for rows.Next() {
+		rev, err := scanRevision(rows)
+		if err != nil {
+			log.Error("row scan error", zap.Error(err))
+			return nil, err
+		}
+		list = append(list, rev)
+	}
+	return list, rows.Err()
+}
+
+// FindRevision returns one revision of a task, or ErrNotFound.
+func (r *mysqlTaskRepository) FindRevision(ctx context.Context, taskID, revision int64) (model.Revision, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("querying task revision", zap.Int64("task_id", taskID), zap.Int64("revision", revision))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	row := r.db.Reader(ctx).QueryRowContext(ctx,
+		`SELECT `+revisionColumns+`
+         FROM task_revisions
+         WHERE task_id = ? AND revision = ?`,
+		taskID, revision,
+	)
+	rev, err := scanRevision(row)
+	if err != nil {
+		log.Error("failed to query task revision", zap.Error(err), zap.Int64("task_id", taskID))
+		return model.Revision{}, err
+	}
+	return rev, nil
+}
+
+func scanRevision(row rowScanner) (model.Revision, error) {
+	var (
+		rev   model.Revision
+		state []byte
+	)
+	err := row.Scan(&rev.TaskID, &rev.Number, &state, &rev.Deleted, &rev.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Revision{}, ErrNotFound
+	}
+	if err != nil {
+		return model.Revision{}, err
+	}
+	t, err := fromSnapshot(state)
+	if err != nil {
+		return model.Revision{}, err
+	}
+	rev.Task = *t
+	return rev, nil
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"hearx/pkg/model"
+)
+
+// ErrNotFound is returned when no live task has the requested ID.
+var ErrNotFound = errors.New("task not found")
+
+// ErrQuotaExceeded is returned by Create and CreateBatch when the owner
+// already has the maximum number of open tasks.
+var ErrQuotaExceeded = errors.New("open task quota exceeded")
+
+// taskColumns is the column list every task SELECT reads, in scanTask order.
+const taskColumns = `id, title, description, completed, owner, assignee, parent_id, project_id, due_at, recurrence, created_at, updated_at, deleted_at, archived_at`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows.
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanTask(row rowScanner) (model.Task, error) {
+	var (
+		t        model.Task
+		assignee sql.NullString
+		parent   sql.NullInt64
+		project  sql.NullInt64
+		due      sql.NullTime
+		deleted  sql.NullTime
+		archived sql.NullTime
+	)
+	err := row.Scan(&t.ID, &t.Title, &t.Description, &t.Completed, &t.Owner, &assignee, &parent, &project, &due, &t.Recurrence, &t.CreatedAt, &t.UpdatedAt, &deleted, &archived)
+	if errors.Is(err, sql.ErrNoRows) {
+		err = ErrNotFound
+	}
+	t.Assignee = assignee.String
+	t.ParentID = parent.Int64
+	t.ProjectID = project.Int64
+	if due.Valid {
+		t.DueAt = due.Time.UTC()
+	}
+	if deleted.Valid {
+		t.DeletedAt = deleted.Time.UTC()
+	}
+	if archived.Valid {
+		t.ArchivedAt = archived.Time.UTC()
+	}
+	return t, err
+}
+
+// nullID stores a zero ID as NULL, for optional references such as parent_id.
+func nullID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
+
+// nullString stores an empty string as NULL, for optional references such as assignee.
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// nullTime stores a zero time as NULL, for optional times such as due_at.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t.UTC(), Valid: !t.IsZero()}
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	hlog "hearx/pkg/logger"
+	"hearx/pkg/model"
+
+	"go.uber.org/zap"
+)
+
+// FindDeletedByID returns a task that is in the trash, or ErrNotFound if no
+// deleted task has the ID.
+func (r *mysqlTaskRepository) FindDeletedByID(ctx context.Context, id int64) (model.Task, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("querying deleted task by id", zap.Int64("id", id))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	row := r.db.Reader(ctx).QueryRowContext(ctx,
+		`SELECT `+taskColumns+`
+         FROM tasks
+         WHERE id = ? AND deleted_at IS NOT NULL`,
+		id,
+	)
+	t, err := scanTask(row)
+	if err != nil {
+		log.Error("failed to query deleted task by id", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	return t, nil
+}
+
+// Undelete takes a task out of the trash as it was when it was deleted, and
+// records that as a revision. A task that is not deleted returns ErrNotFound.
+func (r *mysqlTaskRepository) Undelete(ctx context.Context, id int64) (model.Task, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("undeleting task", zap.Int64("id", id))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.Primary().BeginTx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", zap.Error(err))
+		return model.Task{}, err
+	}
+	defer tx.Rollback()
+
+	before, err := lockTask(ctx, tx, id)
+	if errors.Is(err, ErrNotFound) || err == nil && before.DeletedAt.IsZero() {
+		return model.Task{}, fmt.Errorf("%w: no deleted task %d", ErrNotFound, id)
+	}
+	if err != nil {
+		log.Error("failed to lock task", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE tasks
+         SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+         WHERE id = ?`,
+		id,
+	); err != nil {
+		log.Error("failed to undelete task", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	undeleted, err := reviseTask(ctx, tx, id, false)
+	if err != nil {
+		log.Error("failed to record undeleted task", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	if err := addAuditEvents(ctx, tx, changeEvent(ctx, model.ActionUndelete, id, &before, &undeleted)); err != nil {
+		log.Error("failed to record audit event", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit", zap.Error(err))
+		return model.Task{}, err
+	}
+	return undeleted, nil
+}
+
+// Purge hard-deletes up to limit tasks deleted before deletedBefore, with
+// their revisions, comments and dependencies, and returns how many went.
+// Each is recorded as a purge audit event, with the task as it was, in the
+// same transaction. A deleted task whose deleted subtasks are still there
+// is skipped, so the parent_id foreign key holds; it goes in a later call,
+// once they have.
+func (r *mysqlTaskRepository) Purge(ctx context.Context, deletedBefore time.Time, limit int) (int64, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("purging deleted tasks", zap.Time("deleted_before", deletedBefore), zap.Int("limit", limit))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	tx, err := r.db.Primary().BeginTx(ctx, nil)
+	if err != nil {
+		log.Error("failed to begin transaction", zap.Error(err))
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	// MySQL cannot DELETE from a table its subquery reads, so pick the rows first
+	purged, err := lockTasks(ctx, tx,
+		`deleted_at < ? AND NOT EXISTS (SELECT 1 FROM tasks c WHERE c.parent_id = tasks.id)`,
+		deletedBefore, limit,
+	)
+	if err != nil {
+		log.Error("failed to query purgeable tasks", zap.Error(err))
+		return 0, err
+	}
+	if len(purged) == 0 {
+		return 0, nil
+	}
+	ids := make([]any, len(purged))
+	events := make([]model.AuditEvent, len(purged))
+	for i := range purged {
+		ids[i] = purged[i].ID
+		events[i] = changeEvent(ctx, model.ActionPurge, purged[i].ID, &purged[i], nil)
+	}
+	res, err := tx.ExecContext(ctx,
+		`DELETE FROM tasks WHERE id IN (`+placeholders(len(ids))+`)`,
+		ids...,
+	)
+	if err != nil {
+		log.Error("failed to purge deleted tasks", zap.Error(err))
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if err := addAuditEvents(ctx, tx, events...); err != nil {
+		log.Error("failed to record audit events", zap.Error(err))
+		return 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Error("failed to commit", zap.Error(err))
+		return 0, err
+	}
+	return n, nil
+}
+
+// lockTasks reads and locks up to limit tasks matching cond, ordered by ID,
+// inside tx. args fill the placeholders in cond.
+func lockTasks(ctx context.Context, tx *sql.Tx, cond string, args ...any) ([]model.Task, error) {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT `+taskColumns+`
+         FROM tasks
+         WHERE `+cond+`
+         ORDER BY id
+         LIMIT ?
+         FOR UPDATE`,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []model.Task
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, t)
+	}
+	return list, rows.Err()
+}
+
+// placeholders returns n comma-separated bind placeholders for an IN list.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
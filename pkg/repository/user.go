@@ -0,0 +1,109 @@
+//go:generate mockgen -source=user.go -destination=mock_repository/mock_user_repository.go -package=mock_repository
+
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	hlog "hearx/pkg/logger"
+	"hearx/pkg/model"
+	"hearx/pkg/storage"
+
+	"go.uber.org/zap"
+)
+
+// UserRepository defines DB operations for the user registry.
+type UserRepository interface {
+	Register(ctx context.Context, names []string) error
+	Exists(ctx context.Context, name string) (bool, error)
+	FindAll(ctx context.Context) ([]model.User, error)
+}
+
+// mysqlUserRepository is the MySQL implementation of UserRepository.
+type mysqlUserRepository struct {
+	db           *storage.Cluster
+	queryTimeout time.Duration
+	logger       *zap.Logger
+}
+
+// NewUserRepository constructs a MySQL-backed UserRepository.
+func NewUserRepository(db *storage.Cluster, cfg storage.Config, logger *zap.Logger) UserRepository {
+	return &mysqlUserRepository{db: db, queryTimeout: cfg.QueryTimeout, logger: logger}
+}
+
+func (r *mysqlUserRepository) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return boundQuery(ctx, r.queryTimeout)
+}
+
+// Register adds the users that are not registered yet; existing ones keep
+// their creation time.
+func (r *mysqlUserRepository) Register(ctx context.Context, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("registering users", zap.Strings("names", names))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	args := make([]any, len(names))
+	for i, n := range names {
+		args[i] = n
+	}
+	_, err := r.db.Primary().ExecContext(ctx,
+		`INSERT IGNORE INTO users (name)
+         VALUES `+strings.TrimSuffix(strings.Repeat("(?), ", len(names)), ", "),
+		args...,
+	)
+	if err != nil {
+		log.Error("failed to register users", zap.Error(err))
+	}
+	return err
+}
+
+// Exists reports whether a user is registered. It reads from the primary, as
+// it decides a write.
+func (r *mysqlUserRepository) Exists(ctx context.Context, name string) (bool, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("looking up user", zap.String("name", name))
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var n int64
+	err := r.db.Primary().QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM users WHERE name = ?`, name,
+	).Scan(&n)
+	if err != nil {
+		log.Error("failed to look up user", zap.Error(err), zap.String("name", name))
+		return false, err
+	}
+	return n > 0, nil
+}
+
+// FindAll lists the registered users by name.
+func (r *mysqlUserRepository) FindAll(ctx context.Context) ([]model.User, error) {
+	log := hlog.FromContext(ctx, r.logger)
+	log.Debug("querying users")
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, `SELECT name, created_at FROM users ORDER BY name`)
+	if err != nil {
+		log.Error("failed to query users", zap.Error(err))
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []model.User
+	for rows.Next() {
+		var u model.User
+		if err := rows.Scan(&u.Name, &u.CreatedAt); err != nil {
+			log.Error("failed to scan user", zap.Error(err))
+			return nil, err
+		}
+		list = append(list, u)
+	}
+	return list, rows.Err()
+}
@@ -0,0 +1,76 @@
+// pkg/requestid/requestid.go
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// Header is the metadata key a request ID travels under.
+const Header = "x-request-id"
+
+// MaxLen is the longest request ID accepted from a client, the width of
+// the audit_events.request_id column.
+const MaxLen = 64
+
+type ctxKey struct{}
+
+// New returns a random 128-bit request ID.
+func New() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// FromIncoming returns the request ID the client sent, if any. An ID longer
+// than MaxLen or with anything but printable ASCII is ignored, since it
+// ends up in logs and the audit trail.
+func FromIncoming(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if vals := md.Get(Header); len(vals) > 0 && valid(vals[0]) {
+		return vals[0]
+	}
+	return ""
+}
+
+func valid(id string) bool {
+	if len(id) > MaxLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// NewContext attaches id to ctx.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the request ID attached to ctx, or "".
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// Ensure returns ctx carrying a request ID: the one already attached, the
+// one the client sent, or a freshly generated one.
+func Ensure(ctx context.Context) (context.Context, string) {
+	if id := FromContext(ctx); id != "" {
+		return ctx, id
+	}
+	id := FromIncoming(ctx)
+	if id == "" {
+		id = New()
+	}
+	return NewContext(ctx, id), id
+}
@@ -0,0 +1,28 @@
+// pkg/secret/secret.go
+package secret
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// FromEnv resolves a secret by name, following the Docker secrets convention:
+// if NAME_FILE is set, the secret is read from that file (e.g. /run/secrets/...),
+// otherwise the plain NAME environment variable is used.
+func FromEnv(name string) (string, error) {
+	if path := os.Getenv(name + "_FILE"); path != "" {
+		return FromFile(path)
+	}
+	return os.Getenv(name), nil
+}
+
+// FromFile reads a secret from disk, trimming the trailing newline that
+// editors and `echo` usually leave behind.
+func FromFile(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read secret file %s: %w", path, err)
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
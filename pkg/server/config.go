@@ -0,0 +1,255 @@
+// pkg/server/config.go
+package server
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"hearx/pkg/logger"
+	"hearx/pkg/middleware"
+	"hearx/pkg/ratelimit"
+	"hearx/pkg/secret"
+	"hearx/pkg/service"
+	"hearx/pkg/storage"
+)
+
+// provideLoggerConfig reads LOG_LEVEL, LOG_FORMAT, LOG_SAMPLING and
+// LOG_OUTPUT, falling back to logger.DefaultConfig.
+func provideLoggerConfig() (logger.Config, error) {
+	cfg := logger.DefaultConfig()
+	if v := os.Getenv("LOG_LEVEL"); v != "" {
+		cfg.Level = v
+	}
+	if v := os.Getenv("LOG_FORMAT"); v != "" {
+		cfg.Format = v
+	}
+	if v := os.Getenv("LOG_OUTPUT"); v != "" {
+		cfg.OutputPaths = splitList(v)
+	}
+	if err := envBool("LOG_SAMPLING", &cfg.Sampling); err != nil {
+		return logger.Config{}, err
+	}
+	return cfg, nil
+}
+
+// provideServiceConfig reads MAX_OPEN_TASKS_PER_OWNER (0 = unlimited) and
+// AUTO_COMPLETE_PARENTS.
+func provideServiceConfig() (service.Config, error) {
+	var cfg service.Config
+	if v := os.Getenv("MAX_OPEN_TASKS_PER_OWNER"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return service.Config{}, fmt.Errorf("MAX_OPEN_TASKS_PER_OWNER: %w", err)
+		}
+		cfg.MaxOpenTasksPerOwner = n
+	}
+	if err := envBool("AUTO_COMPLETE_PARENTS", &cfg.AutoCompleteParents); err != nil {
+		return service.Config{}, err
+	}
+	return cfg, nil
+}
+
+// providePurgeConfig reads TRASH_RETENTION, TRASH_PURGE_INTERVAL and
+// TRASH_PURGE_BATCH_SIZE over service.DefaultPurgeConfig. A retention of 0
+// turns the purge off.
+func providePurgeConfig() (service.PurgeConfig, error) {
+	cfg := service.DefaultPurgeConfig()
+	if err := envDuration("TRASH_RETENTION", &cfg.Retention); err != nil {
+		return service.PurgeConfig{}, err
+	}
+	if err := envDuration("TRASH_PURGE_INTERVAL", &cfg.Interval); err != nil {
+		return service.PurgeConfig{}, err
+	}
+	if err := envInt("TRASH_PURGE_BATCH_SIZE", &cfg.BatchSize); err != nil {
+		return service.PurgeConfig{}, err
+	}
+	if cfg.Interval <= 0 {
+		return service.PurgeConfig{}, fmt.Errorf("TRASH_PURGE_INTERVAL: must be positive, got %s", cfg.Interval)
+	}
+	if cfg.BatchSize <= 0 {
+		return service.PurgeConfig{}, fmt.Errorf("TRASH_PURGE_BATCH_SIZE: must be positive, got %d", cfg.BatchSize)
+	}
+	return cfg, nil
+}
+
+// provideArchiveConfig reads ARCHIVE_AFTER, ARCHIVE_INTERVAL and
+// ARCHIVE_BATCH_SIZE over service.DefaultArchiveConfig. ARCHIVE_AFTER=0
+// turns the scheduled archival off; todo admin archive still works.
+func provideArchiveConfig() (service.ArchiveConfig, error) {
+	cfg := service.DefaultArchiveConfig()
+	if err := envDuration("ARCHIVE_AFTER", &cfg.After); err != nil {
+		return service.ArchiveConfig{}, err
+	}
+	if err := envDuration("ARCHIVE_INTERVAL", &cfg.Interval); err != nil {
+		return service.ArchiveConfig{}, err
+	}
+	if err := envInt("ARCHIVE_BATCH_SIZE", &cfg.BatchSize); err != nil {
+		return service.ArchiveConfig{}, err
+	}
+	if cfg.Interval <= 0 {
+		return service.ArchiveConfig{}, fmt.Errorf("ARCHIVE_INTERVAL: must be positive, got %s", cfg.Interval)
+	}
+	if cfg.BatchSize <= 0 {
+		return service.ArchiveConfig{}, fmt.Errorf("ARCHIVE_BATCH_SIZE: must be positive, got %d", cfg.BatchSize)
+	}
+	return cfg, nil
+}
+
+// provideRateLimitConfig reads RATE_LIMIT_RPS and RATE_LIMIT_BURST for the
+// default per-caller limit and RATE_LIMIT_METHODS for per-method overrides.
+// By default each caller gets 10 req/s per method with bursts of 20.
+func provideRateLimitConfig() (ratelimit.Config, error) {
+	cfg := ratelimit.Config{Default: ratelimit.Limit{Rate: 10, Burst: 20}}
+	if v := os.Getenv("RATE_LIMIT_RPS"); v != "" {
+		rate, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return ratelimit.Config{}, fmt.Errorf("RATE_LIMIT_RPS: %w", err)
+		}
+		cfg.Default.Rate = rate
+	}
+	if err := envInt("RATE_LIMIT_BURST", &cfg.Default.Burst); err != nil {
+		return ratelimit.Config{}, err
+	}
+	if cfg.Default.Rate > 0 && cfg.Default.Burst < 1 {
+		return ratelimit.Config{}, fmt.Errorf("RATE_LIMIT_BURST: must be at least 1 while RATE_LIMIT_RPS is set, got %d", cfg.Default.Burst)
+	}
+	methods, err := ratelimit.ParseMethods(os.Getenv("RATE_LIMIT_METHODS"))
+	if err != nil {
+		return ratelimit.Config{}, err
+	}
+	cfg.Methods = methods
+	return cfg, nil
+}
+
+// provideDeadlineConfig reads GRPC_DEFAULT_TIMEOUT (default 10s) and
+// GRPC_MAX_TIMEOUT (default 30s), plus per-method overrides from
+// GRPC_METHOD_TIMEOUTS, and fails on a default longer than its maximum.
+func provideDeadlineConfig() (middleware.DeadlineConfig, error) {
+	cfg := middleware.DeadlineConfig{
+		Timeouts: middleware.Timeouts{Default: 10 * time.Second, Max: 30 * time.Second},
+	}
+	if err := envDuration("GRPC_DEFAULT_TIMEOUT", &cfg.Default); err != nil {
+		return middleware.DeadlineConfig{}, err
+	}
+	if err := envDuration("GRPC_MAX_TIMEOUT", &cfg.Max); err != nil {
+		return middleware.DeadlineConfig{}, err
+	}
+	if err := cfg.Timeouts.Validate(); err != nil {
+		return middleware.DeadlineConfig{}, fmt.Errorf("GRPC_DEFAULT_TIMEOUT: %w", err)
+	}
+	methods, err := middleware.ParseTimeouts(os.Getenv("GRPC_METHOD_TIMEOUTS"))
+	if err != nil {
+		return middleware.DeadlineConfig{}, err
+	}
+	cfg.Methods = methods
+	return cfg, nil
+}
+
+// provideStorageConfig reads the MySQL pool, retry and TLS settings from the
+// environment, falling back to storage.DefaultConfig for anything unset, and
+// fails on values the pool cannot run with.
+func provideStorageConfig(dsn string) (storage.Config, error) {
+	cfg := storage.DefaultConfig()
+	cfg.DSN = dsn
+
+	ints := map[string]*int{
+		"MYSQL_MAX_OPEN_CONNS": &cfg.MaxOpenConns,
+		"MYSQL_MAX_IDLE_CONNS": &cfg.MaxIdleConns,
+	}
+	for name, dst := range ints {
+		if err := envInt(name, dst); err != nil {
+			return storage.Config{}, err
+		}
+	}
+
+	durations := map[string]*time.Duration{
+		"MYSQL_CONN_MAX_LIFETIME":      &cfg.ConnMaxLifetime,
+		"MYSQL_CONN_MAX_IDLE_TIME":     &cfg.ConnMaxIdleTime,
+		"MYSQL_CONNECT_TIMEOUT":        &cfg.ConnectTimeout,
+		"MYSQL_RETRY_INITIAL":          &cfg.RetryInitial,
+		"MYSQL_RETRY_MAX":              &cfg.RetryMax,
+		"MYSQL_QUERY_TIMEOUT":          &cfg.QueryTimeout,
+		"MYSQL_REPLICA_MAX_LAG":        &cfg.ReplicaMaxLag,
+		"MYSQL_REPLICA_CHECK_INTERVAL": &cfg.ReplicaCheckInterval,
+	}
+	for name, dst := range durations {
+		if err := envDuration(name, dst); err != nil {
+			return storage.Config{}, err
+		}
+	}
+
+	// replica DSNs carry credentials, so they may also come from a file
+	replicas, err := secret.FromEnv("MYSQL_REPLICA_DSNS")
+	if err != nil {
+		return storage.Config{}, err
+	}
+	cfg.ReplicaDSNs = splitList(replicas)
+
+	cfg.TLS = storage.TLSConfig{
+		Mode:       os.Getenv("MYSQL_TLS"),
+		CAFile:     os.Getenv("MYSQL_TLS_CA"),
+		CertFile:   os.Getenv("MYSQL_TLS_CERT"),
+		KeyFile:    os.Getenv("MYSQL_TLS_KEY"),
+		ServerName: os.Getenv("MYSQL_TLS_SERVER_NAME"),
+	}
+	if err := cfg.Validate(); err != nil {
+		return storage.Config{}, err
+	}
+	return cfg, nil
+}
+
+// splitList splits a comma- or newline-separated list, dropping blanks.
+func splitList(v string) []string {
+	var out []string
+	for _, item := range strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// envInt overwrites *dst with the integer in the named variable, if set.
+func envInt(name string, dst *int) error {
+	v := os.Getenv(name)
+	if v == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	*dst = n
+	return nil
+}
+
+// envBool overwrites *dst with the boolean in the named variable, if set.
+func envBool(name string, dst *bool) error {
+	v := os.Getenv(name)
+	if v == "" {
+		return nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	*dst = b
+	return nil
+}
+
+// envDuration overwrites *dst with the duration in the named variable, if set.
+func envDuration(name string, dst *time.Duration) error {
+	v := os.Getenv(name)
+	if v == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	*dst = d
+	return nil
+}
@@ -0,0 +1,59 @@
+// pkg/server/http.go
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	"hearx/pkg/auth"
+	httpTransport "hearx/pkg/transport/http"
+)
+
+// newHTTPMux wires the admin endpoints and the calendar feed. Everything on
+// it requires the same token as the gRPC API; the admin endpoints also need
+// a caller in AUTH_ADMINS, and the feed also accepts the token as a ?token=
+// query parameter.
+func newHTTPMux(level zap.AtomicLevel, calendar *httpTransport.CalendarHandler) *http.ServeMux {
+	mux := http.NewServeMux()
+	// GET returns the current level, PUT {"level":"debug"} changes it
+	mux.Handle("/admin/log/level", auth.AdminMiddleware(level))
+	// GET /calendar/alice.ics serves alice's tasks as VTODOs
+	mux.Handle("/calendar/{file}", auth.FeedMiddleware(calendar))
+	return mux
+}
+
+func newHTTPServer(mux *http.ServeMux) *http.Server {
+	p := os.Getenv("HTTP_PORT")
+	if p == "" {
+		p = "8000"
+	}
+	return &http.Server{Addr: ":" + p, Handler: mux}
+}
+
+func startHTTP(lc fx.Lifecycle, srv *http.Server, log *zap.Logger) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			lis, err := net.Listen("tcp", srv.Addr)
+			if err != nil {
+				return err
+			}
+			log.Info("HTTP starting", zap.String("addr", lis.Addr().String()))
+			go func() {
+				if err := srv.Serve(lis); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					log.Error("HTTP server failed", zap.Error(err))
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			log.Info("HTTP stopping")
+			return srv.Shutdown(ctx)
+		},
+	})
+}
@@ -3,54 +3,118 @@ package server
 
 import (
 	"context"
-	"fmt"
 	"net"
 	"os"
+	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
 
 	"hearx/pkg/auth"
 	"hearx/pkg/logger"
+	"hearx/pkg/middleware"
+	"hearx/pkg/ratelimit"
 	"hearx/pkg/repository"
+	"hearx/pkg/secret"
 	"hearx/pkg/service"
 	"hearx/pkg/storage"
 	grpcTransport "hearx/pkg/transport/grpc"
+	httpTransport "hearx/pkg/transport/http"
 	pb "hearx/proto"
 )
 
 func Run() {
 	app := fx.New(
 		fx.Provide(
+			provideLoggerConfig,
+			logger.NewAtomicLevel,
 			logger.NewLogger,
 			provideMySQLDSN,
+			provideStorageConfig,
 			storage.NewMySQLConn,
+			storage.NewCluster,
 			repository.NewTaskRepository,
+			repository.NewProjectRepository,
+			repository.NewCommentRepository,
+			repository.NewUserRepository,
+			repository.NewAuditRepository,
+			provideServiceConfig,
 			service.NewTaskService,
+			service.NewProjectService,
+			service.NewCommentService,
+			service.NewAuditService,
+			providePurgeConfig,
+			service.NewPurger,
+			provideArchiveConfig,
+			service.NewArchiver,
+			service.NewArchiveService,
 			grpcTransport.NewTaskServer,
+			grpcTransport.NewProjectServer,
+			grpcTransport.NewCommentServer,
+			grpcTransport.NewAdminServer,
+			httpTransport.NewCalendarHandler,
+			provideDeadlineConfig,
+			provideRateLimitConfig,
+			ratelimit.NewLimiter,
+			newInterceptorChain,
 			newGRPCServer,
 			newListener,
+			newHTTPMux,
+			newHTTPServer,
 		),
-		fx.Invoke(register, start),
+		fx.Invoke(register, registerUsers, start, startHTTP, startPurger, startArchiver),
 	)
 	app.Run()
 }
 
-func provideMySQLDSN() string {
-	host := os.Getenv("MYSQL_HOST")
-	port := os.Getenv("MYSQL_PORT")
-	user := os.Getenv("MYSQL_USER")
-	pass := os.Getenv("MYSQL_PASSWORD")
-	db := os.Getenv("MYSQL_DATABASE")
-	return fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true",
-		user, pass, host, port, db,
-	)
+// provideMySQLDSN builds the DSN through mysql.Config so that credentials and
+// database names are escaped correctly. The password is read from
+// MYSQL_PASSWORD_FILE (e.g. a Docker secret) when set, else MYSQL_PASSWORD.
+func provideMySQLDSN() (string, error) {
+	pass, err := secret.FromEnv("MYSQL_PASSWORD")
+	if err != nil {
+		return "", err
+	}
+
+	cfg := mysql.NewConfig()
+	cfg.User = os.Getenv("MYSQL_USER")
+	cfg.Passwd = pass
+	cfg.Net = "tcp"
+	cfg.Addr = net.JoinHostPort(os.Getenv("MYSQL_HOST"), os.Getenv("MYSQL_PORT"))
+	cfg.DBName = os.Getenv("MYSQL_DATABASE")
+	cfg.ParseTime = true
+	return cfg.FormatDSN(), nil
 }
 
-func newGRPCServer() *grpc.Server {
+// newInterceptorChain lists the unary interceptors, outermost first.
+func newInterceptorChain(
+	log *zap.Logger,
+	deadlines middleware.DeadlineConfig,
+	limiter *ratelimit.Limiter,
+) middleware.Chain {
+	return middleware.Chain{
+		middleware.RequestID(),
+		middleware.AccessLog(log),
+		middleware.Recovery(log),
+		middleware.Deadline(deadlines),
+		auth.UnaryServerInterceptor(),
+		ratelimit.UnaryServerInterceptor(limiter),
+		logger.UnaryServerInterceptor(log),
+	}
+}
+
+func newGRPCServer(chain middleware.Chain) *grpc.Server {
 	return grpc.NewServer(
-		grpc.UnaryInterceptor(auth.UnaryServerInterceptor()),
+		chain.ServerOption(),
+		// accept the CLI's 30s keepalive pings, the default policy would
+		// answer them with GOAWAY
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             10 * time.Second,
+			PermitWithoutStream: true,
+		}),
 	)
 }
 func newListener() (net.Listener, error) {
@@ -61,8 +125,62 @@ func newListener() (net.Listener, error) {
 	return net.Listen("tcp", ":"+p)
 }
 
-func register(server *grpc.Server, ts *grpcTransport.TaskServer) {
+func register(
+	server *grpc.Server,
+	ts *grpcTransport.TaskServer,
+	ps *grpcTransport.ProjectServer,
+	cs *grpcTransport.CommentServer,
+	as *grpcTransport.AdminServer,
+) {
 	pb.RegisterTodoServiceServer(server, ts)
+	pb.RegisterProjectServiceServer(server, ps)
+	pb.RegisterCommentServiceServer(server, cs)
+	pb.RegisterAdminServiceServer(server, as)
+}
+
+// registerUsers adds every caller with a token, plus the names listed in
+// USERS, to the registry tasks can be assigned from. Names are never removed:
+// a user whose token is revoked keeps their assigned tasks.
+func registerUsers(lc fx.Lifecycle, users repository.UserRepository, log *zap.Logger) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			names := append(auth.Callers(), splitList(os.Getenv("USERS"))...)
+			if len(names) == 0 {
+				return nil
+			}
+			log.Info("registering users", zap.Int("count", len(names)))
+			return users.Register(ctx, names)
+		},
+	})
+}
+
+// startPurger runs the trash purge in the background while the server is up.
+// OnStop cancels a purge in progress, so shutdown does not wait for it.
+func startPurger(lc fx.Lifecycle, p *service.Purger) {
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			p.Start()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			p.Stop()
+			return nil
+		},
+	})
+}
+
+// startArchiver archives completed tasks on schedule while the server is up.
+func startArchiver(lc fx.Lifecycle, a *service.Archiver) {
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			a.Start()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			a.Stop()
+			return nil
+		},
+	})
 }
 
 func start(lc fx.Lifecycle, server *grpc.Server, lis net.Listener, log *zap.Logger) {
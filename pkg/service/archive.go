@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"hearx/pkg/model"
+	"hearx/pkg/repository"
+
+	"go.uber.org/zap"
+)
+
+// ArchiveConfig is the archival policy for completed tasks.
+type ArchiveConfig struct {
+	// After is how long a task must have been completed, and unchanged,
+	// before it is archived; zero turns the scheduled archival off.
+	After time.Duration
+	// Interval is how often the archiver looks for tasks to archive.
+	Interval time.Duration
+	// BatchSize caps the tasks one UPDATE archives.
+	BatchSize int
+}
+
+// DefaultArchiveConfig archives tasks completed 30 days ago, checking hourly.
+func DefaultArchiveConfig() ArchiveConfig {
+	return ArchiveConfig{After: 30 * 24 * time.Hour, Interval: time.Hour, BatchSize: 500}
+}
+
+// Archiver moves completed tasks into the archive, where ListTasks leaves
+// them out unless asked. It runs on a schedule and, through ArchiveService,
+// on demand.
+type Archiver struct {
+	repo   repository.TaskRepository
+	cfg    ArchiveConfig
+	logger *zap.Logger
+	job    periodic
+}
+
+// NewArchiver constructs an Archiver. It does nothing until Start.
+func NewArchiver(repo repository.TaskRepository, cfg ArchiveConfig, logger *zap.Logger) *Archiver {
+	return &Archiver{repo: repo, cfg: cfg, logger: logger}
+}
+
+// Start archives once and then every Interval, in the background, until
+// Stop. It does not start at all when After is zero.
+func (a *Archiver) Start() {
+	if a.cfg.After <= 0 {
+		a.logger.Info("scheduled archival disabled")
+		return
+	}
+	a.logger.Info("scheduled archival starting", zap.Duration("after", a.cfg.After), zap.Duration("interval", a.cfg.Interval))
+	a.job.start(a.cfg.Interval, func(ctx context.Context) {
+		if _, err := a.Archive(ctx, a.cfg.After); err != nil && ctx.Err() == nil {
+			a.logger.Error("scheduled archival failed", zap.Error(err))
+		}
+	})
+}
+
+// Stop cancels a run in progress and waits for the background loop to
+// return.
+func (a *Archiver) Stop() {
+	a.job.stop()
+}
+
+// Archive archives every task completed more than olderThan ago, one batch
+// at a time, and returns how many it archived.
+func (a *Archiver) Archive(ctx context.Context, olderThan time.Duration) (int64, error) {
+	before := time.Now().Add(-olderThan)
+	ctx = repository.WithAction(ctx, model.ActionArchive, fmt.Sprintf("completed over %s ago", olderThan))
+	var total int64
+	for {
+		n, err := a.repo.Archive(ctx, before, a.cfg.BatchSize)
+		total += n
+		if err != nil || n == 0 {
+			if total > 0 {
+				a.logger.Info("archived completed tasks", zap.Int64("count", total), zap.Duration("older_than", olderThan))
+			}
+			return total, err
+		}
+	}
+}
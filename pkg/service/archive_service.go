@@ -0,0 +1,46 @@
+//go:generate mockgen -source=archive_service.go -destination=mock_service/mock_archive_service.go -package=mock_service
+
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"hearx/pkg/auth"
+	hlog "hearx/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+// ArchiveService archives completed tasks on request, outside the schedule.
+type ArchiveService interface {
+	ArchiveTasks(ctx context.Context, olderThan time.Duration) (int64, error)
+}
+
+type archiveService struct {
+	archiver *Archiver
+	logger   *zap.Logger
+}
+
+func NewArchiveService(archiver *Archiver, logger *zap.Logger) ArchiveService {
+	return &archiveService{archiver: archiver, logger: logger}
+}
+
+// ArchiveTasks archives every task completed more than olderThan ago and
+// returns how many it archived. Only admins may call it.
+func (s *archiveService) ArchiveTasks(ctx context.Context, olderThan time.Duration) (int64, error) {
+	log := hlog.FromContext(ctx, s.logger)
+	log.Debug("service: archiving tasks", zap.Duration("older_than", olderThan))
+	if !auth.IsAdmin(ctx) {
+		return 0, ErrNotAdmin
+	}
+	if olderThan <= 0 {
+		return 0, fmt.Errorf("%w: older than must be positive", ErrInvalidTask)
+	}
+	n, err := s.archiver.Archive(ctx, olderThan)
+	if err != nil {
+		log.Error("service: ArchiveTasks failed", zap.Error(err), zap.Int64("archived", n))
+	}
+	return n, err
+}
@@ -0,0 +1,126 @@
+// pkg/service/archive_test.go
+package service_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+
+	"hearx/pkg/auth"
+	"hearx/pkg/model"
+	mockrepo "hearx/pkg/repository/mock_repository"
+	svc "hearx/pkg/service"
+)
+
+var _ = Describe("Archiver", func() {
+	var (
+		ctrl     *gomock.Controller
+		repoMock *mockrepo.MockTaskRepository
+		cfg      svc.ArchiveConfig
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		repoMock = mockrepo.NewMockTaskRepository(ctrl)
+		cfg = svc.ArchiveConfig{After: 24 * time.Hour, Interval: time.Hour, BatchSize: 2}
+	})
+
+	AfterEach(func() { ctrl.Finish() })
+
+	It("should archive batch by batch until nothing is left", func() {
+		var cutoff time.Time
+		gomock.InOrder(
+			repoMock.EXPECT().Archive(withAction(model.ActionArchive, "completed over 48h0m0s ago"), gomock.Any(), 2).DoAndReturn(
+				func(_ context.Context, before time.Time, _ int) (int64, error) {
+					cutoff = before
+					return 2, nil
+				}),
+			repoMock.EXPECT().Archive(gomock.Any(), gomock.Any(), 2).Return(int64(1), nil),
+			repoMock.EXPECT().Archive(gomock.Any(), gomock.Any(), 2).Return(int64(0), nil),
+		)
+
+		n, err := svc.NewArchiver(repoMock, cfg, zap.NewNop()).Archive(context.Background(), 48*time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(n).To(Equal(int64(3)))
+		Expect(cutoff).To(BeTemporally("~", time.Now().Add(-48*time.Hour), time.Minute))
+	})
+
+	It("should return what it archived before an error", func() {
+		repoMock.EXPECT().Archive(gomock.Any(), gomock.Any(), 2).Return(int64(2), nil)
+		repoMock.EXPECT().Archive(gomock.Any(), gomock.Any(), 2).Return(int64(0), errors.New("boom"))
+
+		n, err := svc.NewArchiver(repoMock, cfg, zap.NewNop()).Archive(context.Background(), time.Hour)
+		Expect(err).To(MatchError("boom"))
+		Expect(n).To(Equal(int64(2)))
+	})
+
+	It("should archive on start with the configured age and stop cleanly", func() {
+		archived := make(chan time.Time, 1)
+		repoMock.EXPECT().Archive(gomock.Any(), gomock.Any(), 2).DoAndReturn(
+			func(_ context.Context, before time.Time, _ int) (int64, error) {
+				archived <- before
+				return 0, nil
+			})
+
+		a := svc.NewArchiver(repoMock, cfg, zap.NewNop())
+		a.Start()
+		var cutoff time.Time
+		Eventually(archived).Should(Receive(&cutoff))
+		a.Stop()
+		Expect(cutoff).To(BeTemporally("~", time.Now().Add(-24*time.Hour), time.Minute))
+	})
+
+	It("should not run on a schedule with no age", func() {
+		cfg.After = 0
+		a := svc.NewArchiver(repoMock, cfg, zap.NewNop())
+		a.Start()
+		a.Stop()
+	})
+})
+
+var _ = Describe("archiveService", func() {
+	var (
+		ctrl     *gomock.Controller
+		repoMock *mockrepo.MockTaskRepository
+		service  svc.ArchiveService
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		repoMock = mockrepo.NewMockTaskRepository(ctrl)
+		archiver := svc.NewArchiver(repoMock, svc.DefaultArchiveConfig(), zap.NewNop())
+		service = svc.NewArchiveService(archiver, zap.NewNop())
+		os.Setenv("AUTH_ADMINS", "root")
+	})
+
+	AfterEach(func() {
+		os.Unsetenv("AUTH_ADMINS")
+		ctrl.Finish()
+	})
+
+	It("should archive for an admin", func() {
+		repoMock.EXPECT().Archive(gomock.Any(), gomock.Any(), 500).Return(int64(4), nil)
+		repoMock.EXPECT().Archive(gomock.Any(), gomock.Any(), 500).Return(int64(0), nil)
+
+		n, err := service.ArchiveTasks(auth.WithCaller(context.Background(), "root"), time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(n).To(Equal(int64(4)))
+	})
+
+	It("should refuse anyone else", func() {
+		_, err := service.ArchiveTasks(auth.WithCaller(context.Background(), "alice"), time.Hour)
+		Expect(err).To(MatchError(svc.ErrNotAdmin))
+	})
+
+	It("should reject a non-positive age", func() {
+		_, err := service.ArchiveTasks(auth.WithCaller(context.Background(), "root"), 0)
+		Expect(err).To(MatchError(svc.ErrInvalidTask))
+	})
+})
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	hlog "hearx/pkg/logger"
+	"hearx/pkg/model"
+	"hearx/pkg/storage"
+
+	"go.uber.org/zap"
+)
+
+// AssignTask makes a registered user responsible for a task, replacing any
+// previous assignee.
+func (s *taskService) AssignTask(ctx context.Context, id int64, assignee string) (model.Task, error) {
+	log := hlog.FromContext(ctx, s.logger)
+	log.Debug("service: assigning task", zap.Int64("id", id), zap.String("assignee", assignee))
+	if assignee == "" {
+		return model.Task{}, fmt.Errorf("%w: assignee is required", ErrInvalidTask)
+	}
+	ctx = storage.WithPrimary(ctx)
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
+		log.Error("service: FindByID failed", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	if err := s.checkAssignee(ctx, assignee); err != nil {
+		log.Warn("service: AssignTask rejected", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	assigned, err := s.repo.SetAssignee(ctx, id, assignee)
+	if err != nil {
+		log.Error("service: AssignTask failed", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	return assigned, nil
+}
+
+// UnassignTask leaves a task without an assignee. Unassigning an unassigned
+// task is not an error.
+func (s *taskService) UnassignTask(ctx context.Context, id int64) (model.Task, error) {
+	log := hlog.FromContext(ctx, s.logger)
+	log.Debug("service: unassigning task", zap.Int64("id", id))
+	ctx = storage.WithPrimary(ctx)
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
+		log.Error("service: FindByID failed", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	unassigned, err := s.repo.SetAssignee(ctx, id, "")
+	if err != nil {
+		log.Error("service: UnassignTask failed", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	return unassigned, nil
+}
+
+// ListUsers returns the registry of users tasks can be assigned to.
+func (s *taskService) ListUsers(ctx context.Context) ([]model.User, error) {
+	log := hlog.FromContext(ctx, s.logger)
+	log.Debug("service: listing users")
+	list, err := s.users.FindAll(ctx)
+	if err != nil {
+		log.Error("service: ListUsers failed", zap.Error(err))
+	}
+	return list, err
+}
+
+// checkAssignee reports an unregistered assignee as an invalid task. An
+// empty assignee always passes.
+func (s *taskService) checkAssignee(ctx context.Context, assignee string) error {
+	if assignee == "" {
+		return nil
+	}
+	ok, err := s.users.Exists(ctx, assignee)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: user %q is not registered", ErrInvalidTask, assignee)
+	}
+	return nil
+}
+
+// checkAssignees runs checkAssignee once for every user a batch assigns.
+func (s *taskService) checkAssignees(ctx context.Context, tasks []model.Task) error {
+	seen := map[string]bool{}
+	for _, t := range tasks {
+		if t.Assignee == "" || seen[t.Assignee] {
+			continue
+		}
+		seen[t.Assignee] = true
+		if err := s.checkAssignee(ctx, t.Assignee); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+// pkg/service/assignment_test.go
+package service_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+
+	"hearx/pkg/model"
+	"hearx/pkg/repository"
+	mockrepo "hearx/pkg/repository/mock_repository"
+	svc "hearx/pkg/service"
+)
+
+var _ = Describe("taskService assignment", func() {
+	var (
+		ctrl     *gomock.Controller
+		repoMock *mockrepo.MockTaskRepository
+		userMock *mockrepo.MockUserRepository
+		service  svc.TaskService
+		ctx      context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		repoMock = mockrepo.NewMockTaskRepository(ctrl)
+		userMock = mockrepo.NewMockUserRepository(ctrl)
+		service = svc.NewTaskService(repoMock, mockrepo.NewMockProjectRepository(ctrl), userMock, svc.Config{}, zap.NewNop())
+		ctx = context.Background()
+	})
+
+	AfterEach(func() { ctrl.Finish() })
+
+	Describe("AssignTask", func() {
+		It("should assign a task to a registered user", func() {
+			repoMock.EXPECT().FindByID(gomock.Any(), int64(5)).Return(model.Task{ID: 5}, nil)
+			userMock.EXPECT().Exists(gomock.Any(), "alice").Return(true, nil)
+			repoMock.EXPECT().SetAssignee(gomock.Any(), int64(5), "alice").
+				Return(model.Task{ID: 5, Assignee: "alice"}, nil)
+
+			t, err := service.AssignTask(ctx, 5, "alice")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t.Assignee).To(Equal("alice"))
+		})
+
+		It("should reject an unregistered user", func() {
+			repoMock.EXPECT().FindByID(gomock.Any(), int64(5)).Return(model.Task{ID: 5}, nil)
+			userMock.EXPECT().Exists(gomock.Any(), "mallory").Return(false, nil)
+
+			_, err := service.AssignTask(ctx, 5, "mallory")
+			Expect(err).To(MatchError(svc.ErrInvalidTask))
+		})
+
+		It("should return ErrNotFound for a missing task", func() {
+			repoMock.EXPECT().FindByID(gomock.Any(), int64(5)).Return(model.Task{}, repository.ErrNotFound)
+
+			_, err := service.AssignTask(ctx, 5, "alice")
+			Expect(err).To(MatchError(repository.ErrNotFound))
+		})
+	})
+
+	Describe("UnassignTask", func() {
+		It("should clear the assignee", func() {
+			repoMock.EXPECT().FindByID(gomock.Any(), int64(5)).Return(model.Task{ID: 5, Assignee: "alice"}, nil)
+			repoMock.EXPECT().SetAssignee(gomock.Any(), int64(5), "").Return(model.Task{ID: 5}, nil)
+
+			t, err := service.UnassignTask(ctx, 5)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t.Assignee).To(BeEmpty())
+		})
+	})
+
+	Describe("AddTasks", func() {
+		It("should check each assignee once", func() {
+			userMock.EXPECT().Exists(gomock.Any(), "alice").Return(true, nil).Times(1)
+			repoMock.EXPECT().CreateBatch(gomock.Any(), gomock.Len(2), int64(0)).Return([]model.Task{{ID: 1}, {ID: 2}}, nil)
+
+			_, err := service.AddTasks(ctx, []model.Task{{Title: "A", Assignee: "alice"}, {Title: "B", Assignee: "alice"}})
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
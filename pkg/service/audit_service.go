@@ -0,0 +1,47 @@
+//go:generate mockgen -source=audit_service.go -destination=mock_service/mock_audit_service.go -package=mock_service
+
+package service
+
+import (
+	"context"
+	"errors"
+
+	"hearx/pkg/auth"
+	hlog "hearx/pkg/logger"
+	"hearx/pkg/model"
+	"hearx/pkg/repository"
+
+	"go.uber.org/zap"
+)
+
+// AuditService reads the audit trail the task repository writes.
+type AuditService interface {
+	ListAuditEvents(ctx context.Context, filter model.AuditFilter) ([]model.AuditEvent, error)
+}
+
+// ErrNotAdmin is returned when a caller without the admin role calls an
+// admin-only method.
+var ErrNotAdmin = errors.New("admin role required")
+
+type auditService struct {
+	repo   repository.AuditRepository
+	logger *zap.Logger
+}
+
+func NewAuditService(repo repository.AuditRepository, logger *zap.Logger) AuditService {
+	return &auditService{repo: repo, logger: logger}
+}
+
+// ListAuditEvents lists recorded task changes. Only admins may read them.
+func (s *auditService) ListAuditEvents(ctx context.Context, filter model.AuditFilter) ([]model.AuditEvent, error) {
+	log := hlog.FromContext(ctx, s.logger)
+	log.Debug("service: listing audit events", zap.Int64("after_id", filter.AfterID), zap.Int("limit", filter.Limit))
+	if !auth.IsAdmin(ctx) {
+		return nil, ErrNotAdmin
+	}
+	list, err := s.repo.FindAll(ctx, filter)
+	if err != nil {
+		log.Error("service: ListAuditEvents failed", zap.Error(err))
+	}
+	return list, err
+}
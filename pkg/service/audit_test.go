@@ -0,0 +1,127 @@
+// pkg/service/audit_test.go
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+
+	"hearx/pkg/auth"
+	"hearx/pkg/model"
+	"hearx/pkg/repository"
+	mockrepo "hearx/pkg/repository/mock_repository"
+	svc "hearx/pkg/service"
+)
+
+// actionMatcher matches a context whose task writes are recorded as the
+// given audit action and detail.
+type actionMatcher struct{ name, detail string }
+
+func withAction(name, detail string) gomock.Matcher {
+	return actionMatcher{name: name, detail: detail}
+}
+
+func (m actionMatcher) Matches(x any) bool {
+	ctx, ok := x.(context.Context)
+	if !ok {
+		return false
+	}
+	name, detail, ok := repository.ActionFromContext(ctx)
+	return ok && name == m.name && detail == m.detail
+}
+
+func (m actionMatcher) String() string {
+	return fmt.Sprintf("context with audit action %q (%q)", m.name, m.detail)
+}
+
+var _ = Describe("audited writes", func() {
+	var (
+		ctrl     *gomock.Controller
+		repoMock *mockrepo.MockTaskRepository
+		service  svc.TaskService
+		ctx      context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		repoMock = mockrepo.NewMockTaskRepository(ctrl)
+		service = svc.NewTaskService(repoMock, mockrepo.NewMockProjectRepository(ctrl), mockrepo.NewMockUserRepository(ctrl), svc.Config{}, zap.NewNop())
+		ctx = context.Background()
+	})
+
+	AfterEach(func() { ctrl.Finish() })
+
+	It("should record completing a task as complete rather than update", func() {
+		repoMock.EXPECT().FindByID(gomock.Any(), int64(3)).Return(model.Task{ID: 3}, nil)
+		repoMock.EXPECT().CountOpenBlockers(gomock.Any(), int64(3)).Return(int64(0), nil)
+		repoMock.EXPECT().Update(withAction(model.ActionComplete, ""), model.Task{ID: 3, Completed: true}).
+			Return(model.Task{ID: 3, Completed: true}, nil)
+
+		_, _, err := service.CompleteTask(ctx, 3)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should record a new recurrence rule as set_recurrence", func() {
+		due := time.Date(2026, 10, 26, 9, 0, 0, 0, time.UTC)
+		repoMock.EXPECT().FindByID(gomock.Any(), int64(3)).Return(model.Task{ID: 3}, nil)
+		repoMock.EXPECT().Update(withAction(model.ActionSetRecurrence, ""), gomock.Any()).Return(model.Task{ID: 3}, nil)
+
+		_, err := service.SetRecurrence(ctx, 3, "FREQ=DAILY", due)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should leave a plain update to the repository's own action", func() {
+		repoMock.EXPECT().SetText(gomock.Any(), int64(3), "New", "").DoAndReturn(
+			func(ctx context.Context, id int64, title, description string) (model.Task, error) {
+				_, _, ok := repository.ActionFromContext(ctx)
+				Expect(ok).To(BeFalse())
+				return model.Task{ID: id, Title: title}, nil
+			})
+
+		_, err := service.UpdateTask(ctx, model.Task{ID: 3, Title: "New"})
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
+
+var _ = Describe("auditService", func() {
+	var (
+		ctrl      *gomock.Controller
+		auditMock *mockrepo.MockAuditRepository
+		service   svc.AuditService
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		auditMock = mockrepo.NewMockAuditRepository(ctrl)
+		service = svc.NewAuditService(auditMock, zap.NewNop())
+		os.Setenv("AUTH_ADMINS", "root, carol")
+	})
+
+	AfterEach(func() {
+		os.Unsetenv("AUTH_ADMINS")
+		ctrl.Finish()
+	})
+
+	It("should list events for an admin", func() {
+		filter := model.AuditFilter{TaskID: 3, Limit: 10}
+		auditMock.EXPECT().FindAll(gomock.Any(), filter).Return([]model.AuditEvent{{ID: 1}}, nil)
+
+		list, err := service.ListAuditEvents(auth.WithCaller(context.Background(), "carol"), filter)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list).To(HaveLen(1))
+	})
+
+	It("should refuse anyone else", func() {
+		_, err := service.ListAuditEvents(auth.WithCaller(context.Background(), "alice"), model.AuditFilter{})
+		Expect(err).To(MatchError(svc.ErrNotAdmin))
+		_, err = service.ListAuditEvents(context.Background(), model.AuditFilter{})
+		Expect(err).To(MatchError(svc.ErrNotAdmin))
+	})
+})
@@ -0,0 +1,107 @@
+//go:generate mockgen -source=comment_service.go -destination=mock_service/mock_comment_service.go -package=mock_service
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"hearx/pkg/auth"
+	hlog "hearx/pkg/logger"
+	"hearx/pkg/model"
+	"hearx/pkg/repository"
+	"hearx/pkg/storage"
+
+	"go.uber.org/zap"
+)
+
+type CommentService interface {
+	AddComment(ctx context.Context, taskID int64, body string) (model.Comment, error)
+	ListComments(ctx context.Context, taskID, afterID int64, limit int) ([]model.Comment, error)
+	DeleteComment(ctx context.Context, id int64) error
+}
+
+// ErrInvalidComment is returned when a comment fails validation.
+var ErrInvalidComment = errors.New("invalid comment")
+
+// ErrNotAuthor is returned by DeleteComment when the caller did not write
+// the comment.
+var ErrNotAuthor = errors.New("only the author may do this")
+
+// maxCommentBody bounds a comment, in characters.
+const maxCommentBody = 10000
+
+type commentService struct {
+	repo   repository.CommentRepository
+	tasks  repository.TaskRepository
+	logger *zap.Logger
+}
+
+func NewCommentService(repo repository.CommentRepository, tasks repository.TaskRepository, logger *zap.Logger) CommentService {
+	return &commentService{repo: repo, tasks: tasks, logger: logger}
+}
+
+// AddComment adds a comment to a task, written by the caller.
+func (s *commentService) AddComment(ctx context.Context, taskID int64, body string) (model.Comment, error) {
+	log := hlog.FromContext(ctx, s.logger)
+	log.Debug("service: adding comment", zap.Int64("task_id", taskID))
+	body = strings.TrimSpace(body)
+	switch {
+	case body == "":
+		return model.Comment{}, fmt.Errorf("%w: body is required", ErrInvalidComment)
+	case utf8.RuneCountInString(body) > maxCommentBody:
+		return model.Comment{}, fmt.Errorf("%w: body is longer than %d characters", ErrInvalidComment, maxCommentBody)
+	}
+	if _, err := s.tasks.FindByID(storage.WithPrimary(ctx), taskID); err != nil {
+		log.Error("service: FindByID failed", zap.Error(err), zap.Int64("id", taskID))
+		return model.Comment{}, err
+	}
+	author, _ := auth.CallerFromContext(ctx)
+	created, err := s.repo.Create(ctx, model.Comment{TaskID: taskID, Author: author, Body: body})
+	if err != nil {
+		log.Error("service: AddComment failed", zap.Error(err), zap.Int64("task_id", taskID))
+		return model.Comment{}, err
+	}
+	log.Debug("service: comment added", zap.Int64("id", created.ID))
+	return created, nil
+}
+
+// ListComments returns a task's comments oldest first, after afterID.
+func (s *commentService) ListComments(ctx context.Context, taskID, afterID int64, limit int) ([]model.Comment, error) {
+	log := hlog.FromContext(ctx, s.logger)
+	log.Debug("service: listing comments", zap.Int64("task_id", taskID))
+	// a missing task is NotFound, not an empty list
+	if _, err := s.tasks.FindByID(ctx, taskID); err != nil {
+		log.Error("service: FindByID failed", zap.Error(err), zap.Int64("id", taskID))
+		return nil, err
+	}
+	list, err := s.repo.FindByTask(ctx, taskID, afterID, limit)
+	if err != nil {
+		log.Error("service: ListComments failed", zap.Error(err), zap.Int64("task_id", taskID))
+	}
+	return list, err
+}
+
+// DeleteComment removes a comment. When the caller is known it must be the
+// comment's author.
+func (s *commentService) DeleteComment(ctx context.Context, id int64) error {
+	log := hlog.FromContext(ctx, s.logger)
+	log.Debug("service: deleting comment", zap.Int64("id", id))
+	c, err := s.repo.FindByID(storage.WithPrimary(ctx), id)
+	if err != nil {
+		log.Error("service: FindByID failed", zap.Error(err), zap.Int64("id", id))
+		return err
+	}
+	if caller, ok := auth.CallerFromContext(ctx); ok && caller != c.Author {
+		log.Warn("service: DeleteComment rejected", zap.String("caller", caller), zap.Int64("id", id))
+		return fmt.Errorf("%w: comment %d", ErrNotAuthor, id)
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		log.Error("service: DeleteComment failed", zap.Error(err), zap.Int64("id", id))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+// pkg/service/comment_service_test.go
+package service_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+
+	"hearx/pkg/auth"
+	"hearx/pkg/model"
+	"hearx/pkg/repository"
+	mockrepo "hearx/pkg/repository/mock_repository"
+	svc "hearx/pkg/service"
+)
+
+var _ = Describe("commentService", func() {
+	var (
+		ctrl        *gomock.Controller
+		commentMock *mockrepo.MockCommentRepository
+		taskMock    *mockrepo.MockTaskRepository
+		service     svc.CommentService
+		ctx         context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		commentMock = mockrepo.NewMockCommentRepository(ctrl)
+		taskMock = mockrepo.NewMockTaskRepository(ctrl)
+		service = svc.NewCommentService(commentMock, taskMock, zap.NewNop())
+		ctx = auth.WithCaller(context.Background(), "alice")
+	})
+
+	AfterEach(func() { ctrl.Finish() })
+
+	Describe("AddComment", func() {
+		It("should record the caller as author", func() {
+			taskMock.EXPECT().FindByID(gomock.Any(), int64(1)).Return(model.Task{ID: 1}, nil)
+			commentMock.EXPECT().Create(gomock.Any(), model.Comment{TaskID: 1, Author: "alice", Body: "Looks good"}).
+				Return(model.Comment{ID: 5, TaskID: 1, Author: "alice", Body: "Looks good"}, nil)
+
+			c, err := service.AddComment(ctx, 1, "  Looks good\n")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.ID).To(Equal(int64(5)))
+		})
+
+		It("should reject an empty body without a lookup", func() {
+			_, err := service.AddComment(ctx, 1, " \n")
+			Expect(err).To(MatchError(svc.ErrInvalidComment))
+		})
+
+		It("should not comment on a missing task", func() {
+			taskMock.EXPECT().FindByID(gomock.Any(), int64(9)).Return(model.Task{}, repository.ErrNotFound)
+
+			_, err := service.AddComment(ctx, 9, "hello")
+			Expect(err).To(MatchError(repository.ErrNotFound))
+		})
+	})
+
+	Describe("ListComments", func() {
+		It("should page through a task's comments", func() {
+			taskMock.EXPECT().FindByID(gomock.Any(), int64(1)).Return(model.Task{ID: 1}, nil)
+			commentMock.EXPECT().FindByTask(gomock.Any(), int64(1), int64(5), 11).
+				Return([]model.Comment{{ID: 6}, {ID: 7}}, nil)
+
+			list, err := service.ListComments(ctx, 1, 5, 11)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(list).To(HaveLen(2))
+		})
+	})
+
+	Describe("DeleteComment", func() {
+		It("should let the author delete a comment", func() {
+			commentMock.EXPECT().FindByID(gomock.Any(), int64(5)).Return(model.Comment{ID: 5, Author: "alice"}, nil)
+			commentMock.EXPECT().Delete(gomock.Any(), int64(5)).Return(nil)
+
+			Expect(service.DeleteComment(ctx, 5)).To(Succeed())
+		})
+
+		It("should refuse anyone else", func() {
+			commentMock.EXPECT().FindByID(gomock.Any(), int64(5)).Return(model.Comment{ID: 5, Author: "bob"}, nil)
+
+			Expect(service.DeleteComment(ctx, 5)).To(MatchError(svc.ErrNotAuthor))
+		})
+	})
+})
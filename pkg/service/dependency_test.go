@@ -0,0 +1,125 @@
+// pkg/service/dependency_test.go
+package service_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+
+	"hearx/pkg/model"
+	"hearx/pkg/repository"
+	mockrepo "hearx/pkg/repository/mock_repository"
+	svc "hearx/pkg/service"
+)
+
+var _ = Describe("taskService dependencies", func() {
+	var (
+		ctrl     *gomock.Controller
+		repoMock *mockrepo.MockTaskRepository
+		service  svc.TaskService
+		ctx      context.Context
+	)
+
+	// 3 waits for 2, which waits for 1; 4 waits for nothing
+	blockers := map[int64][]model.Task{
+		2: {{ID: 1}},
+		3: {{ID: 2}},
+	}
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		repoMock = mockrepo.NewMockTaskRepository(ctrl)
+		service = svc.NewTaskService(repoMock, mockrepo.NewMockProjectRepository(ctrl), mockrepo.NewMockUserRepository(ctrl), svc.Config{}, zap.NewNop())
+		ctx = context.Background()
+
+		repoMock.EXPECT().FindByID(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, id int64) (model.Task, error) {
+				if id < 1 || id > 4 {
+					return model.Task{}, repository.ErrNotFound
+				}
+				return model.Task{ID: id}, nil
+			}).AnyTimes()
+		repoMock.EXPECT().FindBlockers(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ context.Context, id int64) ([]model.Task, error) {
+				return blockers[id], nil
+			}).AnyTimes()
+	})
+
+	AfterEach(func() { ctrl.Finish() })
+
+	Describe("AddDependency", func() {
+		It("should record a dependency that closes no loop", func() {
+			repoMock.EXPECT().AddDependency(gomock.Any(), int64(4), int64(3)).Return(nil)
+
+			Expect(service.AddDependency(ctx, 4, 3)).To(Succeed())
+		})
+
+		It("should refuse a task depending on itself", func() {
+			Expect(service.AddDependency(ctx, 2, 2)).To(MatchError(svc.ErrCycle))
+		})
+
+		It("should refuse a dependency back along an existing chain", func() {
+			Expect(service.AddDependency(ctx, 1, 3)).To(MatchError(svc.ErrCycle))
+		})
+
+		It("should return NotFound for a missing blocker", func() {
+			Expect(service.AddDependency(ctx, 1, 99)).To(MatchError(repository.ErrNotFound))
+		})
+	})
+
+	Describe("ListBlockers", func() {
+		It("should return the tasks a task waits for", func() {
+			Expect(service.ListBlockers(ctx, 3)).To(Equal([]model.Task{{ID: 2}}))
+		})
+	})
+
+	Describe("PlanTasks", func() {
+		ids := func(list []model.Task) []int64 {
+			var out []int64
+			for _, t := range list {
+				out = append(out, t.ID)
+			}
+			return out
+		}
+
+		It("should list each open task after the tasks it waits for", func() {
+			// 2 waits for 5 and 1; 3 waits for 2; 6 is done
+			repoMock.EXPECT().FindAll(gomock.Any(), model.TaskFilter{ProjectID: 7}).Return([]model.Task{
+				{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}, {ID: 6, Completed: true},
+			}, nil)
+			repoMock.EXPECT().FindOpenDependencies(gomock.Any()).Return([]model.Dependency{
+				{TaskID: 2, BlockerID: 1}, {TaskID: 2, BlockerID: 5}, {TaskID: 3, BlockerID: 2},
+			}, nil)
+
+			plan, err := service.PlanTasks(ctx, model.TaskFilter{ProjectID: 7, AfterID: 3, Limit: 2, Ready: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids(plan)).To(Equal([]int64{1, 4, 5, 2, 3}))
+		})
+
+		It("should ignore blockers outside the filter", func() {
+			repoMock.EXPECT().FindAll(gomock.Any(), gomock.Any()).Return([]model.Task{{ID: 2}, {ID: 3}}, nil)
+			repoMock.EXPECT().FindOpenDependencies(gomock.Any()).Return([]model.Dependency{
+				{TaskID: 2, BlockerID: 9}, {TaskID: 2, BlockerID: 3},
+			}, nil)
+
+			plan, err := service.PlanTasks(ctx, model.TaskFilter{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids(plan)).To(Equal([]int64{3, 2}))
+		})
+
+		It("should still list tasks caught in a loop, last", func() {
+			repoMock.EXPECT().FindAll(gomock.Any(), gomock.Any()).Return([]model.Task{{ID: 1}, {ID: 2}, {ID: 3}}, nil)
+			repoMock.EXPECT().FindOpenDependencies(gomock.Any()).Return([]model.Dependency{
+				{TaskID: 1, BlockerID: 2}, {TaskID: 2, BlockerID: 1},
+			}, nil)
+
+			plan, err := service.PlanTasks(ctx, model.TaskFilter{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ids(plan)).To(Equal([]int64{3, 1, 2}))
+		})
+	})
+})
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	hlog "hearx/pkg/logger"
+	"hearx/pkg/model"
+	"hearx/pkg/repository"
+	"hearx/pkg/storage"
+
+	"go.uber.org/zap"
+)
+
+// maxDepth bounds the walk up a hierarchy, so a cycle that got into the
+// table by other means cannot loop forever.
+const maxDepth = 100
+
+func (s *taskService) ListChildren(ctx context.Context, parentID int64) ([]model.Task, error) {
+	log := hlog.FromContext(ctx, s.logger)
+	log.Debug("service: listing children", zap.Int64("parent_id", parentID))
+	if _, err := s.repo.FindByID(ctx, parentID); err != nil {
+		log.Error("service: FindByID failed", zap.Error(err), zap.Int64("id", parentID))
+		return nil, err
+	}
+	list, err := s.repo.FindChildren(ctx, parentID)
+	if err != nil {
+		log.Error("service: ListChildren failed", zap.Error(err))
+	}
+	return list, err
+}
+
+// MoveTask moves a task under parentID, or to the top level when parentID is
+// zero. Moving a task below itself is rejected with ErrCycle.
+func (s *taskService) MoveTask(ctx context.Context, id, parentID int64) (model.Task, error) {
+	log := hlog.FromContext(ctx, s.logger)
+	log.Debug("service: moving task", zap.Int64("id", id), zap.Int64("parent_id", parentID))
+	ctx = storage.WithPrimary(ctx)
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
+		log.Error("service: FindByID failed", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	if parentID != 0 {
+		if err := s.checkAncestry(ctx, id, parentID); err != nil {
+			log.Warn("service: MoveTask rejected", zap.Error(err), zap.Int64("id", id))
+			return model.Task{}, err
+		}
+	}
+	moved, err := s.repo.SetParent(ctx, id, parentID)
+	if err != nil {
+		log.Error("service: MoveTask failed", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	log.Debug("service: task moved", zap.Int64("id", id))
+	return moved, nil
+}
+
+// checkParent reports a missing parent as an invalid task rather than as the
+// task itself not being found.
+func (s *taskService) checkParent(ctx context.Context, parentID int64) error {
+	_, err := s.repo.FindByID(ctx, parentID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Errorf("%w: parent %d does not exist", ErrInvalidTask, parentID)
+	}
+	return err
+}
+
+// checkAncestry walks up from parentID and fails if it meets id, i.e. if
+// parentID is id itself or one of its descendants.
+func (s *taskService) checkAncestry(ctx context.Context, id, parentID int64) error {
+	if err := s.checkParent(ctx, parentID); err != nil {
+		return err
+	}
+	cur := parentID
+	for depth := 0; cur != 0; depth++ {
+		if cur == id {
+			return fmt.Errorf("%w: %d is %d or below it", ErrCycle, parentID, id)
+		}
+		if depth == maxDepth {
+			return fmt.Errorf("%w: hierarchy deeper than %d", ErrCycle, maxDepth)
+		}
+		t, err := s.repo.FindByID(ctx, cur)
+		if err != nil {
+			return err
+		}
+		cur = t.ParentID
+	}
+	return nil
+}
+
+// completeParents completes parentID if it has no open children or blockers
+// left, then repeats for its own parent.
+func (s *taskService) completeParents(ctx context.Context, parentID int64) error {
+	log := hlog.FromContext(ctx, s.logger)
+	for depth := 0; parentID != 0 && depth < maxDepth; depth++ {
+		open, err := s.repo.CountOpenChildren(ctx, parentID)
+		if err != nil || open > 0 {
+			return err
+		}
+		// a parent still waiting on other tasks stays open, and so do its own parents
+		if blockers, err := s.repo.CountOpenBlockers(ctx, parentID); err != nil || blockers > 0 {
+			return err
+		}
+		parent, err := s.repo.FindByID(ctx, parentID)
+		if err != nil {
+			return err
+		}
+		if !parent.Completed {
+			parent.Completed = true
+			auto := repository.WithAction(ctx, model.ActionComplete, "no open subtasks left")
+			if parent, err = s.repo.Update(auto, parent); err != nil {
+				return err
+			}
+			log.Debug("service: parent auto-completed", zap.Int64("id", parent.ID))
+		}
+		parentID = parent.ParentID
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+// pkg/service/hierarchy_test.go
+package service_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+
+	"hearx/pkg/model"
+	"hearx/pkg/repository"
+	mockrepo "hearx/pkg/repository/mock_repository"
+	svc "hearx/pkg/service"
+)
+
+var _ = Describe("taskService hierarchy", func() {
+	var (
+		ctrl     *gomock.Controller
+		repoMock *mockrepo.MockTaskRepository
+		service  svc.TaskService
+		ctx      context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		repoMock = mockrepo.NewMockTaskRepository(ctrl)
+		service = svc.NewTaskService(repoMock, mockrepo.NewMockProjectRepository(ctrl), mockrepo.NewMockUserRepository(ctrl), svc.Config{}, zap.NewNop())
+		ctx = context.Background()
+	})
+
+	AfterEach(func() { ctrl.Finish() })
+
+	Describe("AddTask", func() {
+		It("should reject a parent that does not exist", func() {
+			repoMock.EXPECT().FindByID(gomock.Any(), int64(9)).Return(model.Task{}, repository.ErrNotFound)
+
+			_, err := service.AddTask(ctx, model.Task{Title: "child", ParentID: 9})
+			Expect(err).To(MatchError(svc.ErrInvalidTask))
+		})
+
+		It("should create the task under an existing parent", func() {
+			in := model.Task{Title: "child", ParentID: 1}
+			repoMock.EXPECT().FindByID(gomock.Any(), int64(1)).Return(model.Task{ID: 1}, nil)
+			repoMock.EXPECT().Create(gomock.Any(), in, int64(0)).Return(model.Task{ID: 2, Title: "child", ParentID: 1}, nil)
+
+			created, err := service.AddTask(ctx, in)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(created.ParentID).To(Equal(int64(1)))
+		})
+	})
+
+	Describe("ListChildren", func() {
+		It("should return NotFound for a missing parent", func() {
+			repoMock.EXPECT().FindByID(gomock.Any(), int64(5)).Return(model.Task{}, repository.ErrNotFound)
+
+			_, err := service.ListChildren(ctx, 5)
+			Expect(err).To(MatchError(repository.ErrNotFound))
+		})
+
+		It("should return the children from the repository", func() {
+			kids := []model.Task{{ID: 2, ParentID: 1}, {ID: 3, ParentID: 1}}
+			repoMock.EXPECT().FindByID(gomock.Any(), int64(1)).Return(model.Task{ID: 1}, nil)
+			repoMock.EXPECT().FindChildren(gomock.Any(), int64(1)).Return(kids, nil)
+
+			Expect(service.ListChildren(ctx, 1)).To(Equal(kids))
+		})
+	})
+
+	Describe("MoveTask", func() {
+		// 1 ─ 2 ─ 3, and 4 on its own
+		tasks := map[int64]model.Task{
+			1: {ID: 1},
+			2: {ID: 2, ParentID: 1},
+			3: {ID: 3, ParentID: 2},
+			4: {ID: 4},
+		}
+
+		BeforeEach(func() {
+			repoMock.EXPECT().FindByID(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, id int64) (model.Task, error) {
+					t, ok := tasks[id]
+					if !ok {
+						return model.Task{}, repository.ErrNotFound
+					}
+					return t, nil
+				}).AnyTimes()
+		})
+
+		It("should move a task under an unrelated one", func() {
+			repoMock.EXPECT().SetParent(gomock.Any(), int64(2), int64(4)).Return(model.Task{ID: 2, ParentID: 4}, nil)
+
+			moved, err := service.MoveTask(ctx, 2, 4)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(moved.ParentID).To(Equal(int64(4)))
+		})
+
+		It("should move a task to the top level", func() {
+			repoMock.EXPECT().SetParent(gomock.Any(), int64(3), int64(0)).Return(model.Task{ID: 3}, nil)
+
+			_, err := service.MoveTask(ctx, 3, 0)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should refuse to make a task its own parent", func() {
+			_, err := service.MoveTask(ctx, 2, 2)
+			Expect(err).To(MatchError(svc.ErrCycle))
+		})
+
+		It("should refuse to move a task below its own descendant", func() {
+			_, err := service.MoveTask(ctx, 1, 3)
+			Expect(err).To(MatchError(svc.ErrCycle))
+		})
+
+		It("should report a missing parent as an invalid task", func() {
+			_, err := service.MoveTask(ctx, 2, 99)
+			Expect(err).To(MatchError(svc.ErrInvalidTask))
+		})
+
+		It("should return NotFound for a missing task", func() {
+			_, err := service.MoveTask(ctx, 99, 1)
+			Expect(err).To(MatchError(repository.ErrNotFound))
+		})
+	})
+
+	Describe("CompleteTask with AutoCompleteParents", func() {
+		BeforeEach(func() {
+			service = svc.NewTaskService(repoMock, mockrepo.NewMockProjectRepository(ctrl), mockrepo.NewMockUserRepository(ctrl), svc.Config{AutoCompleteParents: true}, zap.NewNop())
+		})
+
+		It("should complete the parent chain once no children are open", func() {
+			gomock.InOrder(
+				repoMock.EXPECT().FindByID(gomock.Any(), int64(3)).Return(model.Task{ID: 3, ParentID: 2}, nil),
+				repoMock.EXPECT().CountOpenBlockers(gomock.Any(), int64(3)).Return(int64(0), nil),
+				repoMock.EXPECT().Update(withAction(model.ActionComplete, ""), model.Task{ID: 3, ParentID: 2, Completed: true}).
+					Return(model.Task{ID: 3, ParentID: 2, Completed: true}, nil),
+				repoMock.EXPECT().CountOpenChildren(gomock.Any(), int64(2)).Return(int64(0), nil),
+				repoMock.EXPECT().CountOpenBlockers(gomock.Any(), int64(2)).Return(int64(0), nil),
+				repoMock.EXPECT().FindByID(gomock.Any(), int64(2)).Return(model.Task{ID: 2, ParentID: 1}, nil),
+				// recorded as completed, with why, in the audit trail
+				repoMock.EXPECT().Update(withAction(model.ActionComplete, "no open subtasks left"), model.Task{ID: 2, ParentID: 1, Completed: true}).
+					Return(model.Task{ID: 2, ParentID: 1, Completed: true}, nil),
+				repoMock.EXPECT().CountOpenChildren(gomock.Any(), int64(1)).Return(int64(1), nil),
+			)
+
+			done, _, err := service.CompleteTask(ctx, 3)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(done.Completed).To(BeTrue())
+		})
+
+		It("should leave parents alone when the option is off", func() {
+			service = svc.NewTaskService(repoMock, mockrepo.NewMockProjectRepository(ctrl), mockrepo.NewMockUserRepository(ctrl), svc.Config{}, zap.NewNop())
+			repoMock.EXPECT().FindByID(gomock.Any(), int64(3)).Return(model.Task{ID: 3, ParentID: 2}, nil)
+			repoMock.EXPECT().CountOpenBlockers(gomock.Any(), int64(3)).Return(int64(0), nil)
+			repoMock.EXPECT().Update(gomock.Any(), gomock.Any()).Return(model.Task{ID: 3, ParentID: 2, Completed: true}, nil)
+
+			_, _, err := service.CompleteTask(ctx, 3)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should not complete a parent that is itself blocked", func() {
+			repoMock.EXPECT().FindByID(gomock.Any(), int64(3)).Return(model.Task{ID: 3, ParentID: 2}, nil)
+			repoMock.EXPECT().CountOpenBlockers(gomock.Any(), int64(3)).Return(int64(0), nil)
+			repoMock.EXPECT().Update(gomock.Any(), gomock.Any()).Return(model.Task{ID: 3, ParentID: 2, Completed: true}, nil)
+			repoMock.EXPECT().CountOpenChildren(gomock.Any(), int64(2)).Return(int64(0), nil)
+			repoMock.EXPECT().CountOpenBlockers(gomock.Any(), int64(2)).Return(int64(1), nil)
+
+			_, _, err := service.CompleteTask(ctx, 3)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
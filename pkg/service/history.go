@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	hlog "hearx/pkg/logger"
+	"hearx/pkg/model"
+	"hearx/pkg/repository"
+	"hearx/pkg/storage"
+
+	"go.uber.org/zap"
+)
+
+// DeleteTask soft-deletes a task. It drops out of every listing but keeps
+// its history, and RestoreTaskRevision brings it back. A task with live
+// subtasks cannot be deleted, as they would be left under a missing parent.
+func (s *taskService) DeleteTask(ctx context.Context, id int64) error {
+	log := hlog.FromContext(ctx, s.logger)
+	log.Debug("service: deleting task", zap.Int64("id", id))
+	ctx = storage.WithPrimary(ctx)
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
+		log.Error("service: FindByID failed", zap.Error(err), zap.Int64("id", id))
+		return err
+	}
+	children, err := s.repo.FindChildren(ctx, id)
+	if err != nil {
+		log.Error("service: FindChildren failed", zap.Error(err), zap.Int64("id", id))
+		return err
+	}
+	if len(children) > 0 {
+		err := fmt.Errorf("%w: task %d has %d subtasks", ErrInvalidTask, id, len(children))
+		log.Warn("service: DeleteTask rejected", zap.Error(err), zap.Int64("id", id))
+		return err
+	}
+	if _, err := s.repo.Delete(ctx, id); err != nil {
+		log.Error("service: DeleteTask failed", zap.Error(err), zap.Int64("id", id))
+		return err
+	}
+	return nil
+}
+
+// TaskHistory pages through the revisions of a task, deleted or not, oldest
+// first, each with the fields it changed from the revision before. A task
+// with no revisions at all returns repository.ErrNotFound.
+func (s *taskService) TaskHistory(ctx context.Context, id, afterRevision int64, limit int) ([]model.Revision, error) {
+	log := hlog.FromContext(ctx, s.logger)
+	log.Debug("service: listing task history", zap.Int64("id", id), zap.Int64("after", afterRevision))
+	revs, err := s.repo.FindRevisions(ctx, id, afterRevision, limit)
+	if err != nil {
+		log.Error("service: FindRevisions failed", zap.Error(err), zap.Int64("id", id))
+		return nil, err
+	}
+	if len(revs) == 0 && afterRevision == 0 {
+		return nil, fmt.Errorf("%w: %d", repository.ErrNotFound, id)
+	}
+
+	// the first revision on a later page diffs against the last one on the
+	// page before; revision 1 diffs against nothing
+	var prev model.Revision
+	if afterRevision > 0 && len(revs) > 0 {
+		prev, err = s.repo.FindRevision(ctx, id, afterRevision)
+		if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			log.Error("service: FindRevision failed", zap.Error(err), zap.Int64("id", id))
+			return nil, err
+		}
+	}
+	for i := range revs {
+		revs[i].Changes = diffRevisions(prev, revs[i])
+		prev = revs[i]
+	}
+	return revs, nil
+}
+
+// RestoreTaskRevision writes a task back as it was at an earlier revision,
+// undeleting it if it has been deleted since. The restore is itself a new
+// revision, so it can be undone the same way. A parent, project or assignee
+// that is no longer valid rejects the restore with ErrInvalidTask.
+func (s *taskService) RestoreTaskRevision(ctx context.Context, id, revision int64) (model.Task, error) {
+	log := hlog.FromContext(ctx, s.logger)
+	log.Debug("service: restoring task revision", zap.Int64("id", id), zap.Int64("revision", revision))
+	ctx = storage.WithPrimary(ctx)
+	rev, err := s.repo.FindRevision(ctx, id, revision)
+	if errors.Is(err, repository.ErrNotFound) {
+		return model.Task{}, fmt.Errorf("%w: task %d has no revision %d", repository.ErrNotFound, id, revision)
+	}
+	if err != nil {
+		log.Error("service: FindRevision failed", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	state := rev.Task
+	state.ID = id
+	if err := s.checkRestore(ctx, state); err != nil {
+		log.Warn("service: RestoreTaskRevision rejected", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	restored, err := s.repo.Restore(repository.WithAction(ctx, model.ActionRestore, fmt.Sprintf("revision %d", revision)), state)
+	if err != nil {
+		log.Error("service: RestoreTaskRevision failed", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	return restored, nil
+}
+
+// checkRestore validates an old or deleted state against the tasks,
+// projects and users there are now, as AddTask and the setters would.
+func (s *taskService) checkRestore(ctx context.Context, state model.Task) error {
+	_, err := s.repo.FindByID(ctx, state.ID)
+	deleted := errors.Is(err, repository.ErrNotFound)
+	switch {
+	case deleted:
+		// undeleting an open task opens one more
+		if !state.Completed {
+			if err := s.checkQuota(ctx, state.Owner, 1); err != nil {
+				return err
+			}
+		}
+	case err != nil:
+		return err
+	}
+	if state.ParentID != 0 {
+		if err := s.checkAncestry(ctx, state.ID, state.ParentID); err != nil {
+			return err
+		}
+	}
+	if err := s.checkProject(ctx, state.ProjectID); err != nil {
+		return err
+	}
+	if err := s.checkAssignee(ctx, state.Assignee); err != nil {
+		return err
+	}
+	if deleted {
+		return s.checkRestoredDependencies(ctx, state.ID)
+	}
+	return nil
+}
+
+// checkRestoredDependencies fails if undeleting id would bring back a
+// dependency loop. Dependency checks skip deleted tasks, so a loop can have
+// been closed through id while it was in the trash.
+func (s *taskService) checkRestoredDependencies(ctx context.Context, id int64) error {
+	dependents, err := s.repo.FindDependents(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, d := range dependents {
+		if err := s.checkDependencyCycle(ctx, d.ID, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// diffRevisions lists the fields that differ from prev to cur, in a fixed
+// order. Against the zero Revision it lists every field cur sets.
+func diffRevisions(prev, cur model.Revision) []model.FieldChange {
+	a, b := prev.Task, cur.Task
+	var changes []model.FieldChange
+	add := func(field, from, to string) {
+		if from != to {
+			changes = append(changes, model.FieldChange{Field: field, From: from, To: to})
+		}
+	}
+	add("title", a.Title, b.Title)
+	add("description", a.Description, b.Description)
+	add("completed", strconv.FormatBool(a.Completed), strconv.FormatBool(b.Completed))
+	add("assignee", a.Assignee, b.Assignee)
+	add("parent_id", formatID(a.ParentID), formatID(b.ParentID))
+	add("project_id", formatID(a.ProjectID), formatID(b.ProjectID))
+	add("due_at", formatTime(a.DueAt), formatTime(b.DueAt))
+	add("recurrence", a.Recurrence, b.Recurrence)
+	add("deleted", strconv.FormatBool(prev.Deleted), strconv.FormatBool(cur.Deleted))
+	return changes
+}
+
+func formatID(id int64) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.FormatInt(id, 10)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
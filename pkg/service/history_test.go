@@ -0,0 +1,145 @@
+// pkg/service/history_test.go
+package service_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+
+	"hearx/pkg/model"
+	"hearx/pkg/repository"
+	mockrepo "hearx/pkg/repository/mock_repository"
+	svc "hearx/pkg/service"
+)
+
+var _ = Describe("taskService history", func() {
+	var (
+		ctrl        *gomock.Controller
+		repoMock    *mockrepo.MockTaskRepository
+		projectMock *mockrepo.MockProjectRepository
+		userMock    *mockrepo.MockUserRepository
+		service     svc.TaskService
+		ctx         context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		repoMock = mockrepo.NewMockTaskRepository(ctrl)
+		projectMock = mockrepo.NewMockProjectRepository(ctrl)
+		userMock = mockrepo.NewMockUserRepository(ctrl)
+		service = svc.NewTaskService(repoMock, projectMock, userMock, svc.Config{MaxOpenTasksPerOwner: 1}, zap.NewNop())
+		ctx = context.Background()
+	})
+
+	AfterEach(func() { ctrl.Finish() })
+
+	Describe("DeleteTask", func() {
+		It("should soft-delete a task without subtasks", func() {
+			repoMock.EXPECT().FindByID(gomock.Any(), int64(5)).Return(model.Task{ID: 5}, nil)
+			repoMock.EXPECT().FindChildren(gomock.Any(), int64(5)).Return(nil, nil)
+			repoMock.EXPECT().Delete(gomock.Any(), int64(5)).Return(model.Task{ID: 5}, nil)
+
+			Expect(service.DeleteTask(ctx, 5)).To(Succeed())
+		})
+
+		It("should refuse a task with subtasks", func() {
+			repoMock.EXPECT().FindByID(gomock.Any(), int64(5)).Return(model.Task{ID: 5}, nil)
+			repoMock.EXPECT().FindChildren(gomock.Any(), int64(5)).Return([]model.Task{{ID: 6, ParentID: 5}}, nil)
+
+			Expect(service.DeleteTask(ctx, 5)).To(MatchError(svc.ErrInvalidTask))
+		})
+	})
+
+	Describe("TaskHistory", func() {
+		It("should diff each revision against the one before", func() {
+			repoMock.EXPECT().FindRevisions(gomock.Any(), int64(5), int64(0), 10).Return([]model.Revision{
+				{Number: 1, Task: model.Task{ID: 5, Title: "draft"}},
+				{Number: 2, Task: model.Task{ID: 5, Title: "final", Completed: true}},
+				{Number: 3, Task: model.Task{ID: 5, Title: "final", Completed: true}, Deleted: true},
+			}, nil)
+
+			revs, err := service.TaskHistory(ctx, 5, 0, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(revs[0].Changes).To(Equal([]model.FieldChange{{Field: "title", To: "draft"}}))
+			Expect(revs[1].Changes).To(Equal([]model.FieldChange{
+				{Field: "title", From: "draft", To: "final"},
+				{Field: "completed", From: "false", To: "true"},
+			}))
+			Expect(revs[2].Changes).To(Equal([]model.FieldChange{{Field: "deleted", From: "false", To: "true"}}))
+		})
+
+		It("should diff the first revision of a later page against the page before", func() {
+			repoMock.EXPECT().FindRevisions(gomock.Any(), int64(5), int64(1), 10).
+				Return([]model.Revision{{Number: 2, Task: model.Task{ID: 5, Title: "b"}}}, nil)
+			repoMock.EXPECT().FindRevision(gomock.Any(), int64(5), int64(1)).
+				Return(model.Revision{Number: 1, Task: model.Task{ID: 5, Title: "a"}}, nil)
+
+			revs, err := service.TaskHistory(ctx, 5, 1, 10)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(revs[0].Changes).To(Equal([]model.FieldChange{{Field: "title", From: "a", To: "b"}}))
+		})
+
+		It("should return ErrNotFound for a task without revisions", func() {
+			repoMock.EXPECT().FindRevisions(gomock.Any(), int64(9), int64(0), 10).Return(nil, nil)
+
+			_, err := service.TaskHistory(ctx, 9, 0, 10)
+			Expect(err).To(MatchError(repository.ErrNotFound))
+		})
+	})
+
+	Describe("RestoreTaskRevision", func() {
+		It("should write the revision back", func() {
+			old := model.Task{ID: 5, Title: "draft", Assignee: "alice"}
+			repoMock.EXPECT().FindRevision(gomock.Any(), int64(5), int64(1)).Return(model.Revision{Number: 1, Task: old}, nil)
+			repoMock.EXPECT().FindByID(gomock.Any(), int64(5)).Return(model.Task{ID: 5, Title: "final"}, nil)
+			userMock.EXPECT().Exists(gomock.Any(), "alice").Return(true, nil)
+			repoMock.EXPECT().Restore(withAction(model.ActionRestore, "revision 1"), old).Return(old, nil)
+
+			t, err := service.RestoreTaskRevision(ctx, 5, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t.Title).To(Equal("draft"))
+		})
+
+		It("should count an undeleted open task against the quota", func() {
+			old := model.Task{ID: 5, Title: "draft", Owner: "alice"}
+			repoMock.EXPECT().FindRevision(gomock.Any(), int64(5), int64(1)).Return(model.Revision{Number: 1, Task: old}, nil)
+			repoMock.EXPECT().FindByID(gomock.Any(), int64(5)).Return(model.Task{}, repository.ErrNotFound)
+			repoMock.EXPECT().CountOpen(gomock.Any(), "alice").Return(int64(1), nil)
+
+			_, err := service.RestoreTaskRevision(ctx, 5, 1)
+			Expect(err).To(MatchError(svc.ErrQuotaExceeded))
+		})
+
+		It("should not restore a deleted task into a dependency loop", func() {
+			old := model.Task{ID: 5, Title: "draft", Completed: true}
+			repoMock.EXPECT().FindRevision(gomock.Any(), int64(5), int64(1)).Return(model.Revision{Number: 1, Task: old}, nil)
+			repoMock.EXPECT().FindByID(gomock.Any(), int64(5)).Return(model.Task{}, repository.ErrNotFound)
+			repoMock.EXPECT().FindDependents(gomock.Any(), int64(5)).Return([]model.Task{{ID: 6}}, nil)
+			repoMock.EXPECT().FindBlockers(gomock.Any(), int64(5)).Return([]model.Task{{ID: 6}}, nil)
+
+			_, err := service.RestoreTaskRevision(ctx, 5, 1)
+			Expect(err).To(MatchError(svc.ErrCycle))
+		})
+
+		It("should reject a revision whose parent is gone", func() {
+			old := model.Task{ID: 5, Title: "draft", ParentID: 2}
+			repoMock.EXPECT().FindRevision(gomock.Any(), int64(5), int64(1)).Return(model.Revision{Number: 1, Task: old}, nil)
+			repoMock.EXPECT().FindByID(gomock.Any(), int64(5)).Return(model.Task{ID: 5}, nil)
+			repoMock.EXPECT().FindByID(gomock.Any(), int64(2)).Return(model.Task{}, repository.ErrNotFound)
+
+			_, err := service.RestoreTaskRevision(ctx, 5, 1)
+			Expect(err).To(MatchError(svc.ErrInvalidTask))
+		})
+
+		It("should return ErrNotFound for a missing revision", func() {
+			repoMock.EXPECT().FindRevision(gomock.Any(), int64(5), int64(7)).Return(model.Revision{}, repository.ErrNotFound)
+
+			_, err := service.RestoreTaskRevision(ctx, 5, 7)
+			Expect(err).To(MatchError(repository.ErrNotFound))
+		})
+	})
+})
@@ -8,6 +8,7 @@ import (
 	context "context"
 	model "hearx/pkg/model"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,6 +36,20 @@ func (m *MockTaskService) EXPECT() *MockTaskServiceMockRecorder {
 	return m.recorder
 }
 
+// AddDependency mocks base method.
+func (m *MockTaskService) AddDependency(ctx context.Context, taskID, blockerID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddDependency", ctx, taskID, blockerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddDependency indicates an expected call of AddDependency.
+func (mr *MockTaskServiceMockRecorder) AddDependency(ctx, taskID, blockerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddDependency", reflect.TypeOf((*MockTaskService)(nil).AddDependency), ctx, taskID, blockerID)
+}
+
 // AddTask mocks base method.
 func (m *MockTaskService) AddTask(ctx context.Context, task model.Task) (model.Task, error) {
 	m.ctrl.T.Helper()
@@ -50,32 +65,286 @@ func (mr *MockTaskServiceMockRecorder) AddTask(ctx, task interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTask", reflect.TypeOf((*MockTaskService)(nil).AddTask), ctx, task)
 }
 
-// CompleteTask mocks base method.
-func (m *MockTaskService) CompleteTask(ctx context.Context, id int64) (model.Task, error) {
+// AddTasks mocks base method.
+func (m *MockTaskService) AddTasks(ctx context.Context, tasks []model.Task) ([]model.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CompleteTask", ctx, id)
+	ret := m.ctrl.Call(m, "AddTasks", ctx, tasks)
+	ret0, _ := ret[0].([]model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddTasks indicates an expected call of AddTasks.
+func (mr *MockTaskServiceMockRecorder) AddTasks(ctx, tasks interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTasks", reflect.TypeOf((*MockTaskService)(nil).AddTasks), ctx, tasks)
+}
+
+// AssignTask mocks base method.
+func (m *MockTaskService) AssignTask(ctx context.Context, id int64, assignee string) (model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignTask", ctx, id, assignee)
 	ret0, _ := ret[0].(model.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
+// AssignTask indicates an expected call of AssignTask.
+func (mr *MockTaskServiceMockRecorder) AssignTask(ctx, id, assignee interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignTask", reflect.TypeOf((*MockTaskService)(nil).AssignTask), ctx, id, assignee)
+}
+
+// CompleteTask mocks base method.
+func (m *MockTaskService) CompleteTask(ctx context.Context, id int64) (model.Task, model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteTask", ctx, id)
+	ret0, _ := ret[0].(model.Task)
+	ret1, _ := ret[1].(model.Task)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
 // CompleteTask indicates an expected call of CompleteTask.
 func (mr *MockTaskServiceMockRecorder) CompleteTask(ctx, id interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteTask", reflect.TypeOf((*MockTaskService)(nil).CompleteTask), ctx, id)
 }
 
+// DeleteTask mocks base method.
+func (m *MockTaskService) DeleteTask(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTask", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTask indicates an expected call of DeleteTask.
+func (mr *MockTaskServiceMockRecorder) DeleteTask(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTask", reflect.TypeOf((*MockTaskService)(nil).DeleteTask), ctx, id)
+}
+
+// ListBlockers mocks base method.
+func (m *MockTaskService) ListBlockers(ctx context.Context, taskID int64) ([]model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBlockers", ctx, taskID)
+	ret0, _ := ret[0].([]model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBlockers indicates an expected call of ListBlockers.
+func (mr *MockTaskServiceMockRecorder) ListBlockers(ctx, taskID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBlockers", reflect.TypeOf((*MockTaskService)(nil).ListBlockers), ctx, taskID)
+}
+
+// ListChildren mocks base method.
+func (m *MockTaskService) ListChildren(ctx context.Context, parentID int64) ([]model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChildren", ctx, parentID)
+	ret0, _ := ret[0].([]model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChildren indicates an expected call of ListChildren.
+func (mr *MockTaskServiceMockRecorder) ListChildren(ctx, parentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChildren", reflect.TypeOf((*MockTaskService)(nil).ListChildren), ctx, parentID)
+}
+
+// ListDeletedTasks mocks base method.
+func (m *MockTaskService) ListDeletedTasks(ctx context.Context, filter model.TaskFilter) ([]model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeletedTasks", ctx, filter)
+	ret0, _ := ret[0].([]model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeletedTasks indicates an expected call of ListDeletedTasks.
+func (mr *MockTaskServiceMockRecorder) ListDeletedTasks(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeletedTasks", reflect.TypeOf((*MockTaskService)(nil).ListDeletedTasks), ctx, filter)
+}
+
 // ListTasks mocks base method.
-func (m *MockTaskService) ListTasks(ctx context.Context) ([]model.Task, error) {
+func (m *MockTaskService) ListTasks(ctx context.Context, filter model.TaskFilter) ([]model.Task, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListTasks", ctx)
+	ret := m.ctrl.Call(m, "ListTasks", ctx, filter)
 	ret0, _ := ret[0].([]model.Task)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListTasks indicates an expected call of ListTasks.
-func (mr *MockTaskServiceMockRecorder) ListTasks(ctx interface{}) *gomock.Call {
+func (mr *MockTaskServiceMockRecorder) ListTasks(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTasks", reflect.TypeOf((*MockTaskService)(nil).ListTasks), ctx, filter)
+}
+
+// ListUsers mocks base method.
+func (m *MockTaskService) ListUsers(ctx context.Context) ([]model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", ctx)
+	ret0, _ := ret[0].([]model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockTaskServiceMockRecorder) ListUsers(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockTaskService)(nil).ListUsers), ctx)
+}
+
+// MoveTask mocks base method.
+func (m *MockTaskService) MoveTask(ctx context.Context, id, parentID int64) (model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveTask", ctx, id, parentID)
+	ret0, _ := ret[0].(model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MoveTask indicates an expected call of MoveTask.
+func (mr *MockTaskServiceMockRecorder) MoveTask(ctx, id, parentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveTask", reflect.TypeOf((*MockTaskService)(nil).MoveTask), ctx, id, parentID)
+}
+
+// PlanTasks mocks base method.
+func (m *MockTaskService) PlanTasks(ctx context.Context, filter model.TaskFilter) ([]model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlanTasks", ctx, filter)
+	ret0, _ := ret[0].([]model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlanTasks indicates an expected call of PlanTasks.
+func (mr *MockTaskServiceMockRecorder) PlanTasks(ctx, filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlanTasks", reflect.TypeOf((*MockTaskService)(nil).PlanTasks), ctx, filter)
+}
+
+// RemoveDependency mocks base method.
+func (m *MockTaskService) RemoveDependency(ctx context.Context, taskID, blockerID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveDependency", ctx, taskID, blockerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveDependency indicates an expected call of RemoveDependency.
+func (mr *MockTaskServiceMockRecorder) RemoveDependency(ctx, taskID, blockerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveDependency", reflect.TypeOf((*MockTaskService)(nil).RemoveDependency), ctx, taskID, blockerID)
+}
+
+// RestoreTaskRevision mocks base method.
+func (m *MockTaskService) RestoreTaskRevision(ctx context.Context, id, revision int64) (model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTaskRevision", ctx, id, revision)
+	ret0, _ := ret[0].(model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreTaskRevision indicates an expected call of RestoreTaskRevision.
+func (mr *MockTaskServiceMockRecorder) RestoreTaskRevision(ctx, id, revision interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTaskRevision", reflect.TypeOf((*MockTaskService)(nil).RestoreTaskRevision), ctx, id, revision)
+}
+
+// SetRecurrence mocks base method.
+func (m *MockTaskService) SetRecurrence(ctx context.Context, id int64, rule string, due time.Time) (model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRecurrence", ctx, id, rule, due)
+	ret0, _ := ret[0].(model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetRecurrence indicates an expected call of SetRecurrence.
+func (mr *MockTaskServiceMockRecorder) SetRecurrence(ctx, id, rule, due interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRecurrence", reflect.TypeOf((*MockTaskService)(nil).SetRecurrence), ctx, id, rule, due)
+}
+
+// SetTaskProject mocks base method.
+func (m *MockTaskService) SetTaskProject(ctx context.Context, id, projectID int64) (model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTaskProject", ctx, id, projectID)
+	ret0, _ := ret[0].(model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetTaskProject indicates an expected call of SetTaskProject.
+func (mr *MockTaskServiceMockRecorder) SetTaskProject(ctx, id, projectID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTaskProject", reflect.TypeOf((*MockTaskService)(nil).SetTaskProject), ctx, id, projectID)
+}
+
+// TaskHistory mocks base method.
+func (m *MockTaskService) TaskHistory(ctx context.Context, id, afterRevision int64, limit int) ([]model.Revision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TaskHistory", ctx, id, afterRevision, limit)
+	ret0, _ := ret[0].([]model.Revision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TaskHistory indicates an expected call of TaskHistory.
+func (mr *MockTaskServiceMockRecorder) TaskHistory(ctx, id, afterRevision, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TaskHistory", reflect.TypeOf((*MockTaskService)(nil).TaskHistory), ctx, id, afterRevision, limit)
+}
+
+// UnassignTask mocks base method.
+func (m *MockTaskService) UnassignTask(ctx context.Context, id int64) (model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnassignTask", ctx, id)
+	ret0, _ := ret[0].(model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnassignTask indicates an expected call of UnassignTask.
+func (mr *MockTaskServiceMockRecorder) UnassignTask(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnassignTask", reflect.TypeOf((*MockTaskService)(nil).UnassignTask), ctx, id)
+}
+
+// UndeleteTask mocks base method.
+func (m *MockTaskService) UndeleteTask(ctx context.Context, id int64) (model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UndeleteTask", ctx, id)
+	ret0, _ := ret[0].(model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UndeleteTask indicates an expected call of UndeleteTask.
+func (mr *MockTaskServiceMockRecorder) UndeleteTask(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndeleteTask", reflect.TypeOf((*MockTaskService)(nil).UndeleteTask), ctx, id)
+}
+
+// UpdateTask mocks base method.
+func (m *MockTaskService) UpdateTask(ctx context.Context, task model.Task) (model.Task, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTask", ctx, task)
+	ret0, _ := ret[0].(model.Task)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTask indicates an expected call of UpdateTask.
+func (mr *MockTaskServiceMockRecorder) UpdateTask(ctx, task interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTasks", reflect.TypeOf((*MockTaskService)(nil).ListTasks), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTask", reflect.TypeOf((*MockTaskService)(nil).UpdateTask), ctx, task)
 }
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// periodic runs a background job once at start and then on every tick, for
+// the maintenance jobs the server runs alongside its RPCs.
+type periodic struct {
+	cancel context.CancelFunc
+	done   sync.WaitGroup
+}
+
+// start runs fn in a goroutine now and every interval until stop. fn gets a
+// context that stop cancels, so a run in progress is abandoned.
+func (p *periodic) start(interval time.Duration, fn func(context.Context)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	p.done.Add(1)
+	go func() {
+		defer p.done.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			fn(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// stop cancels the job and waits for it to return. It is a no-op if the job
+// never started.
+func (p *periodic) stop() {
+	if p.cancel == nil {
+		return
+	}
+	p.cancel()
+	p.done.Wait()
+}
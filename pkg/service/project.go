@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	hlog "hearx/pkg/logger"
+	"hearx/pkg/model"
+	"hearx/pkg/repository"
+	"hearx/pkg/storage"
+
+	"go.uber.org/zap"
+)
+
+// SetTaskProject moves a task into a project, or out of any when projectID
+// is zero. Archived projects take no new tasks.
+func (s *taskService) SetTaskProject(ctx context.Context, id, projectID int64) (model.Task, error) {
+	log := hlog.FromContext(ctx, s.logger)
+	log.Debug("service: setting task project", zap.Int64("id", id), zap.Int64("project_id", projectID))
+	ctx = storage.WithPrimary(ctx)
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
+		log.Error("service: FindByID failed", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	if err := s.checkProject(ctx, projectID); err != nil {
+		log.Warn("service: SetTaskProject rejected", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	moved, err := s.repo.SetProject(ctx, id, projectID)
+	if err != nil {
+		log.Error("service: SetTaskProject failed", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	return moved, nil
+}
+
+// checkProject reports a missing or archived project as an invalid task.
+// Zero, no project, always passes.
+func (s *taskService) checkProject(ctx context.Context, projectID int64) error {
+	if projectID == 0 {
+		return nil
+	}
+	p, err := s.projects.FindByID(ctx, projectID)
+	switch {
+	case errors.Is(err, repository.ErrProjectNotFound):
+		return fmt.Errorf("%w: project %d does not exist", ErrInvalidTask, projectID)
+	case err != nil:
+		return err
+	case p.Archived:
+		return fmt.Errorf("%w: project %d is archived", ErrInvalidTask, projectID)
+	}
+	return nil
+}
+
+// checkProjects runs checkProject once for every project a batch uses.
+func (s *taskService) checkProjects(ctx context.Context, tasks []model.Task) error {
+	seen := map[int64]bool{}
+	for _, t := range tasks {
+		if t.ProjectID == 0 || seen[t.ProjectID] {
+			continue
+		}
+		seen[t.ProjectID] = true
+		if err := s.checkProject(ctx, t.ProjectID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
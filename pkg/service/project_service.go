@@ -0,0 +1,144 @@
+//go:generate mockgen -source=project_service.go -destination=mock_service/mock_project_service.go -package=mock_service
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"hearx/pkg/auth"
+	hlog "hearx/pkg/logger"
+	"hearx/pkg/model"
+	"hearx/pkg/repository"
+	"hearx/pkg/storage"
+
+	"go.uber.org/zap"
+)
+
+type ProjectService interface {
+	CreateProject(ctx context.Context, project model.Project) (model.Project, error)
+	GetProject(ctx context.Context, id int64) (model.Project, error)
+	UpdateProject(ctx context.Context, project model.Project) (model.Project, error)
+	ListProjects(ctx context.Context, includeArchived bool) ([]model.Project, error)
+	DeleteProject(ctx context.Context, id int64) error
+	ProjectStats(ctx context.Context, id int64) (model.ProjectStats, error)
+}
+
+// ErrInvalidProject is returned when a project fails validation.
+var ErrInvalidProject = errors.New("invalid project")
+
+// maxProjectName is the length of the name column, in characters.
+const maxProjectName = 255
+
+type projectService struct {
+	repo   repository.ProjectRepository
+	logger *zap.Logger
+}
+
+func NewProjectService(repo repository.ProjectRepository, logger *zap.Logger) ProjectService {
+	return &projectService{repo: repo, logger: logger}
+}
+
+func (s *projectService) CreateProject(ctx context.Context, project model.Project) (model.Project, error) {
+	log := hlog.FromContext(ctx, s.logger)
+	log.Debug("service: creating project", zap.String("name", project.Name))
+	if err := checkProjectName(&project); err != nil {
+		return model.Project{}, err
+	}
+	if caller, ok := auth.CallerFromContext(ctx); ok {
+		project.Owner = caller
+	}
+	created, err := s.repo.Create(ctx, project)
+	if err != nil {
+		log.Error("service: CreateProject failed", zap.Error(err), zap.String("name", project.Name))
+		return model.Project{}, err
+	}
+	log.Debug("service: project created", zap.Int64("id", created.ID))
+	return created, nil
+}
+
+func (s *projectService) GetProject(ctx context.Context, id int64) (model.Project, error) {
+	log := hlog.FromContext(ctx, s.logger)
+	log.Debug("service: getting project", zap.Int64("id", id))
+	p, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		log.Error("service: GetProject failed", zap.Error(err), zap.Int64("id", id))
+	}
+	return p, err
+}
+
+// UpdateProject replaces the name, description and archived flag of an
+// existing project; its owner is kept.
+func (s *projectService) UpdateProject(ctx context.Context, project model.Project) (model.Project, error) {
+	log := hlog.FromContext(ctx, s.logger)
+	log.Debug("service: updating project", zap.Int64("id", project.ID))
+	if err := checkProjectName(&project); err != nil {
+		return model.Project{}, err
+	}
+	ctx = storage.WithPrimary(ctx)
+	p, err := s.repo.FindByID(ctx, project.ID)
+	if err != nil {
+		log.Error("service: FindByID failed", zap.Error(err), zap.Int64("id", project.ID))
+		return model.Project{}, err
+	}
+	p.Name = project.Name
+	p.Description = project.Description
+	p.Archived = project.Archived
+	updated, err := s.repo.Update(ctx, p)
+	if err != nil {
+		log.Error("service: UpdateProject failed", zap.Error(err), zap.Int64("id", project.ID))
+		return model.Project{}, err
+	}
+	log.Debug("service: project updated", zap.Int64("id", updated.ID))
+	return updated, nil
+}
+
+func (s *projectService) ListProjects(ctx context.Context, includeArchived bool) ([]model.Project, error) {
+	log := hlog.FromContext(ctx, s.logger)
+	log.Debug("service: listing projects", zap.Bool("include_archived", includeArchived))
+	list, err := s.repo.FindAll(ctx, includeArchived)
+	if err != nil {
+		log.Error("service: ListProjects failed", zap.Error(err))
+	}
+	return list, err
+}
+
+// DeleteProject removes a project; its tasks are kept, outside of any project.
+func (s *projectService) DeleteProject(ctx context.Context, id int64) error {
+	log := hlog.FromContext(ctx, s.logger)
+	log.Debug("service: deleting project", zap.Int64("id", id))
+	if err := s.repo.Delete(ctx, id); err != nil {
+		log.Error("service: DeleteProject failed", zap.Error(err), zap.Int64("id", id))
+		return err
+	}
+	return nil
+}
+
+func (s *projectService) ProjectStats(ctx context.Context, id int64) (model.ProjectStats, error) {
+	log := hlog.FromContext(ctx, s.logger)
+	log.Debug("service: counting project tasks", zap.Int64("id", id))
+	if _, err := s.repo.FindByID(ctx, id); err != nil {
+		log.Error("service: FindByID failed", zap.Error(err), zap.Int64("id", id))
+		return model.ProjectStats{}, err
+	}
+	stats, err := s.repo.Stats(ctx, id)
+	if err != nil {
+		log.Error("service: ProjectStats failed", zap.Error(err), zap.Int64("id", id))
+	}
+	return stats, err
+}
+
+// checkProjectName trims the name and rejects an empty or overlong one.
+func checkProjectName(p *model.Project) error {
+	p.Name = strings.TrimSpace(p.Name)
+	switch {
+	case p.Name == "":
+		return fmt.Errorf("%w: name is required", ErrInvalidProject)
+	case utf8.RuneCountInString(p.Name) > maxProjectName:
+		return fmt.Errorf("%w: name is longer than %d characters", ErrInvalidProject, maxProjectName)
+	}
+	return nil
+}
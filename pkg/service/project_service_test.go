@@ -0,0 +1,166 @@
+// pkg/service/project_service_test.go
+package service_test
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+
+	"hearx/pkg/auth"
+	"hearx/pkg/model"
+	"hearx/pkg/repository"
+	mockrepo "hearx/pkg/repository/mock_repository"
+	svc "hearx/pkg/service"
+)
+
+var _ = Describe("projectService", func() {
+	var (
+		ctrl        *gomock.Controller
+		projectMock *mockrepo.MockProjectRepository
+		service     svc.ProjectService
+		ctx         context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		projectMock = mockrepo.NewMockProjectRepository(ctrl)
+		service = svc.NewProjectService(projectMock, zap.NewNop())
+		ctx = context.Background()
+	})
+
+	AfterEach(func() { ctrl.Finish() })
+
+	Describe("CreateProject", func() {
+		It("should trim the name and record the caller as owner", func() {
+			projectMock.EXPECT().Create(gomock.Any(), model.Project{Name: "Home", Owner: "alice"}).
+				Return(model.Project{ID: 1, Name: "Home", Owner: "alice"}, nil)
+
+			p, err := service.CreateProject(auth.WithCaller(ctx, "alice"), model.Project{Name: "  Home "})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p.ID).To(Equal(int64(1)))
+		})
+
+		It("should reject an empty or overlong name", func() {
+			for _, name := range []string{" ", strings.Repeat("x", 256)} {
+				_, err := service.CreateProject(ctx, model.Project{Name: name})
+				Expect(err).To(MatchError(svc.ErrInvalidProject))
+			}
+		})
+	})
+
+	Describe("UpdateProject", func() {
+		It("should replace name, description and archived flag but keep the owner", func() {
+			projectMock.EXPECT().FindByID(gomock.Any(), int64(3)).
+				Return(model.Project{ID: 3, Name: "Old", Owner: "alice"}, nil)
+			want := model.Project{ID: 3, Name: "New", Description: "d", Archived: true, Owner: "alice"}
+			projectMock.EXPECT().Update(gomock.Any(), want).Return(want, nil)
+
+			p, err := service.UpdateProject(ctx, model.Project{ID: 3, Name: "New", Description: "d", Archived: true, Owner: "bob"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p).To(Equal(want))
+		})
+
+		It("should return ErrProjectNotFound for a missing project", func() {
+			projectMock.EXPECT().FindByID(gomock.Any(), int64(3)).Return(model.Project{}, repository.ErrProjectNotFound)
+
+			_, err := service.UpdateProject(ctx, model.Project{ID: 3, Name: "New"})
+			Expect(err).To(MatchError(repository.ErrProjectNotFound))
+		})
+	})
+
+	Describe("ProjectStats", func() {
+		It("should count the tasks of an existing project", func() {
+			projectMock.EXPECT().FindByID(gomock.Any(), int64(3)).Return(model.Project{ID: 3}, nil)
+			projectMock.EXPECT().Stats(gomock.Any(), int64(3)).
+				Return(model.ProjectStats{Total: 4, Completed: 1, Open: 3, Overdue: 2}, nil)
+
+			s, err := service.ProjectStats(ctx, 3)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(s.Open).To(Equal(int64(3)))
+		})
+
+		It("should not count a missing project", func() {
+			projectMock.EXPECT().FindByID(gomock.Any(), int64(3)).Return(model.Project{}, repository.ErrProjectNotFound)
+
+			_, err := service.ProjectStats(ctx, 3)
+			Expect(err).To(MatchError(repository.ErrProjectNotFound))
+		})
+	})
+})
+
+var _ = Describe("taskService projects", func() {
+	var (
+		ctrl        *gomock.Controller
+		repoMock    *mockrepo.MockTaskRepository
+		projectMock *mockrepo.MockProjectRepository
+		service     svc.TaskService
+		ctx         context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		repoMock = mockrepo.NewMockTaskRepository(ctrl)
+		projectMock = mockrepo.NewMockProjectRepository(ctrl)
+		service = svc.NewTaskService(repoMock, projectMock, mockrepo.NewMockUserRepository(ctrl), svc.Config{}, zap.NewNop())
+		ctx = context.Background()
+	})
+
+	AfterEach(func() { ctrl.Finish() })
+
+	Describe("AddTask", func() {
+		It("should add a task to an open project", func() {
+			projectMock.EXPECT().FindByID(gomock.Any(), int64(3)).Return(model.Project{ID: 3}, nil)
+			repoMock.EXPECT().Create(gomock.Any(), model.Task{Title: "T", ProjectID: 3}, int64(0)).
+				Return(model.Task{ID: 1, Title: "T", ProjectID: 3}, nil)
+
+			t, err := service.AddTask(ctx, model.Task{Title: "T", ProjectID: 3})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t.ProjectID).To(Equal(int64(3)))
+		})
+
+		It("should reject a missing or archived project", func() {
+			projectMock.EXPECT().FindByID(gomock.Any(), int64(3)).Return(model.Project{}, repository.ErrProjectNotFound)
+			projectMock.EXPECT().FindByID(gomock.Any(), int64(4)).Return(model.Project{ID: 4, Archived: true}, nil)
+
+			_, err := service.AddTask(ctx, model.Task{Title: "T", ProjectID: 3})
+			Expect(err).To(MatchError(svc.ErrInvalidTask))
+			_, err = service.AddTask(ctx, model.Task{Title: "T", ProjectID: 4})
+			Expect(err).To(MatchError(svc.ErrInvalidTask))
+		})
+	})
+
+	Describe("AddTasks", func() {
+		It("should look each project up once", func() {
+			projectMock.EXPECT().FindByID(gomock.Any(), int64(3)).Return(model.Project{ID: 3}, nil).Times(1)
+			repoMock.EXPECT().CreateBatch(gomock.Any(), gomock.Len(2), int64(0)).Return([]model.Task{{ID: 1}, {ID: 2}}, nil)
+
+			_, err := service.AddTasks(ctx, []model.Task{{Title: "A", ProjectID: 3}, {Title: "B", ProjectID: 3}})
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("SetTaskProject", func() {
+		It("should move a task into a project", func() {
+			repoMock.EXPECT().FindByID(gomock.Any(), int64(1)).Return(model.Task{ID: 1}, nil)
+			projectMock.EXPECT().FindByID(gomock.Any(), int64(3)).Return(model.Project{ID: 3}, nil)
+			repoMock.EXPECT().SetProject(gomock.Any(), int64(1), int64(3)).Return(model.Task{ID: 1, ProjectID: 3}, nil)
+
+			t, err := service.SetTaskProject(ctx, 1, 3)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(t.ProjectID).To(Equal(int64(3)))
+		})
+
+		It("should take a task out of its project without a lookup", func() {
+			repoMock.EXPECT().FindByID(gomock.Any(), int64(1)).Return(model.Task{ID: 1, ProjectID: 3}, nil)
+			repoMock.EXPECT().SetProject(gomock.Any(), int64(1), int64(0)).Return(model.Task{ID: 1}, nil)
+
+			_, err := service.SetTaskProject(ctx, 1, 0)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
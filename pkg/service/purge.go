@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"hearx/pkg/model"
+	"hearx/pkg/repository"
+
+	"go.uber.org/zap"
+)
+
+// PurgeConfig says how long deleted tasks stay in the trash.
+type PurgeConfig struct {
+	// Retention is how long a task stays in the trash before it is deleted
+	// for good; zero keeps deleted tasks forever.
+	Retention time.Duration
+	// Interval is how often the purger looks for expired tasks.
+	Interval time.Duration
+	// BatchSize caps the tasks one DELETE removes, so clearing a backlog
+	// does not hold locks for long.
+	BatchSize int
+}
+
+// DefaultPurgeConfig keeps deleted tasks for 30 days and checks hourly.
+func DefaultPurgeConfig() PurgeConfig {
+	return PurgeConfig{Retention: 30 * 24 * time.Hour, Interval: time.Hour, BatchSize: 500}
+}
+
+// Purger hard-deletes tasks that have been in the trash longer than the
+// retention period. Purged tasks lose their history and cannot be restored;
+// their audit events are kept.
+type Purger struct {
+	repo   repository.TaskRepository
+	cfg    PurgeConfig
+	logger *zap.Logger
+	job    periodic
+}
+
+// NewPurger constructs a Purger. It does nothing until Start.
+func NewPurger(repo repository.TaskRepository, cfg PurgeConfig, logger *zap.Logger) *Purger {
+	return &Purger{repo: repo, cfg: cfg, logger: logger}
+}
+
+// Start purges once and then every Interval, in the background, until Stop.
+// It does not start at all when Retention is zero.
+func (p *Purger) Start() {
+	if p.cfg.Retention <= 0 {
+		p.logger.Info("trash purge disabled")
+		return
+	}
+	p.logger.Info("trash purge starting", zap.Duration("retention", p.cfg.Retention), zap.Duration("interval", p.cfg.Interval))
+	p.job.start(p.cfg.Interval, func(ctx context.Context) {
+		if _, err := p.Purge(ctx); err != nil && ctx.Err() == nil {
+			p.logger.Error("trash purge failed", zap.Error(err))
+		}
+	})
+}
+
+// Stop cancels a purge in progress and waits for the background loop to
+// return.
+func (p *Purger) Stop() {
+	p.job.stop()
+}
+
+// Purge deletes every task deleted more than Retention ago, one batch at a
+// time, and returns how many it deleted. A parent goes in the batch after
+// its last deleted subtask.
+func (p *Purger) Purge(ctx context.Context) (int64, error) {
+	before := time.Now().Add(-p.cfg.Retention)
+	ctx = repository.WithAction(ctx, model.ActionPurge, fmt.Sprintf("deleted over %s ago", p.cfg.Retention))
+	var total int64
+	for {
+		n, err := p.repo.Purge(ctx, before, p.cfg.BatchSize)
+		total += n
+		if err != nil || n == 0 {
+			if total > 0 {
+				p.logger.Info("purged deleted tasks", zap.Int64("count", total))
+			}
+			return total, err
+		}
+	}
+}
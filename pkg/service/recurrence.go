@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"hearx/pkg/ical"
+	hlog "hearx/pkg/logger"
+	"hearx/pkg/model"
+	"hearx/pkg/repository"
+	"hearx/pkg/storage"
+
+	"go.uber.org/zap"
+)
+
+// maxCatchUp bounds how many missed occurrences completing a long-overdue
+// recurring task may skip.
+const maxCatchUp = 1000
+
+// SetRecurrence replaces a task's recurrence rule; an empty rule makes it a
+// one-off task. A zero due keeps the current due date.
+func (s *taskService) SetRecurrence(ctx context.Context, id int64, rule string, due time.Time) (model.Task, error) {
+	log := hlog.FromContext(ctx, s.logger)
+	log.Debug("service: setting recurrence", zap.Int64("id", id), zap.String("rule", rule))
+	ctx = storage.WithPrimary(ctx)
+	t, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		log.Error("service: FindByID failed", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	t.Recurrence = rule
+	if !due.IsZero() {
+		t.DueAt = due
+	}
+	if err := checkSchedule(&t); err != nil {
+		return model.Task{}, err
+	}
+	updated, err := s.repo.Update(repository.WithAction(ctx, model.ActionSetRecurrence, ""), t)
+	if err != nil {
+		log.Error("service: SetRecurrence failed", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	return updated, nil
+}
+
+// checkSchedule validates a task's recurrence and stores it in canonical
+// form. A recurring task needs a due date to count from.
+func checkSchedule(t *model.Task) error {
+	if t.Recurrence == "" {
+		return nil
+	}
+	r, err := ical.ParseRule(t.Recurrence)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidTask, err)
+	}
+	if t.DueAt.IsZero() {
+		return fmt.Errorf("%w: a recurring task needs a due date", ErrInvalidTask)
+	}
+	t.Recurrence = r.String()
+	return nil
+}
+
+// nextOccurrence returns the task to create when t is completed: a copy due
+// at the first occurrence after now, so a chore completed late is not
+// immediately overdue again. ok is false once the rule has run out.
+func nextOccurrence(t model.Task, now time.Time) (next model.Task, ok bool, err error) {
+	r, err := ical.ParseRule(t.Recurrence)
+	if err != nil {
+		return model.Task{}, false, fmt.Errorf("%w: %v", ErrInvalidTask, err)
+	}
+	due := t.DueAt
+	for i := 0; i < maxCatchUp; i++ {
+		if due, r, ok = r.Next(due); !ok || due.After(now) {
+			break
+		}
+	}
+	if !ok {
+		return model.Task{}, false, nil
+	}
+	return model.Task{
+		Title:       t.Title,
+		Description: t.Description,
+		Owner:       t.Owner,
+		Assignee:    t.Assignee,
+		ParentID:    t.ParentID,
+		ProjectID:   t.ProjectID,
+		DueAt:       due,
+		Recurrence:  r.String(),
+	}, true, nil
+}
@@ -0,0 +1,147 @@
+// pkg/service/recurrence_test.go
+package service_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+
+	"hearx/pkg/model"
+	mockrepo "hearx/pkg/repository/mock_repository"
+	svc "hearx/pkg/service"
+)
+
+var _ = Describe("taskService recurrence", func() {
+	var (
+		ctrl     *gomock.Controller
+		repoMock *mockrepo.MockTaskRepository
+		service  svc.TaskService
+		ctx      context.Context
+		due      time.Time
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		repoMock = mockrepo.NewMockTaskRepository(ctrl)
+		service = svc.NewTaskService(repoMock, mockrepo.NewMockProjectRepository(ctrl), mockrepo.NewMockUserRepository(ctrl), svc.Config{}, zap.NewNop())
+		ctx = context.Background()
+		due = time.Now().UTC().Truncate(time.Second).Add(24 * time.Hour)
+	})
+
+	AfterEach(func() { ctrl.Finish() })
+
+	Describe("AddTask", func() {
+		It("should store the rule in canonical form", func() {
+			repoMock.EXPECT().Create(gomock.Any(), model.Task{Title: "Review", DueAt: due, Recurrence: "FREQ=WEEKLY;BYDAY=MO,FR"}, int64(0)).
+				Return(model.Task{ID: 1}, nil)
+
+			_, err := service.AddTask(ctx, model.Task{Title: "Review", DueAt: due, Recurrence: "RRULE:FREQ=weekly;BYDAY=FR,MO"})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject an unsupported rule", func() {
+			_, err := service.AddTask(ctx, model.Task{Title: "Review", DueAt: due, Recurrence: "FREQ=HOURLY"})
+			Expect(err).To(MatchError(svc.ErrInvalidTask))
+		})
+
+		It("should reject a rule without a due date", func() {
+			_, err := service.AddTask(ctx, model.Task{Title: "Review", Recurrence: "FREQ=DAILY"})
+			Expect(err).To(MatchError(svc.ErrInvalidTask))
+		})
+	})
+
+	Describe("CompleteTask", func() {
+		expectOpen := func(t model.Task) {
+			repoMock.EXPECT().FindByID(gomock.Any(), t.ID).Return(t, nil)
+			repoMock.EXPECT().CountOpenBlockers(gomock.Any(), t.ID).Return(int64(0), nil)
+		}
+
+		It("should create the next occurrence with one fewer left", func() {
+			t := model.Task{ID: 4, Title: "Water plants", Owner: "alice", DueAt: due, Recurrence: "FREQ=DAILY;COUNT=3"}
+			expectOpen(t)
+			done := t
+			done.Completed = true
+			next := model.Task{Title: "Water plants", Owner: "alice", DueAt: due.AddDate(0, 0, 1), Recurrence: "FREQ=DAILY;COUNT=2"}
+			created := next
+			created.ID = 5
+			repoMock.EXPECT().UpdateAndCreate(gomock.Any(), done, next).Return(done, created, nil)
+
+			gotDone, gotNext, err := service.CompleteTask(ctx, 4)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gotDone.Completed).To(BeTrue())
+			Expect(gotNext).To(Equal(created))
+		})
+
+		It("should skip occurrences that are already past", func() {
+			overdue := due.AddDate(0, 0, -11) // ten days ago
+			t := model.Task{ID: 4, Title: "Weekly review", DueAt: overdue, Recurrence: "FREQ=WEEKLY"}
+			expectOpen(t)
+			repoMock.EXPECT().UpdateAndCreate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ context.Context, done, next model.Task) (model.Task, model.Task, error) {
+					Expect(next.DueAt).To(Equal(overdue.AddDate(0, 0, 14)))
+					return done, next, nil
+				})
+
+			_, _, err := service.CompleteTask(ctx, 4)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should not repeat once the rule has run out", func() {
+			t := model.Task{ID: 4, Title: "Last one", DueAt: due, Recurrence: "FREQ=DAILY;COUNT=1"}
+			expectOpen(t)
+			repoMock.EXPECT().Update(gomock.Any(), gomock.Any()).Return(model.Task{ID: 4, Completed: true}, nil)
+
+			_, next, err := service.CompleteTask(ctx, 4)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(next.ID).To(BeZero())
+		})
+
+		It("should not create a second occurrence when completed again", func() {
+			t := model.Task{ID: 4, Title: "Done already", Completed: true, DueAt: due, Recurrence: "FREQ=DAILY"}
+			expectOpen(t)
+			repoMock.EXPECT().Update(gomock.Any(), t).Return(t, nil)
+
+			_, next, err := service.CompleteTask(ctx, 4)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(next.ID).To(BeZero())
+		})
+
+		It("should return no occurrence when a concurrent call completed it first", func() {
+			t := model.Task{ID: 4, Title: "Raced", DueAt: due, Recurrence: "FREQ=DAILY"}
+			expectOpen(t)
+			stored := t
+			stored.Completed = true
+			repoMock.EXPECT().UpdateAndCreate(gomock.Any(), gomock.Any(), gomock.Any()).Return(stored, model.Task{}, nil)
+
+			gotDone, gotNext, err := service.CompleteTask(ctx, 4)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(gotDone).To(Equal(stored))
+			Expect(gotNext.ID).To(BeZero())
+		})
+	})
+
+	Describe("SetRecurrence", func() {
+		It("should set the rule and due date", func() {
+			repoMock.EXPECT().FindByID(gomock.Any(), int64(2)).Return(model.Task{ID: 2, Title: "t"}, nil)
+			repoMock.EXPECT().Update(gomock.Any(), model.Task{ID: 2, Title: "t", DueAt: due, Recurrence: "FREQ=MONTHLY;BYMONTHDAY=-1"}).
+				Return(model.Task{ID: 2}, nil)
+
+			_, err := service.SetRecurrence(ctx, 2, "FREQ=MONTHLY;BYMONTHDAY=-1", due)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should clear the rule but keep the due date", func() {
+			t := model.Task{ID: 2, Title: "t", DueAt: due, Recurrence: "FREQ=DAILY"}
+			repoMock.EXPECT().FindByID(gomock.Any(), int64(2)).Return(t, nil)
+			repoMock.EXPECT().Update(gomock.Any(), model.Task{ID: 2, Title: "t", DueAt: due}).Return(model.Task{ID: 2}, nil)
+
+			_, err := service.SetRecurrence(ctx, 2, "", time.Time{})
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+})
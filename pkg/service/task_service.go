@@ -4,61 +4,255 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
+	"hearx/pkg/auth"
+	hlog "hearx/pkg/logger"
 	"hearx/pkg/model"
 	"hearx/pkg/repository"
+	"hearx/pkg/storage"
 
 	"go.uber.org/zap"
 )
 
 type TaskService interface {
 	AddTask(ctx context.Context, task model.Task) (model.Task, error)
-	CompleteTask(ctx context.Context, id int64) (model.Task, error)
-	ListTasks(ctx context.Context) ([]model.Task, error)
+	AddTasks(ctx context.Context, tasks []model.Task) ([]model.Task, error)
+	CompleteTask(ctx context.Context, id int64) (done, next model.Task, err error)
+	UpdateTask(ctx context.Context, task model.Task) (model.Task, error)
+	ListTasks(ctx context.Context, filter model.TaskFilter) ([]model.Task, error)
+	ListChildren(ctx context.Context, parentID int64) ([]model.Task, error)
+	MoveTask(ctx context.Context, id, parentID int64) (model.Task, error)
+	AddDependency(ctx context.Context, taskID, blockerID int64) error
+	RemoveDependency(ctx context.Context, taskID, blockerID int64) error
+	ListBlockers(ctx context.Context, taskID int64) ([]model.Task, error)
+	PlanTasks(ctx context.Context, filter model.TaskFilter) ([]model.Task, error)
+	SetRecurrence(ctx context.Context, id int64, rule string, due time.Time) (model.Task, error)
+	SetTaskProject(ctx context.Context, id, projectID int64) (model.Task, error)
+	AssignTask(ctx context.Context, id int64, assignee string) (model.Task, error)
+	UnassignTask(ctx context.Context, id int64) (model.Task, error)
+	ListUsers(ctx context.Context) ([]model.User, error)
+	DeleteTask(ctx context.Context, id int64) error
+	TaskHistory(ctx context.Context, id, afterRevision int64, limit int) ([]model.Revision, error)
+	RestoreTaskRevision(ctx context.Context, id, revision int64) (model.Task, error)
+	ListDeletedTasks(ctx context.Context, filter model.TaskFilter) ([]model.Task, error)
+	UndeleteTask(ctx context.Context, id int64) (model.Task, error)
+}
+
+// ErrQuotaExceeded is returned by AddTask when the caller already has the
+// maximum number of open tasks. The repository enforces it, atomically with
+// the insert.
+var ErrQuotaExceeded = repository.ErrQuotaExceeded
+
+// ErrInvalidTask is returned when a task fails validation.
+var ErrInvalidTask = errors.New("invalid task")
+
+// ErrCycle is returned by MoveTask when the new parent is the task itself or
+// one of its descendants, and by AddDependency when tasks would end up
+// waiting on each other.
+var ErrCycle = errors.New("task cycle")
+
+// ErrBlocked is returned by CompleteTask while a task it depends on is open.
+var ErrBlocked = errors.New("task is blocked")
+
+// Config holds the business rules that vary per deployment.
+type Config struct {
+	// MaxOpenTasksPerOwner caps the open tasks each caller may own; zero
+	// means unlimited.
+	MaxOpenTasksPerOwner int64
+	// AutoCompleteParents completes a parent task once its last open child
+	// is completed, and so on up the hierarchy.
+	AutoCompleteParents bool
 }
 
 type taskService struct {
-	repo   repository.TaskRepository
-	logger *zap.Logger
+	repo     repository.TaskRepository
+	projects repository.ProjectRepository
+	users    repository.UserRepository
+	cfg      Config
+	logger   *zap.Logger
 }
 
-func NewTaskService(repo repository.TaskRepository, logger *zap.Logger) TaskService {
-	return &taskService{repo: repo, logger: logger}
+func NewTaskService(
+	repo repository.TaskRepository,
+	projects repository.ProjectRepository,
+	users repository.UserRepository,
+	cfg Config,
+	logger *zap.Logger,
+) TaskService {
+	return &taskService{repo: repo, projects: projects, users: users, cfg: cfg, logger: logger}
 }
 
 func (s *taskService) AddTask(ctx context.Context, task model.Task) (model.Task, error) {
-	s.logger.Info("service: adding task", zap.String("title", task.Title))
-	created, err := s.repo.Create(ctx, task)
+	log := hlog.FromContext(ctx, s.logger)
+	log.Debug("service: adding task", zap.String("title", task.Title))
+	if caller, ok := auth.CallerFromContext(ctx); ok {
+		task.Owner = caller
+	}
+	if task.ParentID != 0 {
+		if err := s.checkParent(storage.WithPrimary(ctx), task.ParentID); err != nil {
+			return model.Task{}, err
+		}
+	}
+	if err := s.checkProject(storage.WithPrimary(ctx), task.ProjectID); err != nil {
+		return model.Task{}, err
+	}
+	if err := s.checkAssignee(ctx, task.Assignee); err != nil {
+		return model.Task{}, err
+	}
+	if err := checkSchedule(&task); err != nil {
+		return model.Task{}, err
+	}
+	created, err := s.repo.Create(ctx, task, s.cfg.MaxOpenTasksPerOwner)
+	if errors.Is(err, ErrQuotaExceeded) {
+		log.Warn("service: AddTask rejected", zap.Error(err), zap.String("owner", task.Owner))
+		return model.Task{}, err
+	}
 	if err != nil {
-		s.logger.Error("service: AddTask failed", zap.Error(err), zap.Any("task", task))
+		log.Error("service: AddTask failed", zap.Error(err), zap.Any("task", task))
 		return model.Task{}, err
 	}
-	s.logger.Info("service: task added", zap.Int64("id", created.ID))
+	log.Debug("service: task added", zap.Int64("id", created.ID))
+	return created, nil
+}
+
+// AddTasks creates several tasks at once, all or none. The quota must have
+// room for every open task in the batch.
+func (s *taskService) AddTasks(ctx context.Context, tasks []model.Task) ([]model.Task, error) {
+	log := hlog.FromContext(ctx, s.logger)
+	log.Debug("service: adding tasks", zap.Int("count", len(tasks)))
+	owner, _ := auth.CallerFromContext(ctx)
+	for i := range tasks {
+		if strings.TrimSpace(tasks[i].Title) == "" {
+			return nil, fmt.Errorf("%w: task %d: title is required", ErrInvalidTask, i)
+		}
+		if err := checkSchedule(&tasks[i]); err != nil {
+			return nil, fmt.Errorf("task %d: %w", i, err)
+		}
+		tasks[i].Owner = owner
+	}
+	if err := s.checkProjects(storage.WithPrimary(ctx), tasks); err != nil {
+		return nil, err
+	}
+	if err := s.checkAssignees(ctx, tasks); err != nil {
+		return nil, err
+	}
+	created, err := s.repo.CreateBatch(ctx, tasks, s.cfg.MaxOpenTasksPerOwner)
+	if errors.Is(err, ErrQuotaExceeded) {
+		log.Warn("service: AddTasks rejected", zap.Error(err), zap.String("owner", owner))
+		return nil, err
+	}
+	if err != nil {
+		log.Error("service: AddTasks failed", zap.Error(err), zap.Int("count", len(tasks)))
+		return nil, err
+	}
+	log.Debug("service: tasks added", zap.Int("count", len(created)))
 	return created, nil
 }
 
-func (s *taskService) CompleteTask(ctx context.Context, id int64) (model.Task, error) {
-	s.logger.Info("service: completing task", zap.Int64("id", id))
+// checkQuota enforces MaxOpenTasksPerOwner for bringing back n open tasks
+// by a restore or undelete. Unlike the check in Create it is a separate
+// read, so it is advisory under concurrent writes by the same owner.
+func (s *taskService) checkQuota(ctx context.Context, owner string, n int64) error {
+	if s.cfg.MaxOpenTasksPerOwner <= 0 || n == 0 {
+		return nil
+	}
+	open, err := s.repo.CountOpen(ctx, owner)
+	if err != nil {
+		return err
+	}
+	if open+n > s.cfg.MaxOpenTasksPerOwner {
+		return fmt.Errorf("%w: %d of %d", ErrQuotaExceeded, open, s.cfg.MaxOpenTasksPerOwner)
+	}
+	return nil
+}
+
+// CompleteTask marks a task completed. Completing an open recurring task also
+// creates its next occurrence, in the same transaction, and returns it as
+// next; otherwise next is the zero Task. Completing a task twice creates no
+// second occurrence.
+func (s *taskService) CompleteTask(ctx context.Context, id int64) (done, next model.Task, err error) {
+	log := hlog.FromContext(ctx, s.logger)
+	log.Debug("service: completing task", zap.Int64("id", id))
+	// read from the primary: the update below writes back every column
+	ctx = storage.WithPrimary(ctx)
 	t, err := s.repo.FindByID(ctx, id)
 	if err != nil {
-		s.logger.Error("service: FindByID failed", zap.Error(err), zap.Int64("id", id))
-		return model.Task{}, err
+		log.Error("service: FindByID failed", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, model.Task{}, err
 	}
+	if err := s.checkBlockers(ctx, id); err != nil {
+		log.Warn("service: CompleteTask rejected", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, model.Task{}, err
+	}
+	recurs := !t.Completed && t.Recurrence != ""
 	t.Completed = true
-	updated, err := s.repo.Update(ctx, t)
+	ctx = repository.WithAction(ctx, model.ActionComplete, "")
+	if recurs {
+		done, next, err = s.completeOccurrence(ctx, t)
+	} else {
+		done, err = s.repo.Update(ctx, t)
+	}
+	if err != nil {
+		log.Error("service: CompleteTask failed", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, model.Task{}, err
+	}
+	log.Debug("service: task completed", zap.Int64("id", done.ID))
+	if s.cfg.AutoCompleteParents && done.ParentID != 0 {
+		if err := s.completeParents(ctx, done.ParentID); err != nil {
+			// the task itself is completed; a parent left open is not fatal
+			log.Warn("service: auto-completing parents failed", zap.Error(err), zap.Int64("id", id))
+		}
+	}
+	return done, next, nil
+}
+
+// completeOccurrence saves a completed recurring task together with the next
+// occurrence, if its rule has one left. next is zero when a concurrent call
+// completed t first.
+func (s *taskService) completeOccurrence(ctx context.Context, t model.Task) (done, next model.Task, err error) {
+	next, ok, err := nextOccurrence(t, time.Now())
+	if err != nil {
+		return model.Task{}, model.Task{}, err
+	}
+	if !ok {
+		done, err = s.repo.Update(ctx, t)
+		return done, model.Task{}, err
+	}
+	done, next, err = s.repo.UpdateAndCreate(ctx, t, next)
+	if err == nil && next.ID != 0 {
+		hlog.FromContext(ctx, s.logger).Debug("service: next occurrence created",
+			zap.Int64("id", next.ID), zap.Time("due_at", next.DueAt))
+	}
+	return done, next, err
+}
+
+// UpdateTask replaces the title and description of an existing task; its
+// other fields are left as they are, even if written concurrently.
+func (s *taskService) UpdateTask(ctx context.Context, task model.Task) (model.Task, error) {
+	log := hlog.FromContext(ctx, s.logger)
+	log.Debug("service: updating task", zap.Int64("id", task.ID))
+	if strings.TrimSpace(task.Title) == "" {
+		return model.Task{}, fmt.Errorf("%w: title is required", ErrInvalidTask)
+	}
+	updated, err := s.repo.SetText(ctx, task.ID, task.Title, task.Description)
 	if err != nil {
-		s.logger.Error("service: CompleteTask failed", zap.Error(err), zap.Int64("id", id))
+		log.Error("service: UpdateTask failed", zap.Error(err), zap.Int64("id", task.ID))
 		return model.Task{}, err
 	}
-	s.logger.Info("service: task completed", zap.Int64("id", updated.ID))
+	log.Debug("service: task updated", zap.Int64("id", updated.ID))
 	return updated, nil
 }
 
-func (s *taskService) ListTasks(ctx context.Context) ([]model.Task, error) {
-	s.logger.Info("service: listing tasks")
-	list, err := s.repo.FindAll(ctx)
+func (s *taskService) ListTasks(ctx context.Context, filter model.TaskFilter) ([]model.Task, error) {
+	log := hlog.FromContext(ctx, s.logger)
+	log.Debug("service: listing tasks", zap.Any("filter", filter))
+	list, err := s.repo.FindAll(ctx, filter)
 	if err != nil {
-		s.logger.Error("service: ListTasks failed", zap.Error(err))
+		log.Error("service: ListTasks failed", zap.Error(err))
 	}
 	return list, err
 }
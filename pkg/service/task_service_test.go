@@ -4,6 +4,7 @@ package service_test
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -11,7 +12,9 @@ import (
 	"github.com/golang/mock/gomock"
 	"go.uber.org/zap"
 
+	"hearx/pkg/auth"
 	"hearx/pkg/model"
+	"hearx/pkg/repository"
 	mockrepo "hearx/pkg/repository/mock_repository"
 	svc "hearx/pkg/service"
 )
@@ -28,7 +31,7 @@ var _ = Describe("taskService", func() {
 		ctrl = gomock.NewController(GinkgoT())
 		repoMock = mockrepo.NewMockTaskRepository(ctrl)
 		logger = zap.NewNop()
-		service = svc.NewTaskService(repoMock, logger)
+		service = svc.NewTaskService(repoMock, mockrepo.NewMockProjectRepository(ctrl), mockrepo.NewMockUserRepository(ctrl), svc.Config{}, logger)
 	})
 
 	AfterEach(func() { ctrl.Finish() })
@@ -40,7 +43,7 @@ var _ = Describe("taskService", func() {
 
 			repoMock.
 				EXPECT().
-				Create(gomock.Any(), in).
+				Create(gomock.Any(), in, int64(0)).
 				Return(out, nil)
 
 			result, err := service.AddTask(context.Background(), in)
@@ -53,24 +56,97 @@ var _ = Describe("taskService", func() {
 
 			repoMock.
 				EXPECT().
-				Create(gomock.Any(), in).
+				Create(gomock.Any(), in, int64(0)).
 				Return(model.Task{}, errors.New("boom"))
 
 			_, err := service.AddTask(context.Background(), in)
 			Expect(err).To(MatchError("boom"))
 		})
+
+		It("should record the caller as owner", func() {
+			ctx := auth.WithCaller(context.Background(), "alice")
+			in := model.Task{Title: "T1"}
+			owned := model.Task{Title: "T1", Owner: "alice"}
+
+			repoMock.
+				EXPECT().
+				Create(gomock.Any(), owned, int64(0)).
+				Return(model.Task{ID: 1, Title: "T1", Owner: "alice"}, nil)
+
+			result, err := service.AddTask(ctx, in)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.Owner).To(Equal("alice"))
+		})
+
+		Context("with an open task quota", func() {
+			BeforeEach(func() {
+				service = svc.NewTaskService(repoMock, mockrepo.NewMockProjectRepository(ctrl), mockrepo.NewMockUserRepository(ctrl), svc.Config{MaxOpenTasksPerOwner: 2}, logger)
+			})
+
+			It("should have the repository enforce the quota", func() {
+				ctx := auth.WithCaller(context.Background(), "alice")
+
+				repoMock.EXPECT().Create(gomock.Any(), model.Task{Title: "T", Owner: "alice"}, int64(2)).Return(model.Task{ID: 3}, nil)
+
+				_, err := service.AddTask(ctx, model.Task{Title: "T"})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("should reject the task once the quota is reached", func() {
+				ctx := auth.WithCaller(context.Background(), "alice")
+
+				repoMock.EXPECT().Create(gomock.Any(), gomock.Any(), int64(2)).
+					Return(model.Task{}, fmt.Errorf("%w: 2 of 2", repository.ErrQuotaExceeded))
+
+				_, err := service.AddTask(ctx, model.Task{Title: "T"})
+				Expect(errors.Is(err, svc.ErrQuotaExceeded)).To(BeTrue())
+			})
+		})
+	})
+
+	Describe("AddTasks", func() {
+		It("should create the batch owned by the caller", func() {
+			ctx := auth.WithCaller(context.Background(), "alice")
+			in := []model.Task{{Title: "A"}, {Title: "B", Completed: true}}
+			owned := []model.Task{{Title: "A", Owner: "alice"}, {Title: "B", Completed: true, Owner: "alice"}}
+			out := []model.Task{{ID: 1, Title: "A", Owner: "alice"}, {ID: 2, Title: "B", Completed: true, Owner: "alice"}}
+
+			repoMock.EXPECT().CreateBatch(gomock.Any(), owned, int64(0)).Return(out, nil)
+
+			result, err := service.AddTasks(ctx, in)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(out))
+		})
+
+		It("should reject the whole batch when one title is empty", func() {
+			_, err := service.AddTasks(context.Background(), []model.Task{{Title: "A"}, {Title: ""}})
+			Expect(errors.Is(err, svc.ErrInvalidTask)).To(BeTrue())
+		})
+
+		It("should pass the quota to the repository and reject the batch over it", func() {
+			service = svc.NewTaskService(repoMock, mockrepo.NewMockProjectRepository(ctrl), mockrepo.NewMockUserRepository(ctrl), svc.Config{MaxOpenTasksPerOwner: 3}, logger)
+			ctx := auth.WithCaller(context.Background(), "alice")
+			batch := []model.Task{{Title: "A"}, {Title: "B"}, {Title: "done", Completed: true}}
+
+			repoMock.EXPECT().CreateBatch(gomock.Any(), gomock.Len(3), int64(3)).
+				Return(nil, fmt.Errorf("%w: 2 of 3", repository.ErrQuotaExceeded))
+
+			_, err := service.AddTasks(ctx, batch)
+			Expect(errors.Is(err, svc.ErrQuotaExceeded)).To(BeTrue())
+		})
 	})
 
 	Describe("ListTasks", func() {
 		It("should return list from repo", func() {
 			list := []model.Task{{ID: 1, Title: "A"}}
+			filter := model.TaskFilter{AfterID: 0, Limit: 10}
 
 			repoMock.
 				EXPECT().
-				FindAll(gomock.Any()).
+				FindAll(gomock.Any(), filter).
 				Return(list, nil)
 
-			result, err := service.ListTasks(context.Background())
+			result, err := service.ListTasks(context.Background(), filter)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(result).To(Equal(list))
 		})
@@ -78,10 +154,10 @@ var _ = Describe("taskService", func() {
 		It("should propagate errors", func() {
 			repoMock.
 				EXPECT().
-				FindAll(gomock.Any()).
+				FindAll(gomock.Any(), gomock.Any()).
 				Return(nil, errors.New("fail"))
 
-			_, err := service.ListTasks(context.Background())
+			_, err := service.ListTasks(context.Background(), model.TaskFilter{})
 			Expect(err).To(MatchError("fail"))
 		})
 	})
@@ -94,10 +170,11 @@ var _ = Describe("taskService", func() {
 
 			gomock.InOrder(
 				repoMock.EXPECT().FindByID(gomock.Any(), id).Return(orig, nil),
+				repoMock.EXPECT().CountOpenBlockers(gomock.Any(), id).Return(int64(0), nil),
 				repoMock.EXPECT().Update(gomock.Any(), updated).Return(updated, nil),
 			)
 
-			result, err := service.CompleteTask(context.Background(), id)
+			result, _, err := service.CompleteTask(context.Background(), id)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(result).To(Equal(updated))
 		})
@@ -110,7 +187,7 @@ var _ = Describe("taskService", func() {
 				FindByID(gomock.Any(), id).
 				Return(model.Task{}, errors.New("missing"))
 
-			_, err := service.CompleteTask(context.Background(), id)
+			_, _, err := service.CompleteTask(context.Background(), id)
 			Expect(err).To(MatchError("missing"))
 		})
 
@@ -122,13 +199,56 @@ var _ = Describe("taskService", func() {
 				EXPECT().
 				FindByID(gomock.Any(), id).
 				Return(orig, nil)
+			repoMock.
+				EXPECT().
+				CountOpenBlockers(gomock.Any(), id).
+				Return(int64(0), nil)
 			repoMock.
 				EXPECT().
 				Update(gomock.Any(), gomock.Any()).
 				Return(model.Task{}, errors.New("nope"))
 
-			_, err := service.CompleteTask(context.Background(), id)
+			_, _, err := service.CompleteTask(context.Background(), id)
 			Expect(err).To(MatchError("nope"))
 		})
+
+		It("should refuse while a blocker is open", func() {
+			id := int64(12)
+
+			repoMock.
+				EXPECT().
+				FindByID(gomock.Any(), id).
+				Return(model.Task{ID: id}, nil)
+			repoMock.
+				EXPECT().
+				CountOpenBlockers(gomock.Any(), id).
+				Return(int64(2), nil)
+
+			_, _, err := service.CompleteTask(context.Background(), id)
+			Expect(err).To(MatchError(svc.ErrBlocked))
+		})
+	})
+
+	Describe("UpdateTask", func() {
+		It("should change only title and description, without reading the task first", func() {
+			want := model.Task{ID: 3, Title: "new", Description: "", Completed: true, Owner: "alice"}
+			repoMock.EXPECT().SetText(gomock.Any(), int64(3), "new", "").Return(want, nil)
+
+			result, err := service.UpdateTask(context.Background(), model.Task{ID: 3, Title: "new"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result).To(Equal(want))
+		})
+
+		It("should pass NotFound through", func() {
+			repoMock.EXPECT().SetText(gomock.Any(), int64(9), "new", "").Return(model.Task{}, repository.ErrNotFound)
+
+			_, err := service.UpdateTask(context.Background(), model.Task{ID: 9, Title: "new"})
+			Expect(err).To(MatchError(repository.ErrNotFound))
+		})
+
+		It("should reject an empty title without touching the repository", func() {
+			_, err := service.UpdateTask(context.Background(), model.Task{ID: 3, Title: "  "})
+			Expect(errors.Is(err, svc.ErrInvalidTask)).To(BeTrue())
+		})
 	})
 })
@@ -0,0 +1,48 @@
+package service
+
+import (
+	"context"
+
+	hlog "hearx/pkg/logger"
+	"hearx/pkg/model"
+	"hearx/pkg/storage"
+
+	"go.uber.org/zap"
+)
+
+// ListDeletedTasks lists the trash: tasks that are soft-deleted and not yet
+// purged, with the same paging and filters as ListTasks.
+func (s *taskService) ListDeletedTasks(ctx context.Context, filter model.TaskFilter) ([]model.Task, error) {
+	log := hlog.FromContext(ctx, s.logger)
+	log.Debug("service: listing deleted tasks", zap.Any("filter", filter))
+	filter.Deleted = true
+	list, err := s.repo.FindAll(ctx, filter)
+	if err != nil {
+		log.Error("service: ListDeletedTasks failed", zap.Error(err))
+	}
+	return list, err
+}
+
+// UndeleteTask takes a task out of the trash as it was when it was deleted.
+// It is checked like a restore: a parent, project or assignee that is no
+// longer valid rejects it with ErrInvalidTask.
+func (s *taskService) UndeleteTask(ctx context.Context, id int64) (model.Task, error) {
+	log := hlog.FromContext(ctx, s.logger)
+	log.Debug("service: undeleting task", zap.Int64("id", id))
+	ctx = storage.WithPrimary(ctx)
+	t, err := s.repo.FindDeletedByID(ctx, id)
+	if err != nil {
+		log.Error("service: FindDeletedByID failed", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	if err := s.checkRestore(ctx, t); err != nil {
+		log.Warn("service: UndeleteTask rejected", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	undeleted, err := s.repo.Undelete(ctx, id)
+	if err != nil {
+		log.Error("service: UndeleteTask failed", zap.Error(err), zap.Int64("id", id))
+		return model.Task{}, err
+	}
+	return undeleted, nil
+}
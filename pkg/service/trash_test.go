@@ -0,0 +1,149 @@
+// pkg/service/trash_test.go
+package service_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+
+	"hearx/pkg/model"
+	"hearx/pkg/repository"
+	mockrepo "hearx/pkg/repository/mock_repository"
+	svc "hearx/pkg/service"
+)
+
+var _ = Describe("taskService trash", func() {
+	var (
+		ctrl     *gomock.Controller
+		repoMock *mockrepo.MockTaskRepository
+		service  svc.TaskService
+		ctx      context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		repoMock = mockrepo.NewMockTaskRepository(ctrl)
+		service = svc.NewTaskService(repoMock, mockrepo.NewMockProjectRepository(ctrl), mockrepo.NewMockUserRepository(ctrl), svc.Config{}, zap.NewNop())
+		ctx = context.Background()
+	})
+
+	AfterEach(func() { ctrl.Finish() })
+
+	It("should list only deleted tasks", func() {
+		repoMock.EXPECT().FindAll(ctx, model.TaskFilter{Limit: 10, Deleted: true}).Return([]model.Task{{ID: 3}}, nil)
+
+		list, err := service.ListDeletedTasks(ctx, model.TaskFilter{Limit: 10})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(list).To(HaveLen(1))
+	})
+
+	It("should undelete a task", func() {
+		repoMock.EXPECT().FindDeletedByID(gomock.Any(), int64(3)).Return(model.Task{ID: 3, Title: "a", DeletedAt: time.Now()}, nil)
+		repoMock.EXPECT().FindByID(gomock.Any(), int64(3)).Return(model.Task{}, repository.ErrNotFound)
+		repoMock.EXPECT().FindDependents(gomock.Any(), int64(3)).Return(nil, nil)
+		repoMock.EXPECT().Undelete(gomock.Any(), int64(3)).Return(model.Task{ID: 3, Title: "a"}, nil)
+
+		t, err := service.UndeleteTask(ctx, 3)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(t.DeletedAt).To(BeZero())
+	})
+
+	It("should not undelete a task under a deleted parent", func() {
+		repoMock.EXPECT().FindDeletedByID(gomock.Any(), int64(3)).Return(model.Task{ID: 3, ParentID: 2}, nil)
+		repoMock.EXPECT().FindByID(gomock.Any(), int64(3)).Return(model.Task{}, repository.ErrNotFound)
+		repoMock.EXPECT().FindByID(gomock.Any(), int64(2)).Return(model.Task{}, repository.ErrNotFound)
+
+		_, err := service.UndeleteTask(ctx, 3)
+		Expect(err).To(MatchError(svc.ErrInvalidTask))
+	})
+
+	It("should not undelete a task that would close a dependency loop", func() {
+		// 5 waits for 3, and while 3 was deleted 4 was made to wait for 5;
+		// 3 still waits for 4
+		repoMock.EXPECT().FindDeletedByID(gomock.Any(), int64(3)).Return(model.Task{ID: 3, Completed: true}, nil)
+		repoMock.EXPECT().FindByID(gomock.Any(), int64(3)).Return(model.Task{}, repository.ErrNotFound)
+		repoMock.EXPECT().FindDependents(gomock.Any(), int64(3)).Return([]model.Task{{ID: 5}}, nil)
+		repoMock.EXPECT().FindBlockers(gomock.Any(), int64(3)).Return([]model.Task{{ID: 4}}, nil)
+		repoMock.EXPECT().FindBlockers(gomock.Any(), int64(4)).Return([]model.Task{{ID: 5}}, nil)
+
+		_, err := service.UndeleteTask(ctx, 3)
+		Expect(err).To(MatchError(svc.ErrCycle))
+	})
+
+	It("should return ErrNotFound for a task that is not in the trash", func() {
+		repoMock.EXPECT().FindDeletedByID(gomock.Any(), int64(3)).Return(model.Task{}, repository.ErrNotFound)
+
+		_, err := service.UndeleteTask(ctx, 3)
+		Expect(err).To(MatchError(repository.ErrNotFound))
+	})
+})
+
+var _ = Describe("Purger", func() {
+	var (
+		ctrl     *gomock.Controller
+		repoMock *mockrepo.MockTaskRepository
+		cfg      svc.PurgeConfig
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		repoMock = mockrepo.NewMockTaskRepository(ctrl)
+		cfg = svc.PurgeConfig{Retention: 24 * time.Hour, Interval: time.Hour, BatchSize: 2}
+	})
+
+	AfterEach(func() { ctrl.Finish() })
+
+	It("should purge batch by batch until nothing is left", func() {
+		var cutoff time.Time
+		gomock.InOrder(
+			repoMock.EXPECT().Purge(withAction(model.ActionPurge, "deleted over 24h0m0s ago"), gomock.Any(), 2).DoAndReturn(
+				func(_ context.Context, before time.Time, _ int) (int64, error) {
+					cutoff = before
+					return 2, nil
+				}),
+			repoMock.EXPECT().Purge(gomock.Any(), gomock.Any(), 2).Return(int64(1), nil),
+			repoMock.EXPECT().Purge(gomock.Any(), gomock.Any(), 2).Return(int64(0), nil),
+		)
+
+		n, err := svc.NewPurger(repoMock, cfg, zap.NewNop()).Purge(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(n).To(Equal(int64(3)))
+		Expect(cutoff).To(BeTemporally("~", time.Now().Add(-24*time.Hour), time.Minute))
+	})
+
+	It("should return what it purged before an error", func() {
+		repoMock.EXPECT().Purge(gomock.Any(), gomock.Any(), 2).Return(int64(2), nil)
+		repoMock.EXPECT().Purge(gomock.Any(), gomock.Any(), 2).Return(int64(0), errors.New("boom"))
+
+		n, err := svc.NewPurger(repoMock, cfg, zap.NewNop()).Purge(context.Background())
+		Expect(err).To(MatchError("boom"))
+		Expect(n).To(Equal(int64(2)))
+	})
+
+	It("should purge on start and stop cleanly", func() {
+		purged := make(chan struct{})
+		repoMock.EXPECT().Purge(gomock.Any(), gomock.Any(), 2).DoAndReturn(
+			func(context.Context, time.Time, int) (int64, error) {
+				close(purged)
+				return 0, nil
+			})
+
+		p := svc.NewPurger(repoMock, cfg, zap.NewNop())
+		p.Start()
+		Eventually(purged).Should(BeClosed())
+		p.Stop()
+	})
+
+	It("should not run with no retention", func() {
+		cfg.Retention = 0
+		p := svc.NewPurger(repoMock, cfg, zap.NewNop())
+		p.Start()
+		p.Stop()
+	})
+})
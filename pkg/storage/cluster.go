@@ -0,0 +1,226 @@
+// pkg/storage/cluster.go
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+
+	hlog "hearx/pkg/logger"
+)
+
+// Cluster routes queries between the primary and its read replicas.
+// Writes always go to Primary; Reader hands out a healthy, caught-up replica
+// in round-robin order and falls back to the primary when there is none.
+type Cluster struct {
+	primary  *sql.DB
+	replicas []*replica
+	next     atomic.Uint64
+
+	maxLag   time.Duration
+	interval time.Duration
+	logger   *zap.Logger
+
+	stop chan struct{}
+	done sync.WaitGroup
+}
+
+type replica struct {
+	name    string
+	db      *sql.DB
+	healthy atomic.Bool
+}
+
+type primaryKey struct{}
+
+// WithPrimary marks ctx so that reads made with it are served by the primary.
+// Use it for read-modify-write sequences that must see their own writes.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+func usePrimary(ctx context.Context) bool {
+	v, _ := ctx.Value(primaryKey{}).(bool)
+	return v
+}
+
+// NewCluster opens a pool per replica DSN in cfg and starts a background
+// health check that drops replicas which are unreachable or lag by more than
+// cfg.ReplicaMaxLag.
+func NewCluster(
+	lc fx.Lifecycle,
+	logger *zap.Logger,
+	cfg Config,
+	primary *sql.DB,
+) (*Cluster, error) {
+	c := &Cluster{
+		primary:  primary,
+		maxLag:   cfg.ReplicaMaxLag,
+		interval: cfg.ReplicaCheckInterval,
+		logger:   logger,
+		stop:     make(chan struct{}),
+	}
+
+	for i, dsn := range cfg.ReplicaDSNs {
+		rcfg := cfg
+		rcfg.DSN = dsn
+		db, err := open(rcfg)
+		if err != nil {
+			c.closeReplicas()
+			logger.Error("open replica failed", zap.Error(err), hlog.DSN("dsn", dsn))
+			return nil, err
+		}
+		c.replicas = append(c.replicas, &replica{name: "replica-" + strconv.Itoa(i), db: db})
+	}
+	if len(c.replicas) == 0 {
+		return c, nil
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			logger.Info("MySQL replicas configured", zap.Int("count", len(c.replicas)))
+			c.checkReplicas(ctx)
+			c.done.Add(1)
+			go c.healthLoop()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			close(c.stop)
+			c.done.Wait()
+			return c.closeReplicas()
+		},
+	})
+	return c, nil
+}
+
+// Primary returns the pool for writes and read-after-write queries.
+func (c *Cluster) Primary() *sql.DB {
+	return c.primary
+}
+
+// Reader returns the pool a read-only query should use.
+func (c *Cluster) Reader(ctx context.Context) *sql.DB {
+	if usePrimary(ctx) || len(c.replicas) == 0 {
+		return c.primary
+	}
+	n := uint64(len(c.replicas))
+	start := c.next.Add(1)
+	for i := uint64(0); i < n; i++ {
+		r := c.replicas[(start+i)%n]
+		if r.healthy.Load() {
+			return r.db
+		}
+	}
+	return c.primary
+}
+
+func (c *Cluster) healthLoop() {
+	defer c.done.Done()
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), c.interval)
+			c.checkReplicas(ctx)
+			cancel()
+		}
+	}
+}
+
+func (c *Cluster) checkReplicas(ctx context.Context) {
+	for _, r := range c.replicas {
+		healthy := true
+		lag, err := replicationLag(ctx, r.db)
+		switch {
+		case err != nil:
+			healthy = false
+			c.logger.Warn("replica health check failed", zap.String("replica", r.name), zap.Error(err))
+		case lag < 0 || lag > c.maxLag:
+			healthy = false
+			c.logger.Warn("replica lagging, routing reads to primary",
+				zap.String("replica", r.name), zap.Duration("lag", lag), zap.Duration("max_lag", c.maxLag))
+		}
+		if was := r.healthy.Swap(healthy); was != healthy {
+			c.logger.Info("replica health changed", zap.String("replica", r.name), zap.Bool("healthy", healthy))
+		}
+	}
+}
+
+func (c *Cluster) closeReplicas() error {
+	var first error
+	for _, r := range c.replicas {
+		c.logger.Info("closing MySQL replica", append(poolStats(r.db.Stats()), zap.String("replica", r.name))...)
+		if err := r.db.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// errNotReplicating is returned by replicationLag for a server with no
+// replication configured, which is not a replica of the primary at all.
+var errNotReplicating = errors.New("server is not replicating")
+
+// errParse is MySQL's ER_PARSE_ERROR, which servers before 8.0.22 return for
+// SHOW REPLICA STATUS.
+const errParse = 1064
+
+// replicationLag reads Seconds_Behind_Source (Seconds_Behind_Master before
+// MySQL 8.0.22) from SHOW REPLICA STATUS, or SHOW SLAVE STATUS on servers
+// too old to know the former. A server that is not replicating at all
+// returns errNotReplicating; a stopped replication thread reports -1.
+func replicationLag(ctx context.Context, db *sql.DB) (time.Duration, error) {
+	rows, err := db.QueryContext(ctx, "SHOW REPLICA STATUS")
+	var me *mysql.MySQLError
+	if errors.As(err, &me) && me.Number == errParse {
+		rows, err = db.QueryContext(ctx, "SHOW SLAVE STATUS")
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	cols, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return 0, err
+		}
+		return 0, errNotReplicating
+	}
+	vals := make([]sql.NullString, len(cols))
+	ptrs := make([]any, len(cols))
+	for i := range vals {
+		ptrs[i] = &vals[i]
+	}
+	if err := rows.Scan(ptrs...); err != nil {
+		return 0, err
+	}
+	for i, col := range cols {
+		if col != "Seconds_Behind_Source" && col != "Seconds_Behind_Master" {
+			continue
+		}
+		if !vals[i].Valid {
+			return -1, nil
+		}
+		secs, err := strconv.Atoi(vals[i].String)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(secs) * time.Second, nil
+	}
+	return 0, nil
+}
@@ -0,0 +1,174 @@
+// pkg/storage/cluster_test.go
+package storage_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/go-sql-driver/mysql"
+
+	"hearx/pkg/storage"
+)
+
+// fakeServer answers SHOW REPLICA STATUS with a configurable lag, so the
+// health check can run without MySQL.
+type fakeServer struct {
+	mu      sync.Mutex
+	lag     any // seconds as a string, nil for a stopped replication thread
+	err     error
+	legacy  bool // a server before MySQL 8.0.22, which only knows SHOW SLAVE STATUS
+	primary bool // not replicating: the status has no rows
+}
+
+func (s *fakeServer) set(lag any, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lag, s.err = lag, err
+}
+
+func (s *fakeServer) Connect(context.Context) (driver.Conn, error) { return fakeConn{s}, nil }
+func (s *fakeServer) Driver() driver.Driver                        { return s }
+func (s *fakeServer) Open(string) (driver.Conn, error)             { return fakeConn{s}, nil }
+
+type fakeConn struct{ s *fakeServer }
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (c fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.s.mu.Lock()
+	defer c.s.mu.Unlock()
+	switch {
+	case c.s.err != nil:
+		return nil, c.s.err
+	case c.s.legacy && query == "SHOW REPLICA STATUS":
+		return nil, &mysql.MySQLError{Number: 1064, Message: "You have an error in your SQL syntax"}
+	case c.s.legacy && query == "SHOW SLAVE STATUS":
+		return &fakeRows{cols: []string{"Slave_IO_State", "Seconds_Behind_Master"}, vals: []driver.Value{"Waiting for master to send event", c.s.lag}, done: c.s.primary}, nil
+	case query == "SHOW REPLICA STATUS":
+		return &fakeRows{cols: []string{"Replica_IO_State", "Seconds_Behind_Source"}, vals: []driver.Value{"Waiting for source to send event", c.s.lag}, done: c.s.primary}, nil
+	}
+	return nil, errors.New("unexpected query " + query)
+}
+
+type fakeRows struct {
+	cols []string
+	vals []driver.Value
+	done bool
+}
+
+func (r *fakeRows) Columns() []string { return r.cols }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	copy(dest, r.vals)
+	return nil
+}
+
+var _ = Describe("Cluster", func() {
+	var (
+		primary  *sql.DB
+		servers  []*fakeServer
+		replicas []*sql.DB
+		cluster  *storage.Cluster
+		ctx      = context.Background()
+	)
+
+	BeforeEach(func() {
+		primary = sql.OpenDB(&fakeServer{})
+		servers, replicas = nil, nil
+		for i := 0; i < 3; i++ {
+			s := &fakeServer{lag: "0"}
+			servers = append(servers, s)
+			replicas = append(replicas, sql.OpenDB(s))
+		}
+		cluster = storage.NewTestCluster(primary, 5*time.Second, replicas...)
+	})
+
+	AfterEach(func() {
+		primary.Close()
+		for _, db := range replicas {
+			db.Close()
+		}
+	})
+
+	readersOf := func(n int) []*sql.DB {
+		var out []*sql.DB
+		for i := 0; i < n; i++ {
+			out = append(out, cluster.Reader(ctx))
+		}
+		return out
+	}
+
+	It("should read from the primary until replicas pass a health check", func() {
+		Expect(readersOf(3)).To(HaveEach(primary))
+	})
+
+	It("should spread reads over healthy replicas in turn", func() {
+		cluster.CheckReplicas(ctx)
+		got := readersOf(6)
+		Expect(got[:3]).To(ConsistOf(replicas[0], replicas[1], replicas[2]))
+		Expect(got[3:]).To(Equal(got[:3]))
+	})
+
+	It("should skip replicas that fail the check or lag too far behind", func() {
+		servers[0].set(nil, errors.New("connection refused"))
+		servers[1].set("30", nil)
+		cluster.CheckReplicas(ctx)
+		Expect(readersOf(4)).To(HaveEach(replicas[2]))
+
+		servers[1].set("2", nil)
+		cluster.CheckReplicas(ctx)
+		Expect(readersOf(4)).To(ConsistOf(replicas[1], replicas[2], replicas[1], replicas[2]))
+	})
+
+	It("should fall back to the primary when no replica is usable", func() {
+		servers[0].set(nil, nil) // replication stopped
+		servers[1].set("30", nil)
+		servers[2].set(nil, errors.New("connection refused"))
+		cluster.CheckReplicas(ctx)
+		Expect(readersOf(3)).To(HaveEach(primary))
+	})
+
+	It("should fall back to SHOW SLAVE STATUS on servers before MySQL 8.0.22", func() {
+		for _, s := range servers {
+			s.legacy = true
+		}
+		servers[0].set("30", nil)
+		servers[1].set(nil, nil)
+		cluster.CheckReplicas(ctx)
+		Expect(readersOf(3)).To(HaveEach(replicas[2]))
+	})
+
+	It("should skip a server that is not replicating at all", func() {
+		servers[0].primary = true
+		servers[1].legacy, servers[1].primary = true, true
+		cluster.CheckReplicas(ctx)
+		Expect(readersOf(3)).To(HaveEach(replicas[2]))
+	})
+
+	It("should read from the primary when asked to", func() {
+		cluster.CheckReplicas(ctx)
+		Expect(cluster.Reader(storage.WithPrimary(ctx))).To(BeIdenticalTo(primary))
+		Expect(cluster.Primary()).To(BeIdenticalTo(primary))
+	})
+
+	It("should use the primary when no replicas are configured", func() {
+		single := storage.NewTestCluster(primary, time.Second)
+		single.CheckReplicas(ctx)
+		Expect(single.Reader(ctx)).To(BeIdenticalTo(primary))
+	})
+})
@@ -0,0 +1,132 @@
+// pkg/storage/config.go
+package storage
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Config controls how the MySQL pool is opened, sized and health-checked.
+type Config struct {
+	DSN string
+
+	// Pool sizing; zero leaves the database/sql default in place.
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+
+	// Startup retry: Ping is retried with exponential backoff and jitter,
+	// starting at RetryInitial and capped at RetryMax, for up to ConnectTimeout.
+	ConnectTimeout time.Duration
+	RetryInitial   time.Duration
+	RetryMax       time.Duration
+
+	// QueryTimeout bounds every individual query; zero disables it.
+	QueryTimeout time.Duration
+
+	TLS TLSConfig
+
+	// Read replicas share the pool and TLS settings above. A replica is only
+	// used while it answers health checks and lags by at most ReplicaMaxLag.
+	ReplicaDSNs          []string
+	ReplicaMaxLag        time.Duration
+	ReplicaCheckInterval time.Duration
+}
+
+// DefaultConfig returns the settings used when nothing is configured.
+func DefaultConfig() Config {
+	return Config{
+		MaxOpenConns:    25,
+		MaxIdleConns:    25,
+		ConnMaxLifetime: 5 * time.Minute,
+		ConnMaxIdleTime: time.Minute,
+		ConnectTimeout:  time.Minute,
+		RetryInitial:    500 * time.Millisecond,
+		RetryMax:        5 * time.Second,
+		QueryTimeout:    10 * time.Second,
+
+		ReplicaMaxLag:        5 * time.Second,
+		ReplicaCheckInterval: 5 * time.Second,
+	}
+}
+
+// Validate rejects settings that would break the pool at runtime.
+func (c Config) Validate() error {
+	if c.RetryInitial <= 0 {
+		return fmt.Errorf("initial retry backoff must be positive, got %s", c.RetryInitial)
+	}
+	if c.RetryMax < c.RetryInitial {
+		return fmt.Errorf("maximum retry backoff %s is below the initial %s", c.RetryMax, c.RetryInitial)
+	}
+	if c.ReplicaCheckInterval <= 0 {
+		return fmt.Errorf("replica check interval must be positive, got %s", c.ReplicaCheckInterval)
+	}
+	return nil
+}
+
+// TLS modes accepted by TLSConfig.Mode.
+const (
+	TLSDisabled   = "false"
+	TLSRequired   = "true"
+	TLSSkipVerify = "skip-verify"
+	TLSPreferred  = "preferred"
+)
+
+// TLSConfig describes the TLS connection to MySQL.
+type TLSConfig struct {
+	Mode       string
+	CAFile     string
+	CertFile   string
+	KeyFile    string
+	ServerName string
+}
+
+// build turns the settings into a *tls.Config. It returns nil when TLS is
+// disabled; the bool reports whether plaintext fallback is allowed. The
+// preferred mode skips verification unless a CA is given, in which case
+// the server must present a certificate it signed, as in the required mode.
+func (c TLSConfig) build() (*tls.Config, bool, error) {
+	var cfg *tls.Config
+	fallback := false
+	switch c.Mode {
+	case "", TLSDisabled:
+		return nil, false, nil
+	case TLSRequired:
+		cfg = &tls.Config{MinVersion: tls.VersionTLS12}
+	case TLSSkipVerify:
+		cfg = &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: true}
+	case TLSPreferred:
+		cfg = &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: true}
+		fallback = true
+	default:
+		return nil, false, fmt.Errorf("unknown MySQL TLS mode %q", c.Mode)
+	}
+
+	cfg.ServerName = c.ServerName
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, false, fmt.Errorf("read MySQL CA: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, false, fmt.Errorf("no certificates found in %s", c.CAFile)
+		}
+		cfg.RootCAs = pool
+		if c.Mode == TLSPreferred {
+			cfg.InsecureSkipVerify = false
+		}
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, false, fmt.Errorf("load MySQL client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, fallback, nil
+}
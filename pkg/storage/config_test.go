@@ -0,0 +1,173 @@
+// pkg/storage/config_test.go
+package storage_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"hearx/pkg/storage"
+)
+
+var _ = Describe("Config", func() {
+	It("should default to a bounded pool, retries and replica checks", func() {
+		cfg := storage.DefaultConfig()
+		Expect(cfg.DSN).To(BeEmpty())
+		Expect(cfg.MaxOpenConns).To(Equal(25))
+		Expect(cfg.MaxIdleConns).To(Equal(25))
+		Expect(cfg.ConnMaxLifetime).To(Equal(5 * time.Minute))
+		Expect(cfg.ConnMaxIdleTime).To(Equal(time.Minute))
+		Expect(cfg.ConnectTimeout).To(Equal(time.Minute))
+		Expect(cfg.RetryInitial).To(Equal(500 * time.Millisecond))
+		Expect(cfg.RetryMax).To(Equal(5 * time.Second))
+		Expect(cfg.QueryTimeout).To(Equal(10 * time.Second))
+		Expect(cfg.TLS).To(Equal(storage.TLSConfig{}))
+		Expect(cfg.ReplicaDSNs).To(BeEmpty())
+		Expect(cfg.ReplicaMaxLag).To(Equal(5 * time.Second))
+		Expect(cfg.ReplicaCheckInterval).To(Equal(5 * time.Second))
+		Expect(cfg.Validate()).To(Succeed())
+	})
+
+	It("should reject a non-positive initial retry backoff", func() {
+		for _, d := range []time.Duration{0, -time.Second} {
+			cfg := storage.DefaultConfig()
+			cfg.RetryInitial = d
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("initial retry backoff")))
+		}
+	})
+
+	It("should reject a maximum retry backoff below the initial one", func() {
+		cfg := storage.DefaultConfig()
+		cfg.RetryInitial = time.Second
+		cfg.RetryMax = 500 * time.Millisecond
+		Expect(cfg.Validate()).To(MatchError(ContainSubstring("maximum retry backoff")))
+
+		cfg.RetryMax = time.Second
+		Expect(cfg.Validate()).To(Succeed())
+	})
+
+	It("should reject a non-positive replica check interval", func() {
+		for _, d := range []time.Duration{0, -time.Second} {
+			cfg := storage.DefaultConfig()
+			cfg.ReplicaCheckInterval = d
+			Expect(cfg.Validate()).To(MatchError(ContainSubstring("replica check interval")))
+		}
+	})
+})
+
+var _ = Describe("TLSConfig", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "storage-tls")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() { os.RemoveAll(dir) })
+
+	// writeCert writes a self-signed certificate and its key as PEM files.
+	writeCert := func() (certFile, keyFile string) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).NotTo(HaveOccurred())
+		tmpl := &x509.Certificate{
+			SerialNumber:          big.NewInt(1),
+			Subject:               pkix.Name{CommonName: "mysql"},
+			NotBefore:             time.Now().Add(-time.Hour),
+			NotAfter:              time.Now().Add(time.Hour),
+			IsCA:                  true,
+			BasicConstraintsValid: true,
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+		Expect(err).NotTo(HaveOccurred())
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		Expect(err).NotTo(HaveOccurred())
+
+		certFile = filepath.Join(dir, "cert.pem")
+		keyFile = filepath.Join(dir, "key.pem")
+		Expect(os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)).To(Succeed())
+		Expect(os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)).To(Succeed())
+		return certFile, keyFile
+	}
+
+	It("should leave TLS off by default", func() {
+		for _, mode := range []string{"", storage.TLSDisabled} {
+			cfg, fallback, err := storage.TLSConfig{Mode: mode}.Build()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg).To(BeNil())
+			Expect(fallback).To(BeFalse())
+		}
+	})
+
+	It("should verify the server when TLS is required", func() {
+		cfg, fallback, err := storage.TLSConfig{Mode: storage.TLSRequired, ServerName: "db.internal"}.Build()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.InsecureSkipVerify).To(BeFalse())
+		Expect(cfg.MinVersion).To(Equal(uint16(tls.VersionTLS12)))
+		Expect(cfg.ServerName).To(Equal("db.internal"))
+		Expect(fallback).To(BeFalse())
+	})
+
+	It("should skip verification only when asked to", func() {
+		cfg, fallback, err := storage.TLSConfig{Mode: storage.TLSSkipVerify}.Build()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.InsecureSkipVerify).To(BeTrue())
+		Expect(fallback).To(BeFalse())
+	})
+
+	It("should allow plaintext fallback when TLS is preferred", func() {
+		cfg, fallback, err := storage.TLSConfig{Mode: storage.TLSPreferred}.Build()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg).NotTo(BeNil())
+		Expect(fallback).To(BeTrue())
+	})
+
+	It("should verify against the CA when TLS is preferred and a CA is given", func() {
+		certFile, _ := writeCert()
+		cfg, fallback, err := storage.TLSConfig{Mode: storage.TLSPreferred, CAFile: certFile}.Build()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.RootCAs).NotTo(BeNil())
+		Expect(cfg.InsecureSkipVerify).To(BeFalse())
+		Expect(fallback).To(BeTrue())
+	})
+
+	It("should reject an unknown mode", func() {
+		_, _, err := storage.TLSConfig{Mode: "yes"}.Build()
+		Expect(err).To(MatchError(ContainSubstring(`unknown MySQL TLS mode "yes"`)))
+	})
+
+	It("should load a CA and a client certificate", func() {
+		certFile, keyFile := writeCert()
+		cfg, _, err := storage.TLSConfig{Mode: storage.TLSRequired, CAFile: certFile, CertFile: certFile, KeyFile: keyFile}.Build()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.RootCAs).NotTo(BeNil())
+		Expect(cfg.Certificates).To(HaveLen(1))
+	})
+
+	It("should fail on a CA file that is missing or holds no certificate", func() {
+		_, _, err := storage.TLSConfig{Mode: storage.TLSRequired, CAFile: filepath.Join(dir, "missing.pem")}.Build()
+		Expect(err).To(MatchError(ContainSubstring("read MySQL CA")))
+
+		empty := filepath.Join(dir, "empty.pem")
+		Expect(os.WriteFile(empty, []byte("not a certificate"), 0o600)).To(Succeed())
+		_, _, err = storage.TLSConfig{Mode: storage.TLSRequired, CAFile: empty}.Build()
+		Expect(err).To(MatchError(ContainSubstring("no certificates found")))
+	})
+
+	It("should fail on a client certificate without its key", func() {
+		certFile, _ := writeCert()
+		_, _, err := storage.TLSConfig{Mode: storage.TLSRequired, CertFile: certFile}.Build()
+		Expect(err).To(MatchError(ContainSubstring("load MySQL client certificate")))
+	})
+})
@@ -0,0 +1,30 @@
+// pkg/storage/export_test.go
+package storage
+
+import (
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// NewTestCluster builds a Cluster over pools the test already opened. No
+// health check runs until CheckReplicas is called.
+func NewTestCluster(primary *sql.DB, maxLag time.Duration, replicas ...*sql.DB) *Cluster {
+	c := &Cluster{primary: primary, maxLag: maxLag, logger: zap.NewNop()}
+	for _, db := range replicas {
+		c.replicas = append(c.replicas, &replica{name: "replica", db: db})
+	}
+	return c
+}
+
+func (c *Cluster) CheckReplicas(ctx context.Context) { c.checkReplicas(ctx) }
+
+var (
+	Backoff = backoff
+	Jitter  = jitter
+)
+
+func (c TLSConfig) Build() (*tls.Config, bool, error) { return c.build() }
@@ -5,59 +5,131 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"math/rand/v2"
 	"time"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 	"go.uber.org/fx"
 	"go.uber.org/zap"
+
+	hlog "hearx/pkg/logger"
 )
 
-// NewMySQLConn provides an *sql.DB connected to MySQL,
-// retrying Ping() up to 1 minute before erroring out.
+// NewMySQLConn provides an *sql.DB connected to MySQL, sized and secured per
+// cfg, retrying Ping() with backoff for up to cfg.ConnectTimeout.
 func NewMySQLConn(
 	lc fx.Lifecycle,
 	logger *zap.Logger,
-	dsn string,
+	cfg Config,
 ) (*sql.DB, error) {
-	logger.Info("connecting to MySQL", zap.String("dsn", dsn))
+	logger.Info("connecting to MySQL", hlog.DSN("dsn", cfg.DSN), zap.String("tls", cfg.TLS.Mode))
 
-	db, err := sql.Open("mysql", dsn)
+	db, err := open(cfg)
 	if err != nil {
 		logger.Error("open failed", zap.Error(err))
 		return nil, err
 	}
 
-	// actively wait up to 60s for the server to be reachable
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
-	defer cancel()
+	if err := waitForPing(db, cfg, logger); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	// Register shutdown hook
+	lc.Append(fx.Hook{
+		OnStop: func(context.Context) error {
+			logger.Info("closing MySQL connection", poolStats(db.Stats())...)
+			return db.Close()
+		},
+	})
+
+	return db, nil
+}
+
+// open builds a connector from the DSN plus the TLS settings and applies
+// the pool limits.
+func open(cfg Config) (*sql.DB, error) {
+	mcfg, err := mysql.ParseDSN(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+	tlsCfg, fallback, err := cfg.TLS.build()
+	if err != nil {
+		return nil, err
+	}
+	if tlsCfg != nil {
+		mcfg.TLS = tlsCfg
+		mcfg.AllowFallbackToPlaintext = fallback
+	}
 
-	ticker := time.NewTicker(5 * time.Second)
-	defer ticker.Stop()
+	connector, err := mysql.NewConnector(mcfg)
+	if err != nil {
+		return nil, err
+	}
+	db := sql.OpenDB(connector)
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	return db, nil
+}
 
-	for {
-		if err := db.PingContext(ctx); err == nil {
-			logger.Info("MySQL reachable")
-			break
-		} else {
-			logger.Warn("MySQL not yet reachable, retrying...", zap.Error(err))
+// waitForPing actively waits for the server to be reachable, backing off
+// exponentially with jitter between attempts.
+func waitForPing(db *sql.DB, cfg Config, logger *zap.Logger) error {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ConnectTimeout)
+	defer cancel()
+
+	delay := cfg.RetryInitial
+	for attempt := 1; ; attempt++ {
+		err := db.PingContext(ctx)
+		if err == nil {
+			logger.Info("MySQL reachable", zap.Int("attempt", attempt))
+			return nil
 		}
+		logger.Warn("MySQL not yet reachable, retrying...", zap.Error(err), zap.Int("attempt", attempt))
 
+		timer := time.NewTimer(jitter(delay))
 		select {
 		case <-ctx.Done():
+			timer.Stop()
 			logger.Error("timeout waiting for MySQL", zap.Error(ctx.Err()))
-			return nil, fmt.Errorf("could not connect to MySQL within 1m: %w", ctx.Err())
-		case <-ticker.C:
+			return fmt.Errorf("could not connect to MySQL within %s: %w", cfg.ConnectTimeout, ctx.Err())
+		case <-timer.C:
 			// retry
 		}
+
+		delay = backoff(delay, cfg.RetryMax)
 	}
+}
 
-	// Register shutdown hook
-	lc.Append(fx.Hook{
-		OnStop: func(context.Context) error {
-			logger.Info("closing MySQL connection")
-			return db.Close()
-		},
-	})
+// backoff doubles the delay before the next attempt, up to max.
+func backoff(delay, max time.Duration) time.Duration {
+	if delay > max/2 {
+		return max
+	}
+	return delay * 2
+}
 
-	return db, nil
+// jitter picks a random delay in [d/2, d] so restarting replicas don't
+// hammer MySQL in lock-step.
+func jitter(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+	half := d / 2
+	return half + rand.N(half+1)
+}
+
+func poolStats(s sql.DBStats) []zap.Field {
+	return []zap.Field{
+		zap.Int("open_connections", s.OpenConnections),
+		zap.Int("in_use", s.InUse),
+		zap.Int("idle", s.Idle),
+		zap.Int64("wait_count", s.WaitCount),
+		zap.Duration("wait_duration", s.WaitDuration),
+		zap.Int64("max_idle_closed", s.MaxIdleClosed),
+		zap.Int64("max_idle_time_closed", s.MaxIdleTimeClosed),
+		zap.Int64("max_lifetime_closed", s.MaxLifetimeClosed),
+	}
 }
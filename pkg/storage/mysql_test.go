@@ -0,0 +1,39 @@
+// pkg/storage/mysql_test.go
+package storage_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"hearx/pkg/storage"
+)
+
+var _ = Describe("startup retry backoff", func() {
+	It("should double the delay up to the maximum", func() {
+		delay := 500 * time.Millisecond
+		var got []time.Duration
+		for i := 0; i < 6; i++ {
+			delay = storage.Backoff(delay, 5*time.Second)
+			got = append(got, delay)
+		}
+		Expect(got).To(Equal([]time.Duration{
+			time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second, 5 * time.Second,
+		}))
+	})
+
+	It("should not overflow on a huge maximum", func() {
+		max := time.Duration(1<<63 - 1)
+		Expect(storage.Backoff(max/2+1, max)).To(Equal(max))
+	})
+
+	It("should jitter between half and all of the delay", func() {
+		for i := 0; i < 100; i++ {
+			d := storage.Jitter(time.Second)
+			Expect(d).To(BeNumerically(">=", 500*time.Millisecond))
+			Expect(d).To(BeNumerically("<=", time.Second))
+		}
+		Expect(storage.Jitter(1)).To(Equal(time.Duration(1)))
+	})
+})
@@ -0,0 +1,89 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"hearx/pkg/model"
+	"hearx/pkg/service"
+	pb "hearx/proto"
+)
+
+// AdminServer implements the gRPC AdminService.
+type AdminServer struct {
+	pb.UnimplementedAdminServiceServer
+	audit   service.AuditService
+	archive service.ArchiveService
+}
+
+// NewAdminServer constructs an AdminServer with the given business‐logic services.
+func NewAdminServer(audit service.AuditService, archive service.ArchiveService) *AdminServer {
+	return &AdminServer{audit: audit, archive: archive}
+}
+
+// ListAuditEvents retrieves one page of the audit trail.
+func (s *AdminServer) ListAuditEvents(ctx context.Context, req *pb.ListAuditEventsRequest) (*pb.ListAuditEventsResponse, error) {
+	afterID, err := decodePageToken(req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	size := pageSize(req.PageSize)
+
+	// ask for one extra row to learn whether another page follows
+	list, err := s.audit.ListAuditEvents(ctx, model.AuditFilter{
+		AfterID: afterID,
+		Limit:   size + 1,
+		Actor:   req.Actor,
+		Action:  req.Action,
+		TaskID:  req.TaskId,
+		Since:   fromTimestamp(req.Since),
+		Until:   fromTimestamp(req.Until),
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &pb.ListAuditEventsResponse{}
+	if len(list) > size {
+		list = list[:size]
+		resp.NextPageToken = encodePageToken(list[size-1].ID)
+	}
+	for _, e := range list {
+		resp.Events = append(resp.Events, auditEventToProto(e))
+	}
+	return resp, nil
+}
+
+// ArchiveTasks archives the tasks completed longer ago than older_than.
+func (s *AdminServer) ArchiveTasks(ctx context.Context, req *pb.ArchiveTasksRequest) (*pb.ArchiveTasksResponse, error) {
+	if req.OlderThan == nil || req.OlderThan.AsDuration() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "older_than must be positive")
+	}
+	n, err := s.archive.ArchiveTasks(ctx, req.OlderThan.AsDuration())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.ArchiveTasksResponse{Archived: n}, nil
+}
+
+// auditEventToProto maps an audit event onto its wire representation.
+func auditEventToProto(e model.AuditEvent) *pb.AuditEvent {
+	out := &pb.AuditEvent{
+		Id:         e.ID,
+		OccurredAt: timestamp(e.OccurredAt),
+		Actor:      e.Actor,
+		Action:     e.Action,
+		TaskId:     e.TaskID,
+		RequestId:  e.RequestID,
+		Detail:     e.Detail,
+	}
+	if e.Before != nil {
+		out.Before = toProto(*e.Before)
+	}
+	if e.After != nil {
+		out.After = toProto(*e.After)
+	}
+	return out
+}
@@ -0,0 +1,117 @@
+// pkg/transport/grpc/admin_server_test.go
+package grpc_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"hearx/pkg/model"
+	"hearx/pkg/service"
+	mocksvc "hearx/pkg/service/mock_service"
+	grpcTransport "hearx/pkg/transport/grpc"
+	pb "hearx/proto"
+)
+
+var _ = Describe("AdminServer (gRPC)", func() {
+	var (
+		ctrl        *gomock.Controller
+		auditMock   *mocksvc.MockAuditService
+		archiveMock *mocksvc.MockArchiveService
+		server      *grpcTransport.AdminServer
+		ctx         context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		auditMock = mocksvc.NewMockAuditService(ctrl)
+		archiveMock = mocksvc.NewMockArchiveService(ctrl)
+		server = grpcTransport.NewAdminServer(auditMock, archiveMock)
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("ListAuditEvents", func() {
+		It("should pass the filters and map both snapshots", func() {
+			since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+			auditMock.
+				EXPECT().
+				ListAuditEvents(ctx, model.AuditFilter{Limit: 101, Actor: "alice", TaskID: 3, Since: since}).
+				Return([]model.AuditEvent{{
+					ID: 1, Actor: "alice", Action: model.ActionUpdate, TaskID: 3,
+					Before: &model.Task{ID: 3, Title: "Old"}, After: &model.Task{ID: 3, Title: "New"},
+				}}, nil)
+
+			resp, err := server.ListAuditEvents(ctx, &pb.ListAuditEventsRequest{
+				Actor: "alice", TaskId: 3, Since: timestamppb.New(since),
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Events).To(HaveLen(1))
+			Expect(resp.Events[0].Before.Title).To(Equal("Old"))
+			Expect(resp.Events[0].After.Title).To(Equal("New"))
+			Expect(resp.NextPageToken).To(BeEmpty())
+		})
+
+		It("should leave the before state of an added task unset", func() {
+			auditMock.
+				EXPECT().
+				ListAuditEvents(ctx, gomock.Any()).
+				Return([]model.AuditEvent{{ID: 1, Action: model.ActionAdd, After: &model.Task{ID: 1}}}, nil)
+
+			resp, err := server.ListAuditEvents(ctx, &pb.ListAuditEventsRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Events[0].Before).To(BeNil())
+		})
+
+		It("should map a caller without the admin role to PermissionDenied", func() {
+			auditMock.
+				EXPECT().
+				ListAuditEvents(ctx, gomock.Any()).
+				Return(nil, service.ErrNotAdmin)
+
+			_, err := server.ListAuditEvents(ctx, &pb.ListAuditEventsRequest{})
+			Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+		})
+	})
+
+	Describe("ArchiveTasks", func() {
+		It("should pass the threshold and return the number archived", func() {
+			archiveMock.
+				EXPECT().
+				ArchiveTasks(ctx, 720*time.Hour).
+				Return(int64(4), nil)
+
+			resp, err := server.ArchiveTasks(ctx, &pb.ArchiveTasksRequest{OlderThan: durationpb.New(720 * time.Hour)})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Archived).To(Equal(int64(4)))
+		})
+
+		It("should reject a missing or non-positive threshold", func() {
+			_, err := server.ArchiveTasks(ctx, &pb.ArchiveTasksRequest{})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+
+			_, err = server.ArchiveTasks(ctx, &pb.ArchiveTasksRequest{OlderThan: durationpb.New(-time.Hour)})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+
+		It("should map a caller without the admin role to PermissionDenied", func() {
+			archiveMock.
+				EXPECT().
+				ArchiveTasks(ctx, time.Hour).
+				Return(int64(0), service.ErrNotAdmin)
+
+			_, err := server.ArchiveTasks(ctx, &pb.ArchiveTasksRequest{OlderThan: durationpb.New(time.Hour)})
+			Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+		})
+	})
+})
@@ -0,0 +1,82 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"hearx/pkg/model"
+	"hearx/pkg/service"
+	pb "hearx/proto"
+)
+
+// CommentServer implements the gRPC CommentService.
+type CommentServer struct {
+	pb.UnimplementedCommentServiceServer
+	svc service.CommentService
+}
+
+// NewCommentServer constructs a CommentServer with the given business‐logic service.
+func NewCommentServer(svc service.CommentService) *CommentServer {
+	return &CommentServer{svc: svc}
+}
+
+// AddComment comments on a task as the caller.
+func (s *CommentServer) AddComment(ctx context.Context, req *pb.AddCommentRequest) (*pb.AddCommentResponse, error) {
+	if req.TaskId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "task_id is required")
+	}
+	created, err := s.svc.AddComment(ctx, req.TaskId, req.Body)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.AddCommentResponse{Comment: commentToProto(created)}, nil
+}
+
+// ListComments retrieves one page of a task's comments.
+func (s *CommentServer) ListComments(ctx context.Context, req *pb.ListCommentsRequest) (*pb.ListCommentsResponse, error) {
+	if req.TaskId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "task_id is required")
+	}
+	afterID, err := decodePageToken(req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	size := pageSize(req.PageSize)
+
+	// ask for one extra row to learn whether another page follows
+	list, err := s.svc.ListComments(ctx, req.TaskId, afterID, size+1)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &pb.ListCommentsResponse{}
+	if len(list) > size {
+		list = list[:size]
+		resp.NextPageToken = encodePageToken(list[size-1].ID)
+	}
+	for _, c := range list {
+		resp.Comments = append(resp.Comments, commentToProto(c))
+	}
+	return resp, nil
+}
+
+// DeleteComment removes one of the caller's comments.
+func (s *CommentServer) DeleteComment(ctx context.Context, req *pb.DeleteCommentRequest) (*pb.DeleteCommentResponse, error) {
+	if err := s.svc.DeleteComment(ctx, req.Id); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.DeleteCommentResponse{}, nil
+}
+
+// commentToProto maps an internal comment onto its wire representation.
+func commentToProto(c model.Comment) *pb.Comment {
+	return &pb.Comment{
+		Id:        c.ID,
+		TaskId:    c.TaskID,
+		Author:    c.Author,
+		Body:      c.Body,
+		CreatedAt: timestamp(c.CreatedAt),
+	}
+}
@@ -0,0 +1,88 @@
+// pkg/transport/grpc/comment_server_test.go
+package grpc_test
+
+import (
+	"context"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"hearx/pkg/model"
+	"hearx/pkg/repository"
+	"hearx/pkg/service"
+	mocksvc "hearx/pkg/service/mock_service"
+	grpcTransport "hearx/pkg/transport/grpc"
+	pb "hearx/proto"
+)
+
+var _ = Describe("CommentServer (gRPC)", func() {
+	var (
+		ctrl    *gomock.Controller
+		svcMock *mocksvc.MockCommentService
+		server  *grpcTransport.CommentServer
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		svcMock = mocksvc.NewMockCommentService(ctrl)
+		server = grpcTransport.NewCommentServer(svcMock)
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("AddComment", func() {
+		It("should require a task", func() {
+			_, err := server.AddComment(ctx, &pb.AddCommentRequest{Body: "hi"})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+
+		It("should map an invalid comment to InvalidArgument", func() {
+			svcMock.EXPECT().AddComment(ctx, int64(1), "").Return(model.Comment{}, service.ErrInvalidComment)
+
+			_, err := server.AddComment(ctx, &pb.AddCommentRequest{TaskId: 1})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+	})
+
+	Describe("ListComments", func() {
+		It("should return a next page token when more comments follow", func() {
+			svcMock.EXPECT().ListComments(ctx, int64(1), int64(0), 3).
+				Return([]model.Comment{{ID: 1}, {ID: 2}, {ID: 3}}, nil)
+
+			resp, err := server.ListComments(ctx, &pb.ListCommentsRequest{TaskId: 1, PageSize: 2})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Comments).To(HaveLen(2))
+			Expect(resp.NextPageToken).NotTo(BeEmpty())
+
+			svcMock.EXPECT().ListComments(ctx, int64(1), int64(2), 3).Return([]model.Comment{{ID: 3}}, nil)
+
+			resp, err = server.ListComments(ctx, &pb.ListCommentsRequest{TaskId: 1, PageSize: 2, PageToken: resp.NextPageToken})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Comments).To(HaveLen(1))
+			Expect(resp.NextPageToken).To(BeEmpty())
+		})
+	})
+
+	Describe("DeleteComment", func() {
+		It("should map a stranger's delete to PermissionDenied", func() {
+			svcMock.EXPECT().DeleteComment(ctx, int64(5)).Return(service.ErrNotAuthor)
+
+			_, err := server.DeleteComment(ctx, &pb.DeleteCommentRequest{Id: 5})
+			Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+		})
+
+		It("should map a missing comment to NotFound", func() {
+			svcMock.EXPECT().DeleteComment(ctx, int64(5)).Return(repository.ErrCommentNotFound)
+
+			_, err := server.DeleteComment(ctx, &pb.DeleteCommentRequest{Id: 5})
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+		})
+	})
+})
@@ -0,0 +1,38 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"hearx/pkg/repository"
+	"hearx/pkg/service"
+)
+
+// toStatus maps the service layer's sentinel errors onto gRPC status codes.
+// Context errors, typically a query aborted by the server-side deadline, keep
+// their meaning too. Anything else is returned unchanged.
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, service.ErrQuotaExceeded):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, service.ErrInvalidTask), errors.Is(err, service.ErrInvalidProject),
+		errors.Is(err, service.ErrInvalidComment):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrCycle), errors.Is(err, service.ErrBlocked):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, repository.ErrProjectExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, service.ErrNotAuthor), errors.Is(err, service.ErrNotAdmin):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, repository.ErrNotFound), errors.Is(err, repository.ErrProjectNotFound),
+		errors.Is(err, repository.ErrCommentNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return status.FromContextError(err).Err()
+	default:
+		return err
+	}
+}
@@ -0,0 +1,47 @@
+package grpc
+
+import (
+	"encoding/base64"
+	"strconv"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// pageSize applies the default and the upper bound to a requested size.
+func pageSize(requested int32) int {
+	switch {
+	case requested <= 0:
+		return defaultPageSize
+	case requested > maxPageSize:
+		return maxPageSize
+	default:
+		return int(requested)
+	}
+}
+
+// listAll reports whether a request predates paging: no size and no token
+// ask for every row in one response, as before page_size existed.
+func listAll(size int32, token string) bool {
+	return size == 0 && token == ""
+}
+
+// Page tokens are opaque to clients; they wrap the last ID of the page.
+func encodePageToken(lastID int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(lastID, 10)))
+}
+
+func decodePageToken(token string) (int64, error) {
+	if token == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, status.Error(codes.InvalidArgument, "invalid page token")
+	}
+	id, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || id < 0 {
+		return 0, status.Error(codes.InvalidArgument, "invalid page token")
+	}
+	return id, nil
+}
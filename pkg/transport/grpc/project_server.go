@@ -0,0 +1,111 @@
+package grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"hearx/pkg/model"
+	"hearx/pkg/service"
+	pb "hearx/proto"
+)
+
+// ProjectServer implements the gRPC ProjectService.
+type ProjectServer struct {
+	pb.UnimplementedProjectServiceServer
+	svc service.ProjectService
+}
+
+// NewProjectServer constructs a ProjectServer with the given business‐logic service.
+func NewProjectServer(svc service.ProjectService) *ProjectServer {
+	return &ProjectServer{svc: svc}
+}
+
+// CreateProject creates a project owned by the caller.
+func (s *ProjectServer) CreateProject(ctx context.Context, req *pb.CreateProjectRequest) (*pb.CreateProjectResponse, error) {
+	in := model.Project{
+		Name:        req.GetProject().GetName(),
+		Description: req.GetProject().GetDescription(),
+	}
+	created, err := s.svc.CreateProject(ctx, in)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.CreateProjectResponse{Project: projectToProto(created)}, nil
+}
+
+// GetProject returns one project, archived or not.
+func (s *ProjectServer) GetProject(ctx context.Context, req *pb.GetProjectRequest) (*pb.GetProjectResponse, error) {
+	p, err := s.svc.GetProject(ctx, req.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.GetProjectResponse{Project: projectToProto(p)}, nil
+}
+
+// UpdateProject changes a project's name, description and archived flag.
+func (s *ProjectServer) UpdateProject(ctx context.Context, req *pb.UpdateProjectRequest) (*pb.UpdateProjectResponse, error) {
+	in := model.Project{
+		ID:          req.GetProject().GetId(),
+		Name:        req.GetProject().GetName(),
+		Description: req.GetProject().GetDescription(),
+		Archived:    req.GetProject().GetArchived(),
+	}
+	updated, err := s.svc.UpdateProject(ctx, in)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.UpdateProjectResponse{Project: projectToProto(updated)}, nil
+}
+
+// ListProjects returns the projects by name.
+func (s *ProjectServer) ListProjects(ctx context.Context, req *pb.ListProjectsRequest) (*pb.ListProjectsResponse, error) {
+	list, err := s.svc.ListProjects(ctx, req.IncludeArchived)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &pb.ListProjectsResponse{Projects: make([]*pb.Project, 0, len(list))}
+	for _, p := range list {
+		resp.Projects = append(resp.Projects, projectToProto(p))
+	}
+	return resp, nil
+}
+
+// DeleteProject removes a project, keeping its tasks.
+func (s *ProjectServer) DeleteProject(ctx context.Context, req *pb.DeleteProjectRequest) (*pb.DeleteProjectResponse, error) {
+	if req.Id <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	if err := s.svc.DeleteProject(ctx, req.Id); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.DeleteProjectResponse{}, nil
+}
+
+// GetProjectStats counts a project's tasks by state.
+func (s *ProjectServer) GetProjectStats(ctx context.Context, req *pb.GetProjectStatsRequest) (*pb.GetProjectStatsResponse, error) {
+	stats, err := s.svc.ProjectStats(ctx, req.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.GetProjectStatsResponse{Stats: &pb.ProjectStats{
+		Total:     stats.Total,
+		Completed: stats.Completed,
+		Open:      stats.Open,
+		Overdue:   stats.Overdue,
+	}}, nil
+}
+
+// projectToProto maps an internal project onto its wire representation.
+func projectToProto(p model.Project) *pb.Project {
+	return &pb.Project{
+		Id:          p.ID,
+		Name:        p.Name,
+		Description: p.Description,
+		Archived:    p.Archived,
+		Owner:       p.Owner,
+		CreatedAt:   timestamp(p.CreatedAt),
+		UpdatedAt:   timestamp(p.UpdatedAt),
+	}
+}
@@ -0,0 +1,99 @@
+// pkg/transport/grpc/project_server_test.go
+package grpc_test
+
+import (
+	"context"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"hearx/pkg/model"
+	"hearx/pkg/repository"
+	"hearx/pkg/service"
+	mocksvc "hearx/pkg/service/mock_service"
+	grpcTransport "hearx/pkg/transport/grpc"
+	pb "hearx/proto"
+)
+
+var _ = Describe("ProjectServer (gRPC)", func() {
+	var (
+		ctrl    *gomock.Controller
+		svcMock *mocksvc.MockProjectService
+		server  *grpcTransport.ProjectServer
+		ctx     context.Context
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		svcMock = mocksvc.NewMockProjectService(ctrl)
+		server = grpcTransport.NewProjectServer(svcMock)
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		ctrl.Finish()
+	})
+
+	Describe("CreateProject", func() {
+		It("should read name and description only", func() {
+			svcMock.EXPECT().CreateProject(ctx, model.Project{Name: "Home", Description: "d"}).
+				Return(model.Project{ID: 1, Name: "Home", Description: "d", Owner: "alice"}, nil)
+
+			resp, err := server.CreateProject(ctx, &pb.CreateProjectRequest{
+				Project: &pb.Project{Name: "Home", Description: "d", Archived: true, Owner: "bob"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Project.Id).To(Equal(int64(1)))
+			Expect(resp.Project.Owner).To(Equal("alice"))
+		})
+
+		It("should map a duplicate name to AlreadyExists", func() {
+			svcMock.EXPECT().CreateProject(ctx, gomock.Any()).Return(model.Project{}, repository.ErrProjectExists)
+
+			_, err := server.CreateProject(ctx, &pb.CreateProjectRequest{Project: &pb.Project{Name: "Home"}})
+			Expect(status.Code(err)).To(Equal(codes.AlreadyExists))
+		})
+
+		It("should map an invalid project to InvalidArgument", func() {
+			svcMock.EXPECT().CreateProject(ctx, gomock.Any()).Return(model.Project{}, service.ErrInvalidProject)
+
+			_, err := server.CreateProject(ctx, &pb.CreateProjectRequest{})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+	})
+
+	Describe("GetProject", func() {
+		It("should map a missing project to NotFound", func() {
+			svcMock.EXPECT().GetProject(ctx, int64(9)).Return(model.Project{}, repository.ErrProjectNotFound)
+
+			_, err := server.GetProject(ctx, &pb.GetProjectRequest{Id: 9})
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+		})
+	})
+
+	Describe("ListProjects", func() {
+		It("should pass include_archived through", func() {
+			svcMock.EXPECT().ListProjects(ctx, true).
+				Return([]model.Project{{ID: 1, Name: "A"}, {ID: 2, Name: "B", Archived: true}}, nil)
+
+			resp, err := server.ListProjects(ctx, &pb.ListProjectsRequest{IncludeArchived: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Projects).To(HaveLen(2))
+			Expect(resp.Projects[1].Archived).To(BeTrue())
+		})
+	})
+
+	Describe("GetProjectStats", func() {
+		It("should return the counts", func() {
+			svcMock.EXPECT().ProjectStats(ctx, int64(1)).
+				Return(model.ProjectStats{Total: 5, Completed: 2, Open: 3, Overdue: 1}, nil)
+
+			resp, err := server.GetProjectStats(ctx, &pb.GetProjectStatsRequest{Id: 1})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Stats).To(Equal(&pb.ProjectStats{Total: 5, Completed: 2, Open: 3, Overdue: 1}))
+		})
+	})
+})
@@ -2,12 +2,24 @@ package grpc
 
 import (
 	"context"
+	"time"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"hearx/pkg/auth"
 	"hearx/pkg/model"
 	"hearx/pkg/service"
 	pb "hearx/proto"
 )
 
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+	maxBatchSize    = 500
+)
+
 // TaskServer implements the gRPC TodoService.
 type TaskServer struct {
 	pb.UnimplementedTodoServiceServer
@@ -25,56 +37,408 @@ func (s *TaskServer) AddTask(ctx context.Context, req *pb.AddTaskRequest) (*pb.A
 	in := model.Task{
 		Title:       req.Task.Title,
 		Description: req.Task.Description,
+		ParentID:    req.Task.ParentId,
+		ProjectID:   req.Task.ProjectId,
+		Assignee:    req.Task.Assignee,
+		DueAt:       fromTimestamp(req.Task.DueAt),
+		Recurrence:  req.Task.Recurrence,
 	}
 
 	created, err := s.svc.AddTask(ctx, in)
 	if err != nil {
-		return nil, err
+		return nil, toStatus(err)
 	}
 
 	// map from internal model → proto
-	return &pb.AddTaskResponse{
-		Task: &pb.Task{
-			Id:          created.ID,
-			Title:       created.Title,
-			Description: created.Description,
-			Completed:   created.Completed,
-		},
-	}, nil
+	return &pb.AddTaskResponse{Task: toProto(created)}, nil
+}
+
+// AddTasks creates a batch of tasks in one transaction.
+func (s *TaskServer) AddTasks(ctx context.Context, req *pb.AddTasksRequest) (*pb.AddTasksResponse, error) {
+	if n := len(req.Tasks); n == 0 || n > maxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "batch must hold 1 to %d tasks, got %d", maxBatchSize, n)
+	}
+	in := make([]model.Task, 0, len(req.Tasks))
+	for _, t := range req.Tasks {
+		in = append(in, model.Task{
+			Title:       t.GetTitle(),
+			Description: t.GetDescription(),
+			Completed:   t.GetCompleted(),
+			ProjectID:   t.GetProjectId(),
+			Assignee:    t.GetAssignee(),
+			DueAt:       fromTimestamp(t.GetDueAt()),
+			Recurrence:  t.GetRecurrence(),
+		})
+	}
+
+	created, err := s.svc.AddTasks(ctx, in)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &pb.AddTasksResponse{Tasks: make([]*pb.Task, 0, len(created))}
+	for _, t := range created {
+		resp.Tasks = append(resp.Tasks, toProto(t))
+	}
+	return resp, nil
 }
 
-// CompleteTask marks the given task as completed.
+// CompleteTask marks the given task as completed, returning the next
+// occurrence too if the task repeats.
 func (s *TaskServer) CompleteTask(ctx context.Context, req *pb.CompleteTaskRequest) (*pb.CompleteTaskResponse, error) {
-	updated, err := s.svc.CompleteTask(ctx, req.Id)
+	done, next, err := s.svc.CompleteTask(ctx, req.Id)
 	if err != nil {
-		return nil, err
+		return nil, toStatus(err)
+	}
+
+	resp := &pb.CompleteTaskResponse{Task: toProto(done)}
+	if next.ID != 0 {
+		resp.Next = toProto(next)
+	}
+	return resp, nil
+}
+
+// UpdateTask changes a task's title and description.
+func (s *TaskServer) UpdateTask(ctx context.Context, req *pb.UpdateTaskRequest) (*pb.UpdateTaskResponse, error) {
+	in := model.Task{
+		ID:          req.GetTask().GetId(),
+		Title:       req.GetTask().GetTitle(),
+		Description: req.GetTask().GetDescription(),
+	}
+
+	updated, err := s.svc.UpdateTask(ctx, in)
+	if err != nil {
+		return nil, toStatus(err)
 	}
 
-	return &pb.CompleteTaskResponse{
-		Task: &pb.Task{
-			Id:          updated.ID,
-			Title:       updated.Title,
-			Description: updated.Description,
-			Completed:   updated.Completed,
-		},
-	}, nil
+	return &pb.UpdateTaskResponse{Task: toProto(updated)}, nil
 }
 
-// ListTasks retrieves all tasks.
-func (s *TaskServer) ListTasks(ctx context.Context, _ *pb.ListTasksRequest) (*pb.ListTasksResponse, error) {
-	list, err := s.svc.ListTasks(ctx)
+// ListTasks retrieves one page of tasks, or every task for a request with
+// neither page_size nor page_token.
+func (s *TaskServer) ListTasks(ctx context.Context, req *pb.ListTasksRequest) (*pb.ListTasksResponse, error) {
+	afterID, err := decodePageToken(req.PageToken)
 	if err != nil {
 		return nil, err
 	}
+	assignee, err := assigneeFilter(ctx, req.Assignee, req.AssignedToMe)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := model.TaskFilter{
+		AfterID:         afterID,
+		Ready:           req.Ready,
+		ProjectID:       req.ProjectId,
+		Assignee:        assignee,
+		IncludeArchived: req.IncludeArchived,
+	}
+	size := 0
+	if !listAll(req.PageSize, req.PageToken) {
+		// ask for one extra row to learn whether another page follows
+		size = pageSize(req.PageSize)
+		filter.Limit = size + 1
+	}
+	list, err := s.svc.ListTasks(ctx, filter)
+	if err != nil {
+		return nil, toStatus(err)
+	}
 
 	resp := &pb.ListTasksResponse{}
+	if size > 0 && len(list) > size {
+		list = list[:size]
+		resp.NextPageToken = encodePageToken(list[size-1].ID)
+	}
 	for _, t := range list {
-		resp.Tasks = append(resp.Tasks, &pb.Task{
-			Id:          t.ID,
-			Title:       t.Title,
-			Description: t.Description,
-			Completed:   t.Completed,
-		})
+		resp.Tasks = append(resp.Tasks, toProto(t))
 	}
 	return resp, nil
 }
+
+// ListChildren returns the direct subtasks of a task.
+func (s *TaskServer) ListChildren(ctx context.Context, req *pb.ListChildrenRequest) (*pb.ListChildrenResponse, error) {
+	if req.ParentId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "parent_id is required")
+	}
+	list, err := s.svc.ListChildren(ctx, req.ParentId)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &pb.ListChildrenResponse{Tasks: make([]*pb.Task, 0, len(list))}
+	for _, t := range list {
+		resp.Tasks = append(resp.Tasks, toProto(t))
+	}
+	return resp, nil
+}
+
+// MoveTask reparents a task; a parent_id of 0 makes it top level.
+func (s *TaskServer) MoveTask(ctx context.Context, req *pb.MoveTaskRequest) (*pb.MoveTaskResponse, error) {
+	if req.ParentId < 0 {
+		return nil, status.Error(codes.InvalidArgument, "parent_id must not be negative")
+	}
+	moved, err := s.svc.MoveTask(ctx, req.Id, req.ParentId)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &pb.MoveTaskResponse{Task: toProto(moved)}, nil
+}
+
+// AddDependency makes task_id wait for blocker_id.
+func (s *TaskServer) AddDependency(ctx context.Context, req *pb.AddDependencyRequest) (*pb.AddDependencyResponse, error) {
+	if req.TaskId <= 0 || req.BlockerId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "task_id and blocker_id are required")
+	}
+	if err := s.svc.AddDependency(ctx, req.TaskId, req.BlockerId); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.AddDependencyResponse{}, nil
+}
+
+// RemoveDependency drops a dependency between two tasks.
+func (s *TaskServer) RemoveDependency(ctx context.Context, req *pb.RemoveDependencyRequest) (*pb.RemoveDependencyResponse, error) {
+	if err := s.svc.RemoveDependency(ctx, req.TaskId, req.BlockerId); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.RemoveDependencyResponse{}, nil
+}
+
+// ListBlockers returns the tasks a task waits for.
+func (s *TaskServer) ListBlockers(ctx context.Context, req *pb.ListBlockersRequest) (*pb.ListBlockersResponse, error) {
+	list, err := s.svc.ListBlockers(ctx, req.TaskId)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &pb.ListBlockersResponse{Tasks: make([]*pb.Task, 0, len(list))}
+	for _, t := range list {
+		resp.Tasks = append(resp.Tasks, toProto(t))
+	}
+	return resp, nil
+}
+
+// PlanTasks lists the open tasks in dependency order.
+func (s *TaskServer) PlanTasks(ctx context.Context, req *pb.PlanTasksRequest) (*pb.PlanTasksResponse, error) {
+	assignee, err := assigneeFilter(ctx, req.Assignee, req.AssignedToMe)
+	if err != nil {
+		return nil, err
+	}
+	list, err := s.svc.PlanTasks(ctx, model.TaskFilter{ProjectID: req.ProjectId, Assignee: assignee})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &pb.PlanTasksResponse{Tasks: make([]*pb.Task, 0, len(list))}
+	for _, t := range list {
+		resp.Tasks = append(resp.Tasks, toProto(t))
+	}
+	return resp, nil
+}
+
+// SetRecurrence changes a task's recurrence rule and, if given, due date.
+func (s *TaskServer) SetRecurrence(ctx context.Context, req *pb.SetRecurrenceRequest) (*pb.SetRecurrenceResponse, error) {
+	updated, err := s.svc.SetRecurrence(ctx, req.Id, req.Recurrence, fromTimestamp(req.DueAt))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.SetRecurrenceResponse{Task: toProto(updated)}, nil
+}
+
+// SetTaskProject moves a task into a project; a project_id of 0 takes it out.
+func (s *TaskServer) SetTaskProject(ctx context.Context, req *pb.SetTaskProjectRequest) (*pb.SetTaskProjectResponse, error) {
+	if req.ProjectId < 0 {
+		return nil, status.Error(codes.InvalidArgument, "project_id must not be negative")
+	}
+	moved, err := s.svc.SetTaskProject(ctx, req.Id, req.ProjectId)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.SetTaskProjectResponse{Task: toProto(moved)}, nil
+}
+
+// AssignTask makes a registered user responsible for a task.
+func (s *TaskServer) AssignTask(ctx context.Context, req *pb.AssignTaskRequest) (*pb.AssignTaskResponse, error) {
+	if req.Assignee == "" {
+		return nil, status.Error(codes.InvalidArgument, "assignee is required")
+	}
+	assigned, err := s.svc.AssignTask(ctx, req.Id, req.Assignee)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.AssignTaskResponse{Task: toProto(assigned)}, nil
+}
+
+// UnassignTask leaves a task without an assignee.
+func (s *TaskServer) UnassignTask(ctx context.Context, req *pb.UnassignTaskRequest) (*pb.UnassignTaskResponse, error) {
+	unassigned, err := s.svc.UnassignTask(ctx, req.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.UnassignTaskResponse{Task: toProto(unassigned)}, nil
+}
+
+// ListUsers returns the users tasks can be assigned to.
+func (s *TaskServer) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
+	list, err := s.svc.ListUsers(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &pb.ListUsersResponse{}
+	for _, u := range list {
+		resp.Users = append(resp.Users, &pb.User{Name: u.Name, CreatedAt: timestamp(u.CreatedAt)})
+	}
+	return resp, nil
+}
+
+// DeleteTask soft-deletes a task.
+func (s *TaskServer) DeleteTask(ctx context.Context, req *pb.DeleteTaskRequest) (*pb.DeleteTaskResponse, error) {
+	if err := s.svc.DeleteTask(ctx, req.Id); err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.DeleteTaskResponse{}, nil
+}
+
+// GetTaskHistory returns a page of a task's revisions, oldest first. The
+// page token is the last revision number returned.
+func (s *TaskServer) GetTaskHistory(ctx context.Context, req *pb.GetTaskHistoryRequest) (*pb.GetTaskHistoryResponse, error) {
+	if req.TaskId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "task_id is required")
+	}
+	after, err := decodePageToken(req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	size := pageSize(req.PageSize)
+
+	// ask for one extra revision to learn whether another page follows
+	revs, err := s.svc.TaskHistory(ctx, req.TaskId, after, size+1)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &pb.GetTaskHistoryResponse{}
+	if len(revs) > size {
+		revs = revs[:size]
+		resp.NextPageToken = encodePageToken(revs[size-1].Number)
+	}
+	for _, r := range revs {
+		resp.Revisions = append(resp.Revisions, revisionToProto(r))
+	}
+	return resp, nil
+}
+
+// RestoreTaskRevision writes a task back as it was at an earlier revision.
+func (s *TaskServer) RestoreTaskRevision(ctx context.Context, req *pb.RestoreTaskRevisionRequest) (*pb.RestoreTaskRevisionResponse, error) {
+	if req.TaskId <= 0 || req.Revision <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "task_id and revision are required")
+	}
+	restored, err := s.svc.RestoreTaskRevision(ctx, req.TaskId, req.Revision)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.RestoreTaskRevisionResponse{Task: toProto(restored)}, nil
+}
+
+// ListDeletedTasks returns a page of the trash, ordered by ID.
+func (s *TaskServer) ListDeletedTasks(ctx context.Context, req *pb.ListDeletedTasksRequest) (*pb.ListDeletedTasksResponse, error) {
+	afterID, err := decodePageToken(req.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	size := pageSize(req.PageSize)
+
+	// ask for one extra row to learn whether another page follows
+	list, err := s.svc.ListDeletedTasks(ctx, model.TaskFilter{
+		AfterID:   afterID,
+		Limit:     size + 1,
+		ProjectID: req.ProjectId,
+	})
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	resp := &pb.ListDeletedTasksResponse{}
+	if len(list) > size {
+		list = list[:size]
+		resp.NextPageToken = encodePageToken(list[size-1].ID)
+	}
+	for _, t := range list {
+		resp.Tasks = append(resp.Tasks, toProto(t))
+	}
+	return resp, nil
+}
+
+// UndeleteTask takes a task out of the trash.
+func (s *TaskServer) UndeleteTask(ctx context.Context, req *pb.UndeleteTaskRequest) (*pb.UndeleteTaskResponse, error) {
+	undeleted, err := s.svc.UndeleteTask(ctx, req.Id)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &pb.UndeleteTaskResponse{Task: toProto(undeleted)}, nil
+}
+
+// assigneeFilter resolves assigned_to_me to the caller's name.
+func assigneeFilter(ctx context.Context, assignee string, mine bool) (string, error) {
+	if !mine {
+		return assignee, nil
+	}
+	if assignee != "" {
+		return "", status.Error(codes.InvalidArgument, "assignee and assigned_to_me are mutually exclusive")
+	}
+	caller, ok := auth.CallerFromContext(ctx)
+	if !ok {
+		return "", status.Error(codes.InvalidArgument, "assigned_to_me needs an authenticated caller")
+	}
+	return caller, nil
+}
+
+// toProto maps an internal task onto its wire representation.
+func toProto(t model.Task) *pb.Task {
+	return &pb.Task{
+		Id:          t.ID,
+		Title:       t.Title,
+		Description: t.Description,
+		Completed:   t.Completed,
+		Owner:       t.Owner,
+		Assignee:    t.Assignee,
+		ParentId:    t.ParentID,
+		ProjectId:   t.ProjectID,
+		DueAt:       timestamp(t.DueAt),
+		Recurrence:  t.Recurrence,
+		CreatedAt:   timestamp(t.CreatedAt),
+		UpdatedAt:   timestamp(t.UpdatedAt),
+		DeletedAt:   timestamp(t.DeletedAt),
+		ArchivedAt:  timestamp(t.ArchivedAt),
+	}
+}
+
+func revisionToProto(r model.Revision) *pb.TaskRevision {
+	rev := &pb.TaskRevision{
+		TaskId:    r.TaskID,
+		Revision:  r.Number,
+		Task:      toProto(r.Task),
+		Deleted:   r.Deleted,
+		CreatedAt: timestamp(r.CreatedAt),
+	}
+	for _, c := range r.Changes {
+		rev.Changes = append(rev.Changes, &pb.FieldChange{Field: c.Field, From: c.From, To: c.To})
+	}
+	return rev
+}
+
+// timestamp leaves unset times unset on the wire.
+func timestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// fromTimestamp maps an unset timestamp to the zero time.
+func fromTimestamp(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
@@ -4,12 +4,19 @@ package grpc_test
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"hearx/pkg/auth"
 	"hearx/pkg/model"
+	"hearx/pkg/repository"
+	"hearx/pkg/service"
 	mocksvc "hearx/pkg/service/mock_service"
 	grpcTransport "hearx/pkg/transport/grpc"
 	pb "hearx/proto"
@@ -72,10 +79,163 @@ var _ = Describe("TaskServer (gRPC)", func() {
 			_, err := server.AddTask(ctx, req)
 			Expect(err).To(MatchError("boom"))
 		})
+
+		It("should map an exceeded quota to ResourceExhausted", func() {
+			req := &pb.AddTaskRequest{Task: &pb.Task{Title: "one too many"}}
+
+			svcMock.
+				EXPECT().
+				AddTask(ctx, gomock.Any()).
+				Return(model.Task{}, service.ErrQuotaExceeded)
+
+			_, err := server.AddTask(ctx, req)
+			Expect(status.Code(err)).To(Equal(codes.ResourceExhausted))
+		})
+	})
+
+	Describe("AddTasks", func() {
+		It("should pass title, description and completion of each task", func() {
+			req := &pb.AddTasksRequest{Tasks: []*pb.Task{
+				{Id: 9, Title: "a", Owner: "mallory"},
+				{Title: "b", Description: "d", Completed: true},
+			}}
+
+			svcMock.
+				EXPECT().
+				AddTasks(ctx, []model.Task{{Title: "a"}, {Title: "b", Description: "d", Completed: true}}).
+				Return([]model.Task{{ID: 1, Title: "a"}, {ID: 2, Title: "b", Description: "d", Completed: true}}, nil)
+
+			resp, err := server.AddTasks(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Tasks).To(HaveLen(2))
+			Expect(resp.Tasks[1].Id).To(Equal(int64(2)))
+		})
+
+		It("should reject empty and oversized batches", func() {
+			_, err := server.AddTasks(ctx, &pb.AddTasksRequest{})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+
+			big := &pb.AddTasksRequest{Tasks: make([]*pb.Task, 501)}
+			_, err = server.AddTasks(ctx, big)
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+	})
+
+	Describe("UpdateTask", func() {
+		It("should pass id, title and description to the service", func() {
+			req := &pb.UpdateTaskRequest{Task: &pb.Task{Id: 4, Title: "t", Description: "d", Completed: true}}
+
+			svcMock.
+				EXPECT().
+				UpdateTask(ctx, model.Task{ID: 4, Title: "t", Description: "d"}).
+				Return(model.Task{ID: 4, Title: "t", Description: "d"}, nil)
+
+			resp, err := server.UpdateTask(ctx, req)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Task.Title).To(Equal("t"))
+		})
+
+		It("should map a missing task to NotFound", func() {
+			svcMock.
+				EXPECT().
+				UpdateTask(ctx, gomock.Any()).
+				Return(model.Task{}, repository.ErrNotFound)
+
+			_, err := server.UpdateTask(ctx, &pb.UpdateTaskRequest{Task: &pb.Task{Id: 99, Title: "t"}})
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+		})
+	})
+
+	Describe("ListChildren", func() {
+		It("should map the children including their parent", func() {
+			svcMock.
+				EXPECT().
+				ListChildren(ctx, int64(1)).
+				Return([]model.Task{{ID: 2, Title: "sub", ParentID: 1}}, nil)
+
+			resp, err := server.ListChildren(ctx, &pb.ListChildrenRequest{ParentId: 1})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Tasks).To(HaveLen(1))
+			Expect(resp.Tasks[0].ParentId).To(Equal(int64(1)))
+		})
+
+		It("should require a parent id", func() {
+			_, err := server.ListChildren(ctx, &pb.ListChildrenRequest{})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+	})
+
+	Describe("MoveTask", func() {
+		It("should pass id and parent to the service", func() {
+			svcMock.
+				EXPECT().
+				MoveTask(ctx, int64(3), int64(1)).
+				Return(model.Task{ID: 3, ParentID: 1}, nil)
+
+			resp, err := server.MoveTask(ctx, &pb.MoveTaskRequest{Id: 3, ParentId: 1})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Task.ParentId).To(Equal(int64(1)))
+		})
+
+		It("should map a cycle to FailedPrecondition", func() {
+			svcMock.
+				EXPECT().
+				MoveTask(ctx, int64(1), int64(3)).
+				Return(model.Task{}, fmt.Errorf("%w: test", service.ErrCycle))
+
+			_, err := server.MoveTask(ctx, &pb.MoveTaskRequest{Id: 1, ParentId: 3})
+			Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		})
+	})
+
+	Describe("AddDependency", func() {
+		It("should pass both IDs to the service", func() {
+			svcMock.EXPECT().AddDependency(ctx, int64(3), int64(1)).Return(nil)
+
+			_, err := server.AddDependency(ctx, &pb.AddDependencyRequest{TaskId: 3, BlockerId: 1})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should require both IDs", func() {
+			_, err := server.AddDependency(ctx, &pb.AddDependencyRequest{TaskId: 3})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+
+		It("should map a cycle to FailedPrecondition", func() {
+			svcMock.EXPECT().AddDependency(ctx, int64(1), int64(3)).Return(fmt.Errorf("%w: test", service.ErrCycle))
+
+			_, err := server.AddDependency(ctx, &pb.AddDependencyRequest{TaskId: 1, BlockerId: 3})
+			Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
+		})
+	})
+
+	Describe("PlanTasks", func() {
+		It("should pass the filter and keep the service's order", func() {
+			svcMock.EXPECT().PlanTasks(ctx, model.TaskFilter{ProjectID: 2}).Return([]model.Task{{ID: 4}, {ID: 1}}, nil)
+
+			resp, err := server.PlanTasks(ctx, &pb.PlanTasksRequest{ProjectId: 2})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Tasks).To(HaveLen(2))
+			Expect(resp.Tasks[0].Id).To(Equal(int64(4)))
+			Expect(resp.Tasks[1].Id).To(Equal(int64(1)))
+		})
+
+		It("should plan the caller's own tasks", func() {
+			mine := auth.WithCaller(ctx, "alice")
+			svcMock.EXPECT().PlanTasks(mine, model.TaskFilter{Assignee: "alice"}).Return(nil, nil)
+
+			_, err := server.PlanTasks(mine, &pb.PlanTasksRequest{AssignedToMe: true})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject assignee together with assigned_to_me", func() {
+			_, err := server.PlanTasks(ctx, &pb.PlanTasksRequest{Assignee: "bob", AssignedToMe: true})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
 	})
 
 	Describe("ListTasks", func() {
-		It("should call service.ListTasks and map the result", func() {
+		It("should list every task when neither page_size nor page_token is set", func() {
 			tasks := []model.Task{
 				{ID: 1, Title: "A", Completed: false},
 				{ID: 2, Title: "B", Completed: true},
@@ -83,7 +243,7 @@ var _ = Describe("TaskServer (gRPC)", func() {
 
 			svcMock.
 				EXPECT().
-				ListTasks(ctx).
+				ListTasks(ctx, model.TaskFilter{}).
 				Return(tasks, nil)
 
 			resp, err := server.ListTasks(ctx, &pb.ListTasksRequest{})
@@ -91,17 +251,112 @@ var _ = Describe("TaskServer (gRPC)", func() {
 			Expect(len(resp.Tasks)).To(Equal(2))
 			Expect(resp.Tasks[1].Id).To(Equal(int64(2)))
 			Expect(resp.Tasks[1].Completed).To(BeTrue())
+			Expect(resp.NextPageToken).To(BeEmpty())
+		})
+
+		It("should page through tasks with a next page token", func() {
+			svcMock.
+				EXPECT().
+				ListTasks(ctx, model.TaskFilter{Limit: 3}).
+				Return([]model.Task{{ID: 1}, {ID: 2}, {ID: 3}}, nil)
+
+			first, err := server.ListTasks(ctx, &pb.ListTasksRequest{PageSize: 2})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(first.Tasks).To(HaveLen(2))
+			Expect(first.NextPageToken).NotTo(BeEmpty())
+
+			svcMock.
+				EXPECT().
+				ListTasks(ctx, model.TaskFilter{AfterID: 2, Limit: 3}).
+				Return([]model.Task{{ID: 3}}, nil)
+
+			second, err := server.ListTasks(ctx, &pb.ListTasksRequest{PageSize: 2, PageToken: first.NextPageToken})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second.Tasks).To(HaveLen(1))
+			Expect(second.NextPageToken).To(BeEmpty())
+		})
+
+		It("should use the default page size once paging has started", func() {
+			svcMock.
+				EXPECT().
+				ListTasks(ctx, model.TaskFilter{Limit: 3}).
+				Return([]model.Task{{ID: 1}, {ID: 2}, {ID: 3}}, nil)
+			first, err := server.ListTasks(ctx, &pb.ListTasksRequest{PageSize: 2})
+			Expect(err).NotTo(HaveOccurred())
+
+			svcMock.
+				EXPECT().
+				ListTasks(ctx, model.TaskFilter{AfterID: 2, Limit: 101}).
+				Return([]model.Task{{ID: 3}}, nil)
+			resp, err := server.ListTasks(ctx, &pb.ListTasksRequest{PageToken: first.NextPageToken})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.NextPageToken).To(BeEmpty())
+		})
+
+		It("should reject a malformed page token", func() {
+			_, err := server.ListTasks(ctx, &pb.ListTasksRequest{PageToken: "!!"})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
 		})
 
 		It("should propagate service errors", func() {
 			svcMock.
 				EXPECT().
-				ListTasks(ctx).
+				ListTasks(ctx, gomock.Any()).
 				Return(nil, errors.New("fail"))
 
 			_, err := server.ListTasks(ctx, &pb.ListTasksRequest{})
 			Expect(err).To(MatchError("fail"))
 		})
+
+		It("should pass the ready filter to the service", func() {
+			svcMock.
+				EXPECT().
+				ListTasks(ctx, model.TaskFilter{Ready: true}).
+				Return(nil, nil)
+
+			_, err := server.ListTasks(ctx, &pb.ListTasksRequest{Ready: true})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should pass the project filter to the service", func() {
+			svcMock.
+				EXPECT().
+				ListTasks(ctx, model.TaskFilter{ProjectID: 3}).
+				Return(nil, nil)
+
+			_, err := server.ListTasks(ctx, &pb.ListTasksRequest{ProjectId: 3})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should pass include_archived to the service and map archived_at", func() {
+			archived := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+			svcMock.
+				EXPECT().
+				ListTasks(ctx, model.TaskFilter{IncludeArchived: true}).
+				Return([]model.Task{{ID: 1, Completed: true, ArchivedAt: archived}}, nil)
+
+			resp, err := server.ListTasks(ctx, &pb.ListTasksRequest{IncludeArchived: true})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Tasks[0].ArchivedAt.AsTime()).To(Equal(archived))
+		})
+
+		It("should resolve assigned_to_me to the caller", func() {
+			mine := auth.WithCaller(ctx, "alice")
+			svcMock.
+				EXPECT().
+				ListTasks(mine, model.TaskFilter{Assignee: "alice"}).
+				Return(nil, nil)
+
+			_, err := server.ListTasks(mine, &pb.ListTasksRequest{AssignedToMe: true})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject assigned_to_me without a caller or with an assignee", func() {
+			_, err := server.ListTasks(ctx, &pb.ListTasksRequest{AssignedToMe: true})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			_, err = server.ListTasks(auth.WithCaller(ctx, "alice"), &pb.ListTasksRequest{AssignedToMe: true, Assignee: "bob"})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
 	})
 
 	Describe("CompleteTask", func() {
@@ -112,22 +367,185 @@ var _ = Describe("TaskServer (gRPC)", func() {
 			svcMock.
 				EXPECT().
 				CompleteTask(ctx, id).
-				Return(updated, nil)
+				Return(updated, model.Task{}, nil)
 
 			resp, err := server.CompleteTask(ctx, &pb.CompleteTaskRequest{Id: id})
 			Expect(err).NotTo(HaveOccurred())
 			Expect(resp.Task.Id).To(Equal(id))
 			Expect(resp.Task.Completed).To(BeTrue())
+			Expect(resp.Next).To(BeNil())
+		})
+
+		It("should return the next occurrence of a recurring task", func() {
+			due := time.Date(2026, 10, 26, 9, 0, 0, 0, time.UTC)
+			svcMock.
+				EXPECT().
+				CompleteTask(ctx, int64(5)).
+				Return(model.Task{ID: 5, Completed: true}, model.Task{ID: 6, DueAt: due, Recurrence: "FREQ=WEEKLY"}, nil)
+
+			resp, err := server.CompleteTask(ctx, &pb.CompleteTaskRequest{Id: 5})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Next.Id).To(Equal(int64(6)))
+			Expect(resp.Next.DueAt.AsTime()).To(Equal(due))
+			Expect(resp.Next.Recurrence).To(Equal("FREQ=WEEKLY"))
+		})
+
+		It("should map a blocked task to FailedPrecondition", func() {
+			svcMock.
+				EXPECT().
+				CompleteTask(ctx, int64(2)).
+				Return(model.Task{}, model.Task{}, fmt.Errorf("%w: test", service.ErrBlocked))
+
+			_, err := server.CompleteTask(ctx, &pb.CompleteTaskRequest{Id: 2})
+			Expect(status.Code(err)).To(Equal(codes.FailedPrecondition))
 		})
 
 		It("should propagate service errors", func() {
 			svcMock.
 				EXPECT().
 				CompleteTask(ctx, int64(99)).
-				Return(model.Task{}, errors.New("nop"))
+				Return(model.Task{}, model.Task{}, errors.New("nop"))
 
 			_, err := server.CompleteTask(ctx, &pb.CompleteTaskRequest{Id: 99})
 			Expect(err).To(MatchError("nop"))
 		})
 	})
+
+	Describe("AssignTask", func() {
+		It("should return the assigned task", func() {
+			svcMock.
+				EXPECT().
+				AssignTask(ctx, int64(5), "alice").
+				Return(model.Task{ID: 5, Assignee: "alice"}, nil)
+
+			resp, err := server.AssignTask(ctx, &pb.AssignTaskRequest{Id: 5, Assignee: "alice"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Task.Assignee).To(Equal("alice"))
+		})
+
+		It("should require an assignee", func() {
+			_, err := server.AssignTask(ctx, &pb.AssignTaskRequest{Id: 5})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+
+		It("should map an unregistered user to InvalidArgument", func() {
+			svcMock.
+				EXPECT().
+				AssignTask(ctx, int64(5), "mallory").
+				Return(model.Task{}, fmt.Errorf("%w: test", service.ErrInvalidTask))
+
+			_, err := server.AssignTask(ctx, &pb.AssignTaskRequest{Id: 5, Assignee: "mallory"})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+	})
+
+	Describe("ListUsers", func() {
+		It("should map the users", func() {
+			svcMock.
+				EXPECT().
+				ListUsers(ctx).
+				Return([]model.User{{Name: "alice"}, {Name: "bob"}}, nil)
+
+			resp, err := server.ListUsers(ctx, &pb.ListUsersRequest{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Users).To(HaveLen(2))
+			Expect(resp.Users[1].Name).To(Equal("bob"))
+		})
+	})
+
+	Describe("GetTaskHistory", func() {
+		It("should map revisions and their changes", func() {
+			svcMock.
+				EXPECT().
+				TaskHistory(ctx, int64(5), int64(0), 101).
+				Return([]model.Revision{
+					{TaskID: 5, Number: 1, Task: model.Task{ID: 5, Title: "a"}, Changes: []model.FieldChange{{Field: "title", To: "a"}}},
+					{TaskID: 5, Number: 2, Task: model.Task{ID: 5, Title: "a"}, Deleted: true},
+				}, nil)
+
+			resp, err := server.GetTaskHistory(ctx, &pb.GetTaskHistoryRequest{TaskId: 5})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Revisions).To(HaveLen(2))
+			Expect(resp.Revisions[0].Changes[0].To).To(Equal("a"))
+			Expect(resp.Revisions[1].Deleted).To(BeTrue())
+			Expect(resp.NextPageToken).To(BeEmpty())
+		})
+
+		It("should page by revision number", func() {
+			svcMock.
+				EXPECT().
+				TaskHistory(ctx, int64(5), int64(0), 3).
+				Return([]model.Revision{{Number: 1}, {Number: 2}, {Number: 3}}, nil)
+
+			first, err := server.GetTaskHistory(ctx, &pb.GetTaskHistoryRequest{TaskId: 5, PageSize: 2})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(first.Revisions).To(HaveLen(2))
+
+			svcMock.
+				EXPECT().
+				TaskHistory(ctx, int64(5), int64(2), 3).
+				Return([]model.Revision{{Number: 3}}, nil)
+
+			second, err := server.GetTaskHistory(ctx, &pb.GetTaskHistoryRequest{TaskId: 5, PageSize: 2, PageToken: first.NextPageToken})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second.Revisions).To(HaveLen(1))
+			Expect(second.NextPageToken).To(BeEmpty())
+		})
+
+		It("should map an unknown task to NotFound", func() {
+			svcMock.
+				EXPECT().
+				TaskHistory(ctx, int64(99), int64(0), 101).
+				Return(nil, repository.ErrNotFound)
+
+			_, err := server.GetTaskHistory(ctx, &pb.GetTaskHistoryRequest{TaskId: 99})
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+		})
+	})
+
+	Describe("RestoreTaskRevision", func() {
+		It("should return the restored task", func() {
+			svcMock.
+				EXPECT().
+				RestoreTaskRevision(ctx, int64(5), int64(2)).
+				Return(model.Task{ID: 5, Title: "old"}, nil)
+
+			resp, err := server.RestoreTaskRevision(ctx, &pb.RestoreTaskRevisionRequest{TaskId: 5, Revision: 2})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Task.Title).To(Equal("old"))
+		})
+
+		It("should require a task and a revision", func() {
+			_, err := server.RestoreTaskRevision(ctx, &pb.RestoreTaskRevisionRequest{TaskId: 5})
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+	})
+
+	Describe("ListDeletedTasks", func() {
+		It("should page through the trash with the deletion time", func() {
+			deleted := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+			svcMock.
+				EXPECT().
+				ListDeletedTasks(ctx, model.TaskFilter{Limit: 2, ProjectID: 4}).
+				Return([]model.Task{{ID: 1, DeletedAt: deleted}, {ID: 2, DeletedAt: deleted}}, nil)
+
+			resp, err := server.ListDeletedTasks(ctx, &pb.ListDeletedTasksRequest{PageSize: 1, ProjectId: 4})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(resp.Tasks).To(HaveLen(1))
+			Expect(resp.Tasks[0].DeletedAt.AsTime()).To(Equal(deleted))
+			Expect(resp.NextPageToken).NotTo(BeEmpty())
+		})
+	})
+
+	Describe("UndeleteTask", func() {
+		It("should map a task not in the trash to NotFound", func() {
+			svcMock.
+				EXPECT().
+				UndeleteTask(ctx, int64(9)).
+				Return(model.Task{}, repository.ErrNotFound)
+
+			_, err := server.UndeleteTask(ctx, &pb.UndeleteTaskRequest{Id: 9})
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+		})
+	})
 })
@@ -0,0 +1,90 @@
+// pkg/transport/http/calendar.go
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"hearx/pkg/auth"
+	"hearx/pkg/ical"
+	hlog "hearx/pkg/logger"
+	"hearx/pkg/model"
+	"hearx/pkg/service"
+)
+
+// feedPageSize is how many tasks the feed reads per ListTasks call.
+const feedPageSize = 500
+
+// CalendarHandler serves a caller's tasks as an iCalendar feed of VTODOs at
+// /calendar/{owner}.ics. Callers may only read their own feed.
+type CalendarHandler struct {
+	svc    service.TaskService
+	logger *zap.Logger
+}
+
+// NewCalendarHandler constructs a CalendarHandler. It expects the caller in
+// the request context, see auth.FeedMiddleware.
+func NewCalendarHandler(svc service.TaskService, logger *zap.Logger) *CalendarHandler {
+	return &CalendarHandler{svc: svc, logger: logger}
+}
+
+func (h *CalendarHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	owner, ok := strings.CutSuffix(r.PathValue("file"), ".ics")
+	if !ok || owner == "" {
+		http.NotFound(w, r)
+		return
+	}
+	caller, _ := auth.CallerFromContext(r.Context())
+	if caller != owner {
+		http.Error(w, "feed belongs to another user", http.StatusForbidden)
+		return
+	}
+
+	ctx := r.Context()
+	log := hlog.FromContext(ctx, h.logger).With(zap.String("owner", owner))
+	filter := model.TaskFilter{Owner: owner, Limit: feedPageSize}
+
+	// the first page is read before any output so a failure can still be
+	// reported with a proper status
+	page, err := h.svc.ListTasks(ctx, filter)
+	if err != nil {
+		log.Error("calendar feed failed", zap.Error(err))
+		http.Error(w, "failed to list tasks", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", ical.ContentType)
+	w.Header().Set("Content-Disposition", `inline; filename="`+owner+`.ics"`)
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	enc := ical.NewEncoder(w)
+	for {
+		for _, t := range page {
+			if err := enc.Encode(t); err != nil {
+				log.Debug("calendar feed aborted by client", zap.Error(err))
+				return
+			}
+		}
+		if len(page) < feedPageSize {
+			break
+		}
+		filter.AfterID = page[len(page)-1].ID
+		if page, err = h.svc.ListTasks(ctx, filter); err != nil {
+			// too late for an error status: cut the response short so the
+			// client does not take a truncated calendar as complete
+			log.Error("calendar feed failed mid-stream", zap.Error(err))
+			panic(http.ErrAbortHandler)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		log.Debug("calendar feed aborted by client", zap.Error(err))
+	}
+}
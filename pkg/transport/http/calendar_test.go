@@ -0,0 +1,86 @@
+// pkg/transport/http/calendar_test.go
+package http_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+
+	"hearx/pkg/auth"
+	"hearx/pkg/ical"
+	"hearx/pkg/model"
+	mocksvc "hearx/pkg/service/mock_service"
+	httpTransport "hearx/pkg/transport/http"
+)
+
+var _ = Describe("CalendarHandler", func() {
+	var (
+		ctrl    *gomock.Controller
+		svcMock *mocksvc.MockTaskService
+		mux     *http.ServeMux
+	)
+
+	BeforeEach(func() {
+		ctrl = gomock.NewController(GinkgoT())
+		svcMock = mocksvc.NewMockTaskService(ctrl)
+		h := httpTransport.NewCalendarHandler(svcMock, zap.NewNop())
+		// stand in for auth.FeedMiddleware: every request is alice's
+		mux = http.NewServeMux()
+		mux.Handle("/calendar/{file}", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h.ServeHTTP(w, r.WithContext(auth.WithCaller(r.Context(), "alice")))
+		}))
+	})
+
+	AfterEach(func() { ctrl.Finish() })
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	It("should serve the caller's tasks as VTODOs, page by page", func() {
+		first := make([]model.Task, 500)
+		for i := range first {
+			first[i] = model.Task{ID: int64(i + 1), Title: "t", Owner: "alice"}
+		}
+		gomock.InOrder(
+			svcMock.EXPECT().
+				ListTasks(gomock.Any(), model.TaskFilter{Owner: "alice", Limit: 500}).
+				Return(first, nil),
+			svcMock.EXPECT().
+				ListTasks(gomock.Any(), model.TaskFilter{Owner: "alice", Limit: 500, AfterID: 500}).
+				Return([]model.Task{{ID: 501, Title: "last", Completed: true}}, nil),
+		)
+
+		rec := get("/calendar/alice.ics")
+		Expect(rec.Code).To(Equal(http.StatusOK))
+		Expect(rec.Header().Get("Content-Type")).To(Equal(ical.ContentType))
+
+		tasks, err := ical.Decode(strings.NewReader(rec.Body.String()))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tasks).To(HaveLen(501))
+		Expect(tasks[500].Title).To(Equal("last"))
+		Expect(tasks[500].Completed).To(BeTrue())
+	})
+
+	It("should refuse another user's feed", func() {
+		Expect(get("/calendar/bob.ics").Code).To(Equal(http.StatusForbidden))
+	})
+
+	It("should only serve .ics files", func() {
+		Expect(get("/calendar/alice").Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("should report a failure before streaming as a 500", func() {
+		svcMock.EXPECT().ListTasks(gomock.Any(), gomock.Any()).Return(nil, errors.New("db down"))
+
+		Expect(get("/calendar/alice.ics").Code).To(Equal(http.StatusInternalServerError))
+	})
+})
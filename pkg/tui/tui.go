@@ -0,0 +1,394 @@
+// pkg/tui/tui.go
+package tui
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"hearx/pkg/model"
+)
+
+// Backend is the subset of TodoService the TUI drives. *client.Client
+// satisfies it; tests use an in-memory fake.
+type Backend interface {
+	AddTask(ctx context.Context, t model.Task) (model.Task, error)
+	UpdateTask(ctx context.Context, t model.Task) (model.Task, error)
+	CompleteTask(ctx context.Context, id int64) (model.Task, error)
+	ListTasks(ctx context.Context) iter.Seq2[model.Task, error]
+}
+
+// Options tunes the TUI.
+type Options struct {
+	// Timeout bounds each RPC.
+	Timeout time.Duration
+	// RefreshInterval reloads the list periodically so changes made by
+	// other clients show up; zero disables it.
+	RefreshInterval time.Duration
+}
+
+type mode int
+
+const (
+	modeList mode = iota
+	modeAdd
+	modeEdit
+	modeFilter
+)
+
+// Messages produced by the commands below.
+type (
+	tasksMsg struct {
+		tasks []model.Task
+		err   error
+	}
+	savedMsg struct {
+		verb string
+		task model.Task
+		err  error
+	}
+	tickMsg struct{}
+)
+
+var (
+	titleStyle  = lipgloss.NewStyle().Bold(true)
+	cursorStyle = lipgloss.NewStyle().Reverse(true)
+	doneStyle   = lipgloss.NewStyle().Faint(true).Strikethrough(true)
+	errStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	helpStyle   = lipgloss.NewStyle().Faint(true)
+)
+
+// Model is the bubbletea model of the task list.
+type Model struct {
+	backend Backend
+	opts    Options
+
+	tasks   []model.Task
+	visible []int // indexes into tasks that match the filter
+	cursor  int
+
+	mode   mode
+	fields [2]textinput.Model // title, description
+	focus  int
+	editID int64
+	filter textinput.Model
+
+	status string
+	err    error
+	height int
+}
+
+// New returns a Model talking to b.
+func New(b Backend, opts Options) Model {
+	m := Model{backend: b, opts: opts}
+	m.fields[0] = newInput("Title: ", "title")
+	m.fields[1] = newInput("Description: ", "description")
+	m.filter = newInput("/", "")
+	return m
+}
+
+// newInput returns a text input with a steady cursor; a blinking one would
+// need its timer messages routed through every mode.
+func newInput(prompt, placeholder string) textinput.Model {
+	in := textinput.New()
+	in.Prompt = prompt
+	in.Placeholder = placeholder
+	in.Cursor.SetMode(cursor.CursorStatic)
+	return in
+}
+
+// Run opens the TUI full-screen and blocks until the user quits.
+func Run(b Backend, opts Options) error {
+	_, err := tea.NewProgram(New(b, opts), tea.WithAltScreen()).Run()
+	return err
+}
+
+// Init loads the list and starts the refresh timer.
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(m.load(), m.tick())
+}
+
+// Update handles a key press or the result of an RPC.
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		return m, nil
+	case tickMsg:
+		return m, tea.Batch(m.load(), m.tick())
+	case tasksMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.err = nil
+		m.tasks = msg.tasks
+		m.applyFilter()
+		return m, nil
+	case savedMsg:
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.err = nil
+		m.status = fmt.Sprintf("%s #%d", msg.verb, msg.task.ID)
+		return m, m.load()
+	case tea.KeyMsg:
+		if msg.Type == tea.KeyCtrlC {
+			return m, tea.Quit
+		}
+		switch m.mode {
+		case modeAdd, modeEdit:
+			return m.updateForm(msg)
+		case modeFilter:
+			return m.updateFilter(msg)
+		default:
+			return m.updateList(msg)
+		}
+	}
+	return m, nil
+}
+
+func (m Model) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "esc":
+		return m, tea.Quit
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.visible)-1 {
+			m.cursor++
+		}
+	case "home", "g":
+		m.cursor = 0
+	case "end", "G":
+		m.cursor = max(len(m.visible)-1, 0)
+	case "r":
+		m.status = "refreshing"
+		return m, m.load()
+	case "a":
+		m.mode = modeAdd
+		m.editID = 0
+		return m, m.openForm("", "")
+	case "e", "enter":
+		if t, ok := m.selected(); ok {
+			m.mode = modeEdit
+			m.editID = t.ID
+			return m, m.openForm(t.Title, t.Description)
+		}
+	case "c", " ":
+		if t, ok := m.selected(); ok && !t.Completed {
+			return m, m.complete(t.ID)
+		}
+	case "/":
+		m.mode = modeFilter
+		return m, m.filter.Focus()
+	}
+	return m, nil
+}
+
+func (m Model) updateForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.mode = modeList
+		return m, nil
+	case tea.KeyTab, tea.KeyShiftTab, tea.KeyUp, tea.KeyDown:
+		m.fields[m.focus].Blur()
+		m.focus = 1 - m.focus
+		return m, m.fields[m.focus].Focus()
+	case tea.KeyEnter:
+		t := model.Task{
+			ID:          m.editID,
+			Title:       strings.TrimSpace(m.fields[0].Value()),
+			Description: strings.TrimSpace(m.fields[1].Value()),
+		}
+		if t.Title == "" {
+			m.err = fmt.Errorf("title is required")
+			return m, nil
+		}
+		cmd := m.save(m.mode, t)
+		m.mode = modeList
+		return m, cmd
+	}
+	var cmd tea.Cmd
+	m.fields[m.focus], cmd = m.fields[m.focus].Update(msg)
+	return m, cmd
+}
+
+func (m Model) updateFilter(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.filter.SetValue("")
+		fallthrough
+	case tea.KeyEnter:
+		m.filter.Blur()
+		m.mode = modeList
+		m.applyFilter()
+		return m, nil
+	}
+	var cmd tea.Cmd
+	m.filter, cmd = m.filter.Update(msg)
+	m.applyFilter()
+	return m, cmd
+}
+
+// openForm resets the form fields to the given values and focuses the title.
+func (m *Model) openForm(title, desc string) tea.Cmd {
+	m.err = nil
+	m.fields[0].SetValue(title)
+	m.fields[1].SetValue(desc)
+	m.fields[1].Blur()
+	m.focus = 0
+	return m.fields[0].Focus()
+}
+
+// applyFilter recomputes the visible rows and keeps the cursor in range.
+func (m *Model) applyFilter() {
+	q := strings.ToLower(m.filter.Value())
+	// a fresh slice: earlier copies of the model may still share the old one
+	m.visible = make([]int, 0, len(m.tasks))
+	for i, t := range m.tasks {
+		if q == "" ||
+			strings.Contains(strings.ToLower(t.Title), q) ||
+			strings.Contains(strings.ToLower(t.Description), q) {
+			m.visible = append(m.visible, i)
+		}
+	}
+	m.cursor = min(m.cursor, max(len(m.visible)-1, 0))
+}
+
+func (m Model) selected() (model.Task, bool) {
+	if len(m.visible) == 0 {
+		return model.Task{}, false
+	}
+	return m.tasks[m.visible[m.cursor]], true
+}
+
+func (m Model) context() (context.Context, context.CancelFunc) {
+	if m.opts.Timeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), m.opts.Timeout)
+}
+
+func (m Model) load() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := m.context()
+		defer cancel()
+		var tasks []model.Task
+		for t, err := range m.backend.ListTasks(ctx) {
+			if err != nil {
+				return tasksMsg{err: err}
+			}
+			tasks = append(tasks, t)
+		}
+		return tasksMsg{tasks: tasks}
+	}
+}
+
+func (m Model) save(md mode, t model.Task) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := m.context()
+		defer cancel()
+		if md == modeAdd {
+			created, err := m.backend.AddTask(ctx, t)
+			return savedMsg{verb: "added", task: created, err: err}
+		}
+		updated, err := m.backend.UpdateTask(ctx, t)
+		return savedMsg{verb: "updated", task: updated, err: err}
+	}
+}
+
+func (m Model) complete(id int64) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := m.context()
+		defer cancel()
+		t, err := m.backend.CompleteTask(ctx, id)
+		return savedMsg{verb: "completed", task: t, err: err}
+	}
+}
+
+func (m Model) tick() tea.Cmd {
+	if m.opts.RefreshInterval <= 0 {
+		return nil
+	}
+	return tea.Tick(m.opts.RefreshInterval, func(time.Time) tea.Msg { return tickMsg{} })
+}
+
+// View renders the list, the active form or filter, and a help line.
+func (m Model) View() string {
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf("Tasks (%d/%d)", len(m.visible), len(m.tasks))))
+	b.WriteString("\n\n")
+
+	start, rows := m.window()
+	for i, idx := range rows {
+		t := m.tasks[idx]
+		box := "[ ]"
+		if t.Completed {
+			box = "[x]"
+		}
+		line := fmt.Sprintf("%s #%d %s", box, t.ID, t.Title)
+		if t.Description != "" {
+			line += " — " + t.Description
+		}
+		switch {
+		case start+i == m.cursor && m.mode == modeList:
+			line = cursorStyle.Render(line)
+		case t.Completed:
+			line = doneStyle.Render(line)
+		}
+		b.WriteString(line + "\n")
+	}
+	if len(m.visible) == 0 {
+		b.WriteString(helpStyle.Render("no tasks") + "\n")
+	}
+	b.WriteString("\n")
+
+	switch m.mode {
+	case modeAdd, modeEdit:
+		heading := "New task"
+		if m.mode == modeEdit {
+			heading = fmt.Sprintf("Edit #%d", m.editID)
+		}
+		b.WriteString(titleStyle.Render(heading) + "\n")
+		b.WriteString(m.fields[0].View() + "\n")
+		b.WriteString(m.fields[1].View() + "\n")
+		b.WriteString(helpStyle.Render("enter save • tab switch field • esc cancel") + "\n")
+	case modeFilter:
+		b.WriteString(m.filter.View() + "\n")
+		b.WriteString(helpStyle.Render("enter apply • esc clear") + "\n")
+	default:
+		if f := m.filter.Value(); f != "" {
+			b.WriteString(helpStyle.Render("filter: "+f) + "\n")
+		}
+		b.WriteString(helpStyle.Render("↑/↓ move • a add • e edit • c complete • / filter • r refresh • q quit") + "\n")
+	}
+
+	if m.err != nil {
+		b.WriteString(errStyle.Render("error: "+m.err.Error()) + "\n")
+	} else if m.status != "" {
+		b.WriteString(helpStyle.Render(m.status) + "\n")
+	}
+	return b.String()
+}
+
+// window returns the visible rows that fit on screen, scrolled so the cursor
+// stays in view, along with the offset of the first one.
+func (m Model) window() (int, []int) {
+	rows := m.height - 8 // heading, help and form lines
+	if m.height == 0 || rows <= 0 || len(m.visible) <= rows {
+		return 0, m.visible
+	}
+	start := max(m.cursor-rows+1, 0)
+	return start, m.visible[start : start+rows]
+}
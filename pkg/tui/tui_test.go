@@ -0,0 +1,182 @@
+// pkg/tui/tui_test.go
+package tui_test
+
+import (
+	"context"
+	"errors"
+	"iter"
+
+	tea "github.com/charmbracelet/bubbletea"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"hearx/pkg/model"
+	"hearx/pkg/tui"
+)
+
+// fakeBackend keeps tasks in memory in place of a TodoService.
+type fakeBackend struct {
+	tasks   []model.Task
+	listErr error
+}
+
+func (f *fakeBackend) AddTask(_ context.Context, t model.Task) (model.Task, error) {
+	t.ID = int64(len(f.tasks) + 1)
+	f.tasks = append(f.tasks, t)
+	return t, nil
+}
+
+func (f *fakeBackend) UpdateTask(_ context.Context, t model.Task) (model.Task, error) {
+	for i := range f.tasks {
+		if f.tasks[i].ID == t.ID {
+			f.tasks[i].Title, f.tasks[i].Description = t.Title, t.Description
+			return f.tasks[i], nil
+		}
+	}
+	return model.Task{}, errors.New("not found")
+}
+
+func (f *fakeBackend) CompleteTask(_ context.Context, id int64) (model.Task, error) {
+	for i := range f.tasks {
+		if f.tasks[i].ID == id {
+			f.tasks[i].Completed = true
+			return f.tasks[i], nil
+		}
+	}
+	return model.Task{}, errors.New("not found")
+}
+
+func (f *fakeBackend) ListTasks(context.Context) iter.Seq2[model.Task, error] {
+	return func(yield func(model.Task, error) bool) {
+		if f.listErr != nil {
+			yield(model.Task{}, f.listErr)
+			return
+		}
+		for _, t := range f.tasks {
+			if !yield(t, nil) {
+				return
+			}
+		}
+	}
+}
+
+// send delivers msg and runs every command it produces to completion, the
+// way the bubbletea runtime would, minus the terminal.
+func send(m tea.Model, msg tea.Msg) tea.Model {
+	m, cmd := m.Update(msg)
+	return run(m, cmd)
+}
+
+func run(m tea.Model, cmd tea.Cmd) tea.Model {
+	if cmd == nil {
+		return m
+	}
+	switch msg := cmd().(type) {
+	case nil:
+		return m
+	case tea.BatchMsg:
+		for _, c := range msg {
+			m = run(m, c)
+		}
+		return m
+	default:
+		return send(m, msg)
+	}
+}
+
+func typeText(m tea.Model, s string) tea.Model {
+	return send(m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)})
+}
+
+func key(t tea.KeyType) tea.KeyMsg { return tea.KeyMsg{Type: t} }
+
+func runes(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+var _ = Describe("Model", func() {
+	var (
+		backend *fakeBackend
+		m       tea.Model
+	)
+
+	BeforeEach(func() {
+		backend = &fakeBackend{tasks: []model.Task{
+			{ID: 1, Title: "buy milk"},
+			{ID: 2, Title: "write report", Description: "quarterly"},
+		}}
+		model := tui.New(backend, tui.Options{})
+		m = run(model, model.Init())
+	})
+
+	It("should load the list on start", func() {
+		Expect(m.View()).To(ContainSubstring("#1 buy milk"))
+		Expect(m.View()).To(ContainSubstring("#2 write report — quarterly"))
+	})
+
+	It("should add a task", func() {
+		m = send(m, runes("a"))
+		m = typeText(m, "call mom")
+		m = send(m, key(tea.KeyTab))
+		m = typeText(m, "sunday")
+		m = send(m, key(tea.KeyEnter))
+
+		Expect(backend.tasks).To(HaveLen(3))
+		Expect(backend.tasks[2]).To(Equal(model.Task{ID: 3, Title: "call mom", Description: "sunday"}))
+		Expect(m.View()).To(ContainSubstring("#3 call mom"))
+		Expect(m.View()).To(ContainSubstring("added #3"))
+	})
+
+	It("should edit the selected task", func() {
+		m = send(m, runes("j"))
+		m = send(m, runes("e"))
+		m = send(m, key(tea.KeyBackspace))
+		m = typeText(m, "S")
+		m = send(m, key(tea.KeyEnter))
+
+		Expect(backend.tasks[1].Title).To(Equal("write reporS"))
+		Expect(backend.tasks[1].Description).To(Equal("quarterly"))
+	})
+
+	It("should refuse to save an empty title", func() {
+		m = send(m, runes("a"))
+		m = send(m, key(tea.KeyEnter))
+
+		Expect(backend.tasks).To(HaveLen(2))
+		Expect(m.View()).To(ContainSubstring("title is required"))
+	})
+
+	It("should complete the selected task", func() {
+		m = send(m, runes("c"))
+
+		Expect(backend.tasks[0].Completed).To(BeTrue())
+		Expect(m.View()).To(ContainSubstring("[x] #1 buy milk"))
+	})
+
+	It("should filter by title and description", func() {
+		m = send(m, runes("/"))
+		m = typeText(m, "QUARTER")
+		m = send(m, key(tea.KeyEnter))
+
+		Expect(m.View()).To(ContainSubstring("Tasks (1/2)"))
+		Expect(m.View()).NotTo(ContainSubstring("buy milk"))
+
+		// actions apply to the filtered selection
+		m = send(m, runes("c"))
+		Expect(backend.tasks[1].Completed).To(BeTrue())
+		Expect(backend.tasks[0].Completed).To(BeFalse())
+	})
+
+	It("should pick up changes made elsewhere on refresh", func() {
+		backend.tasks = append(backend.tasks, model.Task{ID: 9, Title: "from another client"})
+		m = send(m, runes("r"))
+
+		Expect(m.View()).To(ContainSubstring("#9 from another client"))
+	})
+
+	It("should show backend errors and keep the last list", func() {
+		backend.listErr = errors.New("unavailable")
+		m = send(m, runes("r"))
+
+		Expect(m.View()).To(ContainSubstring("error: unavailable"))
+		Expect(m.View()).To(ContainSubstring("buy milk"))
+	})
+})
@@ -0,0 +1,4 @@
+-- 02_add_task_owner.sql
+ALTER TABLE tasks
+  ADD COLUMN owner VARCHAR(64) NOT NULL DEFAULT '' AFTER completed,  -- caller that created the task
+  ADD INDEX idx_tasks_owner_completed (owner, completed);            -- open-task quota lookups
@@ -0,0 +1,5 @@
+-- 03_add_task_parent.sql
+ALTER TABLE tasks
+  ADD COLUMN parent_id BIGINT UNSIGNED NULL DEFAULT NULL AFTER owner,  -- NULL for top-level tasks
+  ADD INDEX idx_tasks_parent (parent_id),                              -- children lookups
+  ADD CONSTRAINT fk_tasks_parent FOREIGN KEY (parent_id) REFERENCES tasks (id);
@@ -0,0 +1,11 @@
+-- 04_create_task_dependencies.sql
+CREATE TABLE IF NOT EXISTS task_dependencies (
+  task_id     BIGINT UNSIGNED NOT NULL,                  -- the blocked task
+  blocker_id  BIGINT UNSIGNED NOT NULL,                  -- must be completed first
+  created_at  TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  PRIMARY KEY (task_id, blocker_id),
+  INDEX idx_task_dependencies_blocker (blocker_id),      -- reverse lookups
+  CONSTRAINT fk_task_dependencies_task FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE,
+  CONSTRAINT fk_task_dependencies_blocker FOREIGN KEY (blocker_id) REFERENCES tasks (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,5 @@
+-- 05_add_task_recurrence.sql
+ALTER TABLE tasks
+  ADD COLUMN due_at     TIMESTAMP    NULL DEFAULT NULL AFTER parent_id,  -- NULL when there is no due date
+  ADD COLUMN recurrence VARCHAR(255) NOT NULL DEFAULT '' AFTER due_at,   -- RRULE value, empty for one-off tasks
+  ADD INDEX idx_tasks_due (due_at);
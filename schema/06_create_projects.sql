@@ -0,0 +1,19 @@
+-- 06_create_projects.sql
+CREATE TABLE IF NOT EXISTS projects (
+  id           BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+  name         VARCHAR(255) NOT NULL,
+  description  TEXT        NULL,
+  archived     BOOLEAN     NOT NULL DEFAULT FALSE,
+  owner        VARCHAR(64) NOT NULL DEFAULT '',           -- caller that created the project
+
+  created_at   TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at   TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP
+                            ON UPDATE CURRENT_TIMESTAMP,
+
+  UNIQUE INDEX idx_projects_name (name)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE tasks
+  ADD COLUMN project_id BIGINT UNSIGNED NULL DEFAULT NULL AFTER parent_id,  -- NULL when in no project
+  ADD INDEX idx_tasks_project (project_id, completed),                      -- filters and statistics
+  ADD CONSTRAINT fk_tasks_project FOREIGN KEY (project_id) REFERENCES projects (id) ON DELETE SET NULL;
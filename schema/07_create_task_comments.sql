@@ -0,0 +1,12 @@
+-- 07_create_task_comments.sql
+CREATE TABLE IF NOT EXISTS task_comments (
+  id          BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+  task_id     BIGINT UNSIGNED NOT NULL,
+  author      VARCHAR(64)     NOT NULL DEFAULT '',          -- authenticated caller that wrote it
+  body        TEXT            NOT NULL,
+
+  created_at  TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  INDEX idx_task_comments_task (task_id, id),               -- a task's comments, in pages
+  CONSTRAINT fk_task_comments_task FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,10 @@
+-- 08_create_users.sql
+CREATE TABLE IF NOT EXISTS users (
+  name        VARCHAR(64) NOT NULL PRIMARY KEY,              -- caller name from AUTH_TOKENS, or listed in USERS
+  created_at  TIMESTAMP   NOT NULL DEFAULT CURRENT_TIMESTAMP
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE tasks
+  ADD COLUMN assignee VARCHAR(64) NULL DEFAULT NULL AFTER owner,   -- NULL when nobody is responsible
+  ADD INDEX idx_tasks_assignee (assignee, completed),              -- "my tasks"
+  ADD CONSTRAINT fk_tasks_assignee FOREIGN KEY (assignee) REFERENCES users (name) ON DELETE SET NULL;
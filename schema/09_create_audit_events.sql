@@ -0,0 +1,19 @@
+-- 09_create_audit_events.sql
+-- Append-only: the server only ever inserts. task_id has no foreign key, so
+-- the trail outlives the tasks it describes.
+CREATE TABLE IF NOT EXISTS audit_events (
+  id           BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+  actor        VARCHAR(64)     NOT NULL DEFAULT '',          -- authenticated caller that made the change
+  action       VARCHAR(32)     NOT NULL,                     -- add, update, complete, move, ...
+  task_id      BIGINT UNSIGNED NOT NULL,
+  request_id   VARCHAR(64)     NOT NULL DEFAULT '',
+  detail       VARCHAR(255)    NOT NULL DEFAULT '',          -- e.g. the blocker of a dependency
+  before_state JSON            NULL,                         -- the task before the change; NULL when added
+  after_state  JSON            NULL,                         -- the task after the change
+
+  occurred_at  TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  INDEX idx_audit_events_task (task_id, id),
+  INDEX idx_audit_events_actor (actor, id),
+  INDEX idx_audit_events_occurred (occurred_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
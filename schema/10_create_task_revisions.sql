@@ -0,0 +1,34 @@
+-- 10_create_task_revisions.sql
+-- Every write to a task adds a revision holding the task as the write left
+-- it, in the same transaction. Revisions go when the task's row does.
+CREATE TABLE IF NOT EXISTS task_revisions (
+  task_id     BIGINT UNSIGNED NOT NULL,
+  revision    INT UNSIGNED    NOT NULL,                     -- 1 for the task as created
+  state       JSON            NOT NULL,                     -- the task's fields, as in the API
+  deleted     BOOLEAN         NOT NULL DEFAULT FALSE,       -- the write soft-deleted the task
+
+  created_at  TIMESTAMP       NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+  PRIMARY KEY (task_id, revision),
+  CONSTRAINT fk_task_revisions_task FOREIGN KEY (task_id) REFERENCES tasks (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+-- tasks that existed before revisions start their history as they are now
+INSERT IGNORE INTO task_revisions (task_id, revision, state, deleted, created_at)
+SELECT id, 1,
+       JSON_OBJECT(
+         'id', id,
+         'title', title,
+         'description', COALESCE(description, ''),
+         'completed', IF(completed, CAST('true' AS JSON), CAST('false' AS JSON)),
+         'owner', owner,
+         'assignee', COALESCE(assignee, ''),
+         'parent_id', COALESCE(parent_id, 0),
+         'project_id', COALESCE(project_id, 0),
+         'due_at', DATE_FORMAT(due_at, '%Y-%m-%dT%H:%i:%sZ'),
+         'recurrence', recurrence,
+         'created_at', DATE_FORMAT(created_at, '%Y-%m-%dT%H:%i:%sZ'),
+         'updated_at', DATE_FORMAT(updated_at, '%Y-%m-%dT%H:%i:%sZ')
+       ),
+       deleted_at IS NOT NULL, updated_at
+FROM tasks;
@@ -0,0 +1,3 @@
+-- 11_index_tasks_deleted_at.sql
+ALTER TABLE tasks
+  ADD INDEX idx_tasks_deleted_at (deleted_at);  -- trash purge by deletion time
@@ -0,0 +1,4 @@
+-- 12_add_task_archived_at.sql
+ALTER TABLE tasks
+  ADD COLUMN archived_at TIMESTAMP NULL DEFAULT NULL AFTER deleted_at,    -- set while a completed task is archived
+  ADD INDEX idx_tasks_archive (archived_at, completed, updated_at);     -- archival job lookups